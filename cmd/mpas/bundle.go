@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/open-component-model/mpas/cmd/mpas/config"
+	"github.com/open-component-model/mpas/internal/bootstrap"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/spf13/cobra"
+)
+
+// NewBundle returns a new cobra.Command to create an air-gap bundle.
+func NewBundle(cfg *config.MpasConfig) *cobra.Command {
+	var (
+		layoutDir  string
+		repository string
+		output     string
+	)
+	cmd := &cobra.Command{
+		Use:   "bundle [flags]",
+		Short: "Create an air-gap bundle to bootstrap air-gapped clusters from.",
+		Long: `Create an air-gap bundle from an OCI image layout. Each manifest of the layout's index.json
+must be annotated with the reference it is served under, e.g. open-component-model/mpas/component-descriptors/ocm.software/mpas/bootstrap:v0.1.0.
+The bundle is used by setting airGapBundle in the bootstrap config file.`,
+		Example: `  - Create an air-gap bundle from the OCI image layout in ./layout
+    mpas bundle --layout ./layout --repository open-component-model/mpas --output mpas-bundle.tar.gz
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if layoutDir == "" || repository == "" {
+				return fmt.Errorf("--layout and --repository must be set, see mpas bundle --help for more information")
+			}
+
+			if err := bootstrap.CreateAirGapBundle(layoutDir, bootstrap.AirGapManifest{Repository: repository}, output); err != nil {
+				return err
+			}
+
+			cfg.Printer.Printf("Created air-gap bundle %s\n", printer.BoldBlue(output))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&layoutDir, "layout", "", "The directory of the OCI image layout to bundle.")
+	cmd.Flags().StringVar(&repository, "repository", "", "The path of the OCM repository in the bundle, e.g. open-component-model/mpas.")
+	cmd.Flags().StringVar(&output, "output", "mpas-bundle.tar.gz", "The path of the bundle to create.")
+
+	return cmd
+}
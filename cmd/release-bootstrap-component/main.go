@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/open-component-model/ocm/pkg/common/accessio"
 	"github.com/open-component-model/ocm/pkg/contexts/datacontext"
@@ -49,6 +50,10 @@ var (
 	targetOS string
 	// The target arch.
 	targetArch string
+	// Add Prometheus operator ServiceMonitors to the controller components.
+	serviceMonitors bool
+	// The labels to add to the ServiceMonitors, e.g. release=prometheus.
+	serviceMonitorLabels string
 )
 
 func main() {
@@ -65,6 +70,8 @@ func main() {
 	flag.StringVar(&username, "username", "", "The username to use.")
 	flag.StringVar(&targetOS, "target-os", "linux", "The target OS to use.")
 	flag.StringVar(&targetArch, "target-arch", "amd64", "The target arch to use.")
+	flag.BoolVar(&serviceMonitors, "service-monitors", false, "Add Prometheus operator ServiceMonitors to the controller components.")
+	flag.StringVar(&serviceMonitorLabels, "service-monitor-labels", "", "Comma separated key=value labels to add to the ServiceMonitors.")
 
 	flag.Parse()
 
@@ -137,6 +144,14 @@ func releaseComponents(ctx context.Context, octx om.Context, token, tmpDir, ctfP
 	defer ctf.Close()
 
 	r := release.New(octx, username, token, tmpDir, repositoryURL, ctf)
+	if serviceMonitors {
+		labels, err := parseLabels(serviceMonitorLabels)
+		if err != nil {
+			fmt.Println("Failed to parse service monitor labels: ", err)
+			os.Exit(1)
+		}
+		r.WithServiceMonitors(labels)
+	}
 
 	generatedComponents := make(map[string]*ocm.Component)
 	for _, comp := range env.BootstrapComponents {
@@ -225,3 +240,21 @@ func releaseComponents(ctx context.Context, octx om.Context, token, tmpDir, ctfP
 	}
 	return nil
 }
+
+// parseLabels parses comma separated key=value pairs.
+func parseLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+	if s == "" {
+		return labels, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q, must be of format key=value", pair)
+		}
+		labels[key] = value
+	}
+
+	return labels, nil
+}
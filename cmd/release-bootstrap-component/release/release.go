@@ -11,8 +11,10 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	ocmmetav1 "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/meta/v1"
 
 	cgen "github.com/open-component-model/mpas/internal/componentsgen"
 	"github.com/open-component-model/mpas/internal/env"
@@ -62,6 +64,9 @@ type Releaser struct {
 	tmpDir        string
 	repositoryURL string
 	ctf           om.Repository
+	// serviceMonitors adds a ServiceMonitor resource to the controller components if set
+	serviceMonitors      bool
+	serviceMonitorLabels map[string]string
 }
 
 // New creates a new Releaser.
@@ -80,6 +85,13 @@ func New(
 	}
 }
 
+// WithServiceMonitors adds a Prometheus operator ServiceMonitor for the metrics of the controllers
+// as an optional resource to the controller components. The labels are added to the ServiceMonitors.
+func (r *Releaser) WithServiceMonitors(labels map[string]string) {
+	r.serviceMonitors = true
+	r.serviceMonitorLabels = labels
+}
+
 // ReleaseBootstrapComponent releases the bootstrap component.
 func (r *Releaser) ReleaseBootstrapComponent(
 	components map[string]*ocm.Component,
@@ -287,10 +299,16 @@ func (r *Releaser) ReleaseFluxComponent(
 		return nil, fmt.Errorf("failed to generate flux manifests: %v", err)
 	}
 
+	labels, err := fluxComponentLabels(fluxVersion, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
 	component, err := ocm.NewComponent(r.octx,
 		fmt.Sprintf("%s/%s", env.ComponentNamePrefix, env.FluxName),
 		fluxVersion,
 		ocm.WithProvider("fluxcd"),
+		ocm.WithLabels(labels),
 		ocm.WithUsername(r.username),
 		ocm.WithToken(r.token),
 		ocm.WithRepositoryURL(r.repositoryURL))
@@ -305,6 +323,22 @@ func (r *Releaser) ReleaseFluxComponent(
 	return component, nil
 }
 
+// fluxComponentLabels returns the metadata labels of the flux component.
+func fluxComponentLabels(fluxVersion string, timestamp time.Time) (ocmmetav1.Labels, error) {
+	var labels ocmmetav1.Labels
+	for name, value := range map[string]string{
+		"flux-version":        fluxVersion,
+		"cluster-type":        "kubernetes",
+		"bootstrap-timestamp": timestamp.Format(time.RFC3339),
+	} {
+		if err := labels.Set(name, value); err != nil {
+			return nil, fmt.Errorf("failed to set label %s: %w", name, err)
+		}
+	}
+
+	return labels, nil
+}
+
 // ReleaseFluxCliComponent releases flux-cli.
 func (r *Releaser) ReleaseFluxCliComponent(
 	ctx context.Context,
@@ -501,6 +535,12 @@ func (r *Releaser) release(
 		return fmt.Errorf("failed to add file resource %s: %w", name, err)
 	}
 
+	if ctrl, ok := gen.(*cgen.Controller); ok && r.serviceMonitors {
+		if err := r.addServiceMonitor(component, ctrl); err != nil {
+			return err
+		}
+	}
+
 	if err := component.AddResource(ocm.WithResourceName("ocm-config"),
 		ocm.WithResourcePath(path.Join(r.tmpDir, "config.yaml")),
 		ocm.WithResourceType("file"),
@@ -520,6 +560,34 @@ func (r *Releaser) release(
 	return nil
 }
 
+// addServiceMonitor adds the ServiceMonitor of the controller as a file resource named <controller>-service-monitor.
+func (r *Releaser) addServiceMonitor(component *ocm.Component, ctrl *cgen.Controller) error {
+	namespace := env.DefaultOCMNamespace
+	if ctrl.Name == env.MpasProductControllerName || ctrl.Name == env.MpasProjectControllerName {
+		namespace = env.DefaultMPASNamespace
+	}
+
+	content, err := ctrl.GenerateServiceMonitor(namespace, r.serviceMonitorLabels)
+	if err != nil {
+		return fmt.Errorf("failed to generate service monitor: %w", err)
+	}
+
+	serviceMonitorPath := path.Join(r.tmpDir, ctrl.Name, "service-monitor.yaml")
+	if err := os.WriteFile(serviceMonitorPath, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write service-monitor.yaml: %w", err)
+	}
+
+	name := ctrl.Name + "-service-monitor"
+	if err := component.AddResource(ocm.WithResourceName(name),
+		ocm.WithResourcePath(serviceMonitorPath),
+		ocm.WithResourceType("file"),
+		ocm.WithResourceVersion(component.Version)); err != nil {
+		return fmt.Errorf("failed to add file resource %s: %w", name, err)
+	}
+
+	return nil
+}
+
 func getBinary(ctx context.Context, version, tmpDir, binURL, hashURL string) (cgen.Binary, error) {
 	b := cgen.Binary{
 		Version: version,
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package release
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/open-component-model/ocm/pkg/common/accessio"
+	"github.com/open-component-model/ocm/pkg/contexts/datacontext"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-component-model/mpas/internal/ocm"
+)
+
+func TestFluxComponentLabels(t *testing.T) {
+	timestamp := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	labels, err := fluxComponentLabels("v2.0.0", timestamp)
+	require.NoError(t, err)
+
+	octx := om.New(datacontext.MODE_SHARED)
+	component, err := ocm.NewComponent(octx, "ocm.software/mpas/flux", "v2.0.0",
+		ocm.WithProvider("fluxcd"),
+		ocm.WithLabels(labels))
+	require.NoError(t, err)
+
+	ctf, err := ocm.CreateCTF(octx, filepath.Join(t.TempDir(), "ctf"), accessio.FormatDirectory)
+	require.NoError(t, err)
+	defer ctf.Close()
+
+	require.NoError(t, component.AddToCTF(ctf))
+	require.NoError(t, component.Close())
+
+	cv, err := ctf.LookupComponentVersion("ocm.software/mpas/flux", "v2.0.0")
+	require.NoError(t, err)
+	defer cv.Close()
+
+	desc := cv.GetDescriptor()
+	require.Len(t, desc.Labels, 3)
+	for name, expected := range map[string]string{
+		"flux-version":        "v2.0.0",
+		"cluster-type":        "kubernetes",
+		"bootstrap-timestamp": "2023-01-01T00:00:00Z",
+	} {
+		var value string
+		ok, err := desc.Labels.GetValue(name, &value)
+		require.NoError(t, err)
+		require.True(t, ok, name)
+		assert.Equal(t, expected, value)
+	}
+}
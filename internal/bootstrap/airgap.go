@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/open-component-model/mpas/internal/fs"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// airGapManifestFileName is the name of the manifest describing the content of an air-gap bundle.
+const airGapManifestFileName = "bootstrap-manifest.yaml"
+
+// AirGapManifest describes the content of an air-gap bundle.
+type AirGapManifest struct {
+	// Repository is the path of the OCM repository in the bundle registry, e.g. open-component-model/mpas.
+	Repository string `json:"repository"`
+}
+
+// WithAirGapBundle bootstraps from the air-gap bundle at path instead of a remote registry.
+// The bundle is a .tar.gz file containing an OCI image layout and a bootstrap-manifest.yaml.
+// Each manifest of the layout's index.json is served under its org.opencontainers.image.ref.name
+// annotation, e.g. open-component-model/mpas/component-descriptors/ocm.software/mpas/bootstrap:v0.1.0,
+// by an in-process registry which is used as the registry of the bootstrap.
+func WithAirGapBundle(path string) Option {
+	return func(o *options) {
+		o.airGapBundle = path
+	}
+}
+
+// CreateAirGapBundle writes the OCI image layout at layoutDir and the manifest as an air-gap bundle
+// to output. The manifest is written to layoutDir as bootstrap-manifest.yaml.
+func CreateAirGapBundle(layoutDir string, manifest AirGapManifest, output string) error {
+	if manifest.Repository == "" {
+		return fmt.Errorf("air-gap bundle repository must be set")
+	}
+
+	if _, err := openLayout(layoutDir); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal air-gap manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(layoutDir, airGapManifestFileName), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write air-gap manifest: %w", err)
+	}
+
+	archive, err := fs.CreateArchive(layoutDir, filepath.Base(output))
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(archive, output); err != nil {
+		return fmt.Errorf("failed to move air-gap bundle to %s: %w", output, err)
+	}
+
+	return nil
+}
+
+// mountAirGapBundle unpacks the air-gap bundle, serves its OCI image layout from a registry
+// listening on localhost and uses it as the registry of the bootstrap.
+// The returned function stops the registry and removes the unpacked bundle.
+func (b *Bootstrap) mountAirGapBundle(ctx context.Context) (func(), error) {
+	dir, err := os.MkdirTemp("", "mpas-airgap-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create directory for air-gap bundle: %w", err)
+	}
+
+	cleanup := func() { os.RemoveAll(dir) }
+	manifest, err := unpackAirGapBundle(b.airGapBundle, dir)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("failed to listen for air-gap registry: %w", err)
+	}
+
+	server := &http.Server{Handler: registry.New(registry.Logger(log.New(io.Discard, "", 0)))}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			b.log().ErrorContext(ctx, "air-gap registry stopped", slog.String("error", err.Error()))
+		}
+	}()
+	cleanup = func() {
+		server.Close()
+		os.RemoveAll(dir)
+	}
+
+	host := listener.Addr().String()
+	if err := pushLayout(ctx, dir, host); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	b.registry = fmt.Sprintf("http://%s/%s", host, manifest.Repository)
+	b.fallbackRegistries = nil
+	b.log().InfoContext(ctx, fmt.Sprintf("Serving air-gap bundle %s from %s", b.airGapBundle, b.registry),
+		slog.String("bundle", b.airGapBundle),
+		slog.String("registry", b.registry))
+
+	return cleanup, nil
+}
+
+// unpackAirGapBundle extracts the bundle at path into dir and returns its manifest.
+func unpackAirGapBundle(path, dir string) (*AirGapManifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open air-gap bundle: %w", err)
+	}
+	defer f.Close()
+
+	if err := fs.ExtractArchive(f, dir); err != nil {
+		return nil, fmt.Errorf("failed to unpack air-gap bundle %s: %w", path, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, airGapManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("air-gap bundle %s has no %s: %w", path, airGapManifestFileName, err)
+	}
+
+	manifest := &AirGapManifest{}
+	if err := yaml.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", airGapManifestFileName, err)
+	}
+	if manifest.Repository == "" {
+		return nil, fmt.Errorf("%s of air-gap bundle %s has no repository", airGapManifestFileName, path)
+	}
+
+	return manifest, nil
+}
+
+// openLayout verifies that dir is an OCI image layout.
+func openLayout(dir string) (layout.Path, error) {
+	p, err := layout.FromPath(dir)
+	if err != nil {
+		return "", fmt.Errorf("%s is not an OCI image layout: %w", dir, err)
+	}
+
+	return p, nil
+}
+
+// pushLayout pushes each manifest of the OCI image layout at dir to the registry at host
+// under the reference of its ref name annotation.
+func pushLayout(ctx context.Context, dir, host string) error {
+	p, err := openLayout(dir)
+	if err != nil {
+		return err
+	}
+
+	index, err := p.ImageIndex()
+	if err != nil {
+		return fmt.Errorf("failed to read index of OCI image layout: %w", err)
+	}
+	im, err := index.IndexManifest()
+	if err != nil {
+		return fmt.Errorf("failed to read index of OCI image layout: %w", err)
+	}
+
+	for _, desc := range im.Manifests {
+		refName := desc.Annotations[ocispec.AnnotationRefName]
+		if refName == "" {
+			return fmt.Errorf("manifest %s of OCI image layout has no %s annotation", desc.Digest, ocispec.AnnotationRefName)
+		}
+
+		ref, err := name.ParseReference(fmt.Sprintf("%s/%s", host, refName), name.Insecure)
+		if err != nil {
+			return fmt.Errorf("invalid reference %q: %w", refName, err)
+		}
+
+		switch {
+		case desc.MediaType.IsIndex():
+			ii, err := index.ImageIndex(desc.Digest)
+			if err != nil {
+				return fmt.Errorf("failed to read index %s: %w", refName, err)
+			}
+			if err := remote.WriteIndex(ref, ii, remote.WithContext(ctx)); err != nil {
+				return fmt.Errorf("failed to push %s: %w", refName, err)
+			}
+		case desc.MediaType.IsImage():
+			img, err := index.Image(desc.Digest)
+			if err != nil {
+				return fmt.Errorf("failed to read manifest %s: %w", refName, err)
+			}
+			if err := remote.Write(ref, img, remote.WithContext(ctx)); err != nil {
+				return fmt.Errorf("failed to push %s: %w", refName, err)
+			}
+		default:
+			return fmt.Errorf("unsupported media type %s of %s", desc.MediaType, refName)
+		}
+	}
+
+	return nil
+}
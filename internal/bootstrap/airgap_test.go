@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/open-component-model/mpas/internal/fs"
+	"github.com/open-component-model/mpas/internal/printer"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const airGapDescriptorRef = "open-component-model/mpas/component-descriptors/ocm.software/mpas/bootstrap:v0.1.0"
+
+// newAirGapLayout writes an OCI image layout with a random image annotated with refName
+// and returns its directory and the image.
+func newAirGapLayout(t *testing.T, refName string) (string, v1.Image) {
+	dir := t.TempDir()
+	p, err := layout.Write(dir, empty.Index)
+	require.NoError(t, err)
+
+	img, err := random.Image(1024, 1)
+	require.NoError(t, err)
+
+	var opts []layout.Option
+	if refName != "" {
+		opts = append(opts, layout.WithAnnotations(map[string]string{ocispec.AnnotationRefName: refName}))
+	}
+	require.NoError(t, p.AppendImage(img, opts...))
+
+	return dir, img
+}
+
+func TestMountAirGapBundle(t *testing.T) {
+	dir, img := newAirGapLayout(t, airGapDescriptorRef)
+	bundle := filepath.Join(t.TempDir(), "mpas-bundle.tar.gz")
+	require.NoError(t, CreateAirGapBundle(dir, AirGapManifest{Repository: "open-component-model/mpas"}, bundle))
+
+	p, err := printer.Newprinter(io.Discard)
+	require.NoError(t, err)
+	b := &Bootstrap{options: options{
+		airGapBundle:       bundle,
+		registry:           "ghcr.io/open-component-model/mpas",
+		fallbackRegistries: []string{"registry.example.com/mpas"},
+		printer:            p,
+	}}
+
+	cleanup, err := b.mountAirGapBundle(context.Background())
+	require.NoError(t, err)
+	defer cleanup()
+
+	assert.True(t, strings.HasPrefix(b.registry, "http://127.0.0.1:"), b.registry)
+	assert.True(t, strings.HasSuffix(b.registry, "/open-component-model/mpas"), b.registry)
+	assert.Empty(t, b.fallbackRegistries)
+
+	// the bundle is served without any outbound connection
+	require.NoError(t, b.CheckRegistryConnectivity(context.Background()))
+
+	host := strings.TrimSuffix(strings.TrimPrefix(b.registry, "http://"), "/open-component-model/mpas")
+	ref, err := name.ParseReference(host+"/"+airGapDescriptorRef, name.Insecure)
+	require.NoError(t, err)
+	served, err := remote.Image(ref)
+	require.NoError(t, err)
+
+	expected, err := img.Digest()
+	require.NoError(t, err)
+	got, err := served.Digest()
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
+
+func TestMountAirGapBundleErrors(t *testing.T) {
+	t.Run("missing ref name annotation", func(t *testing.T) {
+		dir, _ := newAirGapLayout(t, "")
+		bundle := filepath.Join(t.TempDir(), "mpas-bundle.tar.gz")
+		require.NoError(t, CreateAirGapBundle(dir, AirGapManifest{Repository: "open-component-model/mpas"}, bundle))
+
+		b := &Bootstrap{options: options{airGapBundle: bundle}}
+		_, err := b.mountAirGapBundle(context.Background())
+		assert.ErrorContains(t, err, "has no org.opencontainers.image.ref.name annotation")
+	})
+
+	t.Run("missing manifest", func(t *testing.T) {
+		dir, _ := newAirGapLayout(t, airGapDescriptorRef)
+		archive, err := fs.CreateArchive(dir, "mpas-bundle-without-manifest.tar.gz")
+		require.NoError(t, err)
+		t.Cleanup(func() { os.Remove(archive) })
+
+		b := &Bootstrap{options: options{airGapBundle: archive}}
+		_, err = b.mountAirGapBundle(context.Background())
+		assert.ErrorContains(t, err, "has no bootstrap-manifest.yaml")
+	})
+
+	t.Run("not an OCI image layout", func(t *testing.T) {
+		err := CreateAirGapBundle(t.TempDir(), AirGapManifest{Repository: "open-component-model/mpas"}, filepath.Join(t.TempDir(), "b.tar.gz"))
+		assert.ErrorContains(t, err, "is not an OCI image layout")
+	})
+}
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	notificationv1 "github.com/fluxcd/notification-controller/api/v1"
+	notificationv1beta2 "github.com/fluxcd/notification-controller/api/v1beta2"
+	"github.com/fluxcd/pkg/apis/meta"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// alertsDir is the directory below the target path holding the flux Alerts.
+	alertsDir = "alerts"
+	// alertsFileName is the name of the file holding the flux Alerts and their Providers.
+	alertsFileName = "flux-alerts.yaml"
+	// alertAddressKey is the key of the provider address in the Provider secret.
+	alertAddressKey = "address"
+)
+
+// alertProviders are the supported types of FluxAlert providers.
+var alertProviders = []string{
+	notificationv1beta2.SlackProvider,
+	notificationv1beta2.PagerDutyProvider,
+	notificationv1beta2.GenericProvider,
+}
+
+// FluxAlert sends the reconciliation failures of the flux objects to the given provider.
+type FluxAlert struct {
+	// Provider is the type of the provider, one of slack, pagerduty or generic.
+	Provider string `json:"provider"`
+	// Address is the webhook URL of the provider, e.g. the Slack incoming webhook.
+	Address string `json:"address"`
+	// Channel is the channel the alerts are posted to, if supported by the provider.
+	Channel string `json:"channel,omitempty"`
+}
+
+// WithFluxAlerts adds flux notification Alerts sending the reconciliation failures to the given providers.
+func WithFluxAlerts(alerts []FluxAlert) Option {
+	return func(o *options) {
+		o.fluxAlerts = append(o.fluxAlerts, alerts...)
+	}
+}
+
+// withFluxAlerts adds the Alerts and their Providers to the sync manifests.
+func withFluxAlerts(alerts []FluxAlert) fluxOption {
+	return func(o *fluxOptions) {
+		o.alerts = append(o.alerts, alerts...)
+	}
+}
+
+// alertName returns the name of the Alert and Provider of the i-th FluxAlert.
+func alertName(i int, a FluxAlert) string {
+	return fmt.Sprintf("%s-%d", a.Provider, i)
+}
+
+// alertSecretName returns the name of the secret holding the address of the Provider.
+func alertSecretName(name string) string {
+	return name + "-address"
+}
+
+// reconcileAlerts creates the address secrets of the Providers in the cluster and commits the
+// Alerts and Providers to the management repository. The addresses are not committed.
+func (f *fluxInstall) reconcileAlerts(ctx context.Context) error {
+	if len(f.alerts) == 0 {
+		return nil
+	}
+
+	alerts, err := f.generateAlerts()
+	if err != nil {
+		return err
+	}
+
+	for i, a := range f.alerts {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: alertSecretName(alertName(i, a)), Namespace: f.namespace}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, f.kubeClient, secret, func() error {
+			setManagedByLabel(secret)
+			secret.Data = map[string][]byte{alertAddressKey: []byte(a.Address)}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to reconcile secret of alert %s: %w", alertName(i, a), err)
+		}
+	}
+
+	return f.commitAndPush(ctx, fmt.Sprintf("Add Flux %s alerts", f.version), map[string]io.Reader{
+		path.Join(f.targetPath, alertsDir, alertsFileName): bytes.NewReader(alerts),
+	})
+}
+
+// generateAlerts returns the Provider and Alert manifests. The Alerts report the errors
+// of the Kustomizations and GitRepositories in the flux namespace.
+func (f *fluxInstall) generateAlerts() ([]byte, error) {
+	var buf bytes.Buffer
+	for i, a := range f.alerts {
+		name := alertName(i, a)
+		provider := notificationv1beta2.Provider{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: notificationv1beta2.GroupVersion.String(),
+				Kind:       notificationv1beta2.ProviderKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: f.namespace,
+			},
+			Spec: notificationv1beta2.ProviderSpec{
+				Type:    a.Provider,
+				Channel: a.Channel,
+				SecretRef: &meta.LocalObjectReference{
+					Name: alertSecretName(name),
+				},
+			},
+		}
+
+		alert := notificationv1beta2.Alert{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: notificationv1beta2.GroupVersion.String(),
+				Kind:       notificationv1beta2.AlertKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: f.namespace,
+			},
+			Spec: notificationv1beta2.AlertSpec{
+				ProviderRef: meta.LocalObjectReference{
+					Name: name,
+				},
+				EventSeverity: "error",
+				EventSources: []notificationv1.CrossNamespaceObjectReference{
+					{Kind: kustomizev1.KustomizationKind, Name: "*"},
+					{Kind: sourcev1.GitRepositoryKind, Name: "*"},
+				},
+			},
+		}
+
+		for _, obj := range []any{provider, alert} {
+			data, err := yaml.Marshal(obj)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal alert %s: %w", name, err)
+			}
+			buf.WriteString("---\n")
+			buf.Write(data)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
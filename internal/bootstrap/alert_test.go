@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	notificationv1beta2 "github.com/fluxcd/notification-controller/api/v1beta2"
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/gogit"
+	"github.com/fluxcd/pkg/git/repository"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestReconcileAlerts(t *testing.T) {
+	bare := newBareRepository(t, 1)
+
+	gitClient, err := gogit.NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP}, gogit.WithDiskStorage())
+	require.NoError(t, err)
+	_, err = gitClient.Clone(context.Background(), bare, repository.CloneOptions{
+		CheckoutStrategy: repository.CheckoutStrategy{Branch: "main"},
+	})
+	require.NoError(t, err)
+
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	opts := &fluxOptions{
+		gitClient:  gitClient,
+		kubeClient: kubeClient,
+		branch:     "main",
+		targetPath: "clusters",
+		namespace:  "flux-system",
+	}
+	withFluxAlerts([]FluxAlert{{
+		Provider: notificationv1beta2.SlackProvider,
+		Address:  "https://hooks.slack.com/services/s3cr3t",
+		Channel:  "#flux",
+	}})(opts)
+	f := &fluxInstall{version: "v2.0.0", fluxOptions: opts}
+	require.NoError(t, f.reconcileAlerts(context.Background()))
+
+	data, err := os.ReadFile(filepath.Join(gitClient.Path(), "clusters", "alerts", "flux-alerts.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s3cr3t")
+
+	docs := strings.Split(strings.TrimPrefix(string(data), "---\n"), "---\n")
+	require.Len(t, docs, 2)
+
+	provider := &notificationv1beta2.Provider{}
+	require.NoError(t, yaml.UnmarshalStrict([]byte(docs[0]), provider))
+	assert.Equal(t, notificationv1beta2.GroupVersion.String(), provider.APIVersion)
+	assert.Equal(t, notificationv1beta2.ProviderKind, provider.Kind)
+	assert.Equal(t, "slack-0", provider.Name)
+	assert.Equal(t, "flux-system", provider.Namespace)
+	assert.Equal(t, notificationv1beta2.SlackProvider, provider.Spec.Type)
+	assert.Equal(t, "#flux", provider.Spec.Channel)
+	require.NotNil(t, provider.Spec.SecretRef)
+	assert.Equal(t, "slack-0-address", provider.Spec.SecretRef.Name)
+
+	alert := &notificationv1beta2.Alert{}
+	require.NoError(t, yaml.UnmarshalStrict([]byte(docs[1]), alert))
+	assert.Equal(t, "notification.toolkit.fluxcd.io/v1beta2", alert.APIVersion)
+	assert.Equal(t, notificationv1beta2.AlertKind, alert.Kind)
+	assert.Equal(t, "slack-0", alert.Name)
+	assert.Equal(t, "slack-0", alert.Spec.ProviderRef.Name)
+	assert.Equal(t, "error", alert.Spec.EventSeverity)
+	require.Len(t, alert.Spec.EventSources, 2)
+	assert.Equal(t, kustomizev1.KustomizationKind, alert.Spec.EventSources[0].Kind)
+	assert.Equal(t, sourcev1.GitRepositoryKind, alert.Spec.EventSources[1].Kind)
+
+	secret := &corev1.Secret{}
+	require.NoError(t, kubeClient.Get(context.Background(), client.ObjectKey{Name: "slack-0-address", Namespace: "flux-system"}, secret))
+	assert.Equal(t, "https://hooks.slack.com/services/s3cr3t", string(secret.Data["address"]))
+}
+
+func TestReconcileAlertsWithoutAlerts(t *testing.T) {
+	f := &fluxInstall{fluxOptions: &fluxOptions{}}
+	assert.NoError(t, f.reconcileAlerts(context.Background()))
+}
+
+func TestValidateFluxAlerts(t *testing.T) {
+	testCases := []struct {
+		name        string
+		alert       FluxAlert
+		expectedErr string
+	}{
+		{
+			name:  "slack",
+			alert: FluxAlert{Provider: "slack", Address: "https://hooks.slack.com/services/s3cr3t"},
+		},
+		{
+			name:        "unknown provider",
+			alert:       FluxAlert{Provider: "teams", Address: "https://example.com"},
+			expectedErr: `unknown flux alert provider "teams", must be one of slack, pagerduty, generic`,
+		},
+		{
+			name:        "missing address",
+			alert:       FluxAlert{Provider: "generic"},
+			expectedErr: "flux alert address must be set",
+		},
+	}
+
+	p, err := printer.Newprinter(io.Discard)
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := &options{
+				repositoryName:   "mpas",
+				restClientGetter: genericclioptions.NewConfigFlags(false),
+				kubeclient:       fake.NewClientBuilder().Build(),
+				printer:          p,
+			}
+			WithFluxAlerts([]FluxAlert{tc.alert})(opts)
+			err := validateOptions(opts)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
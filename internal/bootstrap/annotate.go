@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/open-component-model/mpas/internal/env"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fluxKustomizationNameLabel and fluxKustomizationNamespaceLabel are set by the kustomize-controller
+// on all objects applied by a Kustomization.
+const (
+	fluxKustomizationNameLabel      = "kustomize.toolkit.fluxcd.io/name"
+	fluxKustomizationNamespaceLabel = "kustomize.toolkit.fluxcd.io/namespace"
+)
+
+// Annotate adds the given labels to all Deployments, Kustomizations, GitRepositories and Secrets
+// installed by the bootstrap. These are the secrets labeled as managed by mpas and the objects
+// Flux applies from the management repository. Existing labels with the same keys are overwritten.
+func (b *Bootstrap) Annotate(ctx context.Context, labels map[string]string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	if _, ok := labels[managedByLabel]; ok {
+		return fmt.Errorf("label %s cannot be changed", managedByLabel)
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"labels": labels,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal labels patch: %w", err)
+	}
+
+	// strategic merge patches are not supported for custom resources, which are patched
+	// with a JSON merge patch instead. Both merge the labels into the existing ones.
+	lists := []struct {
+		list      client.ObjectList
+		patchType types.PatchType
+	}{
+		{list: &appsv1.DeploymentList{}, patchType: types.StrategicMergePatchType},
+		{list: &corev1.SecretList{}, patchType: types.StrategicMergePatchType},
+		{list: &kustomizev1.KustomizationList{}, patchType: types.MergePatchType},
+		{list: &sourcev1.GitRepositoryList{}, patchType: types.MergePatchType},
+	}
+
+	fluxNamespace := b.componentNamespace(env.FluxName, env.DefaultFluxNamespace)
+	selectors := []client.MatchingLabels{
+		{managedByLabel: managedByValue},
+		{fluxKustomizationNameLabel: fluxNamespace, fluxKustomizationNamespaceLabel: fluxNamespace},
+	}
+
+	for _, l := range lists {
+		patched := map[client.ObjectKey]bool{}
+		for _, selector := range selectors {
+			if err := b.kubeclient.List(ctx, l.list, selector); err != nil {
+				return fmt.Errorf("failed to list %T: %w", l.list, err)
+			}
+
+			objects, err := apimeta.ExtractList(l.list)
+			if err != nil {
+				return fmt.Errorf("failed to extract %T: %w", l.list, err)
+			}
+
+			for _, o := range objects {
+				obj, ok := o.(client.Object)
+				if !ok {
+					return fmt.Errorf("unexpected object %T", o)
+				}
+				if patched[client.ObjectKeyFromObject(obj)] {
+					continue
+				}
+				if err := b.kubeclient.Patch(ctx, obj, client.RawPatch(l.patchType, patch)); err != nil {
+					return fmt.Errorf("failed to label %T %s/%s: %w", obj, obj.GetNamespace(), obj.GetName(), err)
+				}
+				patched[client.ObjectKeyFromObject(obj)] = true
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAnnotate(t *testing.T) {
+	managed := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "mpas-system",
+			Labels:    map[string]string{managedByLabel: managedByValue, "existing": "label"},
+		}
+	}
+
+	// objects applied by Flux from the management repository carry the labels of the Kustomization
+	applied := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "flux-system",
+			Labels: map[string]string{
+				fluxKustomizationNameLabel:      "flux-system",
+				fluxKustomizationNamespaceLabel: "flux-system",
+				"existing":                      "label",
+			},
+		}
+	}
+
+	objects := []client.Object{
+		&appsv1.Deployment{ObjectMeta: managed("deployment")},
+		&corev1.Secret{ObjectMeta: managed("secret")},
+		&kustomizev1.Kustomization{ObjectMeta: managed("kustomization")},
+		&sourcev1.GitRepository{ObjectMeta: managed("gitrepository")},
+		&appsv1.Deployment{ObjectMeta: applied("source-controller")},
+		&kustomizev1.Kustomization{ObjectMeta: applied("flux-system")},
+		&sourcev1.GitRepository{ObjectMeta: applied("flux-system")},
+	}
+	unmanaged := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: "mpas-system"}}
+
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+	kubeclient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, unmanaged)...).Build()
+
+	b := &Bootstrap{options: options{kubeclient: kubeclient}}
+	require.NoError(t, b.Annotate(context.Background(), map[string]string{"team": "platform", "existing": "overwritten"}))
+
+	for _, obj := range objects {
+		expected := maps.Clone(obj.GetLabels())
+		expected["existing"] = "overwritten"
+		expected["team"] = "platform"
+		require.NoError(t, kubeclient.Get(context.Background(), client.ObjectKeyFromObject(obj), obj))
+		assert.Equal(t, expected, obj.GetLabels(), obj.GetName())
+	}
+
+	require.NoError(t, kubeclient.Get(context.Background(), client.ObjectKeyFromObject(unmanaged), unmanaged))
+	assert.Empty(t, unmanaged.GetLabels())
+
+	err = b.Annotate(context.Background(), map[string]string{managedByLabel: "other"})
+	assert.ErrorContains(t, err, "cannot be changed")
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// archArm64 is the architecture for which arm64 tagged image variants are used.
+const archArm64 = "arm64"
+
+// WithNodeArchitecture sets the node architecture of the target cluster.
+// If it is not set, the architecture is detected from the node labels.
+func WithNodeArchitecture(arch string) Option {
+	return func(o *options) {
+		o.nodeArchitecture = arch
+	}
+}
+
+// withNodeArchitecture sets the node architecture the flux images are patched for.
+func withNodeArchitecture(arch string) fluxOption {
+	return func(o *fluxOptions) {
+		o.nodeArchitecture = arch
+	}
+}
+
+// resolveNodeArchitecture sets the node architecture from the node labels if it was not configured.
+// The architecture is only set if all nodes share the same architecture.
+func (b *Bootstrap) resolveNodeArchitecture(ctx context.Context) error {
+	if b.nodeArchitecture != "" || b.kubeclient == nil {
+		return nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := b.kubeclient.List(ctx, nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	var arch string
+	for _, node := range nodes.Items {
+		nodeArch := node.Labels[corev1.LabelArchStable]
+		if arch != "" && nodeArch != arch {
+			return nil
+		}
+		arch = nodeArch
+	}
+	b.nodeArchitecture = arch
+
+	return nil
+}
+
+// patchImageArchitecture rewrites the container images of all Deployments to the variant tagged
+// for the given architecture, e.g. image:v1.0.0 becomes image:v1.0.0-arm64.
+// Only arm64 is patched, other architectures use the images as they are.
+func patchImageArchitecture(content []byte, arch string) ([]byte, error) {
+	if arch != archArm64 {
+		return content, nil
+	}
+
+	objects, err := kubeutils.YamlToUnstructructured(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert yaml to unstructured: %w", err)
+	}
+
+	for _, obj := range objects {
+		if obj.GetKind() != "Deployment" {
+			continue
+		}
+
+		for _, field := range []string{"containers", "initContainers"} {
+			path := []string{"spec", "template", "spec", field}
+			containers, found, err := unstructured.NestedSlice(obj.Object, path...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s of deployment %s: %w", field, obj.GetName(), err)
+			}
+			if !found {
+				continue
+			}
+
+			for _, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if image, ok := container["image"].(string); ok {
+					container["image"] = archImage(image, arch)
+				}
+			}
+
+			if err := unstructured.SetNestedSlice(obj.Object, containers, path...); err != nil {
+				return nil, fmt.Errorf("failed to set %s of deployment %s: %w", field, obj.GetName(), err)
+			}
+		}
+	}
+
+	return kubeutils.UnstructuredToYaml(objects)
+}
+
+// archImage returns the image reference of the given architecture variant.
+// Images pinned by digest or without a tag are returned as they are.
+func archImage(image, arch string) string {
+	if strings.Contains(image, "@") {
+		return image
+	}
+
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") || strings.HasSuffix(image, "-"+arch) {
+		return image
+	}
+
+	return fmt.Sprintf("%s-%s", image, arch)
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPatchImageArchitecture(t *testing.T) {
+	data, err := patchImageArchitecture(kustomizedDeployment, archArm64)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "image: ghcr.io/user/git-controller:v1.0.0-arm64")
+
+	data, err = patchImageArchitecture(kustomizedDeployment, "amd64")
+	require.NoError(t, err)
+	assert.Equal(t, kustomizedDeployment, data)
+}
+
+func TestArchImage(t *testing.T) {
+	testCases := []struct {
+		image    string
+		expected string
+	}{
+		{image: "ghcr.io/user/git-controller:v1.0.0", expected: "ghcr.io/user/git-controller:v1.0.0-arm64"},
+		{image: "ghcr.io/user/git-controller:v1.0.0-arm64", expected: "ghcr.io/user/git-controller:v1.0.0-arm64"},
+		{image: "localhost:5000/git-controller", expected: "localhost:5000/git-controller"},
+		{image: "ghcr.io/user/git-controller@sha256:abc", expected: "ghcr.io/user/git-controller@sha256:abc"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.image, func(t *testing.T) {
+			assert.Equal(t, tc.expected, archImage(tc.image, archArm64))
+		})
+	}
+}
+
+func TestResolveNodeArchitecture(t *testing.T) {
+	node := func(name, arch string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{corev1.LabelArchStable: arch},
+		}}
+	}
+
+	testCases := []struct {
+		name     string
+		nodes    []*corev1.Node
+		expected string
+	}{
+		{
+			name:     "arm64 nodes",
+			nodes:    []*corev1.Node{node("node-1", "arm64"), node("node-2", "arm64")},
+			expected: "arm64",
+		},
+		{
+			name:  "mixed nodes",
+			nodes: []*corev1.Node{node("node-1", "arm64"), node("node-2", "amd64")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder()
+			for _, n := range tc.nodes {
+				builder = builder.WithObjects(n)
+			}
+			b := &Bootstrap{options: options{kubeclient: builder.Build()}}
+			require.NoError(t, b.resolveNodeArchitecture(context.Background()))
+			assert.Equal(t, tc.expected, b.nodeArchitecture)
+		})
+	}
+
+	b := &Bootstrap{options: options{nodeArchitecture: "amd64"}}
+	require.NoError(t, b.resolveNodeArchitecture(context.Background()))
+	assert.Equal(t, "amd64", b.nodeArchitecture)
+}
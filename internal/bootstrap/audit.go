@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/open-component-model/mpas/internal/env"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// InstallLogSourceAuditLog marks entries read from the audit log file.
+	InstallLogSourceAuditLog = "audit-log"
+	// InstallLogSourceKubernetesEvent marks entries read from Kubernetes events.
+	InstallLogSourceKubernetesEvent = "kubernetes-event"
+)
+
+// AuditEvent is a record of the audit log file. A record is appended for every progress event.
+type AuditEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Phase     string    `json:"phase"`
+	Component string    `json:"component,omitempty"`
+	Message   string    `json:"message"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// InstallLogEntry is an entry of the install audit trail returned by GetInstallLog.
+type InstallLogEntry struct {
+	Timestamp time.Time
+	// Phase is the bootstrap phase, or the reason of a Kubernetes event.
+	Phase string
+	// Component is the component of the audit event, or the object of a Kubernetes event.
+	Component string
+	Message   string
+	Error     string
+	// Source is either audit-log or kubernetes-event.
+	Source string
+}
+
+// WithAuditLogPath appends an AuditEvent as a JSON line to the file at path for every
+// progress event of the bootstrap.
+func WithAuditLogPath(path string) Option {
+	return func(o *options) {
+		o.auditLogPath = path
+	}
+}
+
+// writeAuditEvent appends the progress event to the audit log file if it is configured.
+// Failing to write the audit log does not fail the bootstrap.
+func (b *Bootstrap) writeAuditEvent(event ProgressEvent) {
+	if b.auditLogPath == "" {
+		return
+	}
+
+	record := AuditEvent{
+		Timestamp: time.Now().UTC(),
+		Phase:     event.Phase,
+		Component: event.Component,
+		Message:   event.Message,
+	}
+	if event.Err != nil {
+		record.Error = event.Err.Error()
+	}
+
+	if err := appendAuditEvent(b.auditLogPath, record); err != nil {
+		b.log().Warn("Failed to write audit log", slog.String("path", b.auditLogPath), slog.Any("error", err))
+	}
+}
+
+func appendAuditEvent(path string, record AuditEvent) (err error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// GetInstallLog returns the install audit trail, newest entries first.
+// The entries are read from the audit log file if it is configured, otherwise from the
+// Kubernetes events of the Flux objects syncing the management repository.
+func (b *Bootstrap) GetInstallLog(ctx context.Context) ([]InstallLogEntry, error) {
+	var (
+		entries []InstallLogEntry
+		err     error
+	)
+	if b.auditLogPath != "" {
+		entries, err = readAuditLog(b.auditLogPath)
+	} else {
+		entries, err = b.syncEvents(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+
+	return entries, nil
+}
+
+// readAuditLog reads the AuditEvent records of the audit log file.
+func readAuditLog(path string) ([]InstallLogEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []InstallLogEntry
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse audit log %s line %d: %w", path, line, err)
+		}
+
+		entries = append(entries, InstallLogEntry{
+			Timestamp: record.Timestamp,
+			Phase:     record.Phase,
+			Component: record.Component,
+			Message:   record.Message,
+			Error:     record.Error,
+			Source:    InstallLogSourceAuditLog,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// syncEvents returns the Kubernetes events of the GitRepository and Kustomization syncing
+// the management repository.
+func (b *Bootstrap) syncEvents(ctx context.Context) ([]InstallLogEntry, error) {
+	namespace := b.componentNamespace(env.FluxName, env.DefaultFluxNamespace)
+
+	events := &corev1.EventList{}
+	if err := b.kubeclient.List(ctx, events, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	var entries []InstallLogEntry
+	for _, event := range events.Items {
+		obj := event.InvolvedObject
+		if obj.Name != namespace || (obj.Kind != sourcev1.GitRepositoryKind && obj.Kind != kustomizev1.KustomizationKind) {
+			continue
+		}
+
+		entry := InstallLogEntry{
+			Timestamp: eventTime(event),
+			Phase:     event.Reason,
+			Component: fmt.Sprintf("%s/%s", obj.Kind, obj.Name),
+			Message:   event.Message,
+			Source:    InstallLogSourceKubernetesEvent,
+		}
+		if event.Type == corev1.EventTypeWarning {
+			entry.Error = event.Message
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// eventTime returns the time the event was last observed.
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
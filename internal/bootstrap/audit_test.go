@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testAuditLog = `{"timestamp":"2023-06-01T10:00:00Z","phase":"component-install","component":"flux","message":"started"}
+{"timestamp":"2023-06-01T10:02:00Z","phase":"health-check","component":"ocm-system","message":"failed","error":"deployment not ready"}
+
+{"timestamp":"2023-06-01T10:01:00Z","phase":"component-install","component":"flux","message":"finished"}
+`
+
+func TestGetInstallLogFromAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	require.NoError(t, os.WriteFile(path, []byte(testAuditLog), 0o600))
+
+	b := &Bootstrap{options: options{auditLogPath: path}}
+	entries, err := b.GetInstallLog(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []InstallLogEntry{
+		{
+			Timestamp: time.Date(2023, 6, 1, 10, 2, 0, 0, time.UTC),
+			Phase:     ProgressPhaseHealthCheck,
+			Component: "ocm-system",
+			Message:   "failed",
+			Error:     "deployment not ready",
+			Source:    InstallLogSourceAuditLog,
+		},
+		{
+			Timestamp: time.Date(2023, 6, 1, 10, 1, 0, 0, time.UTC),
+			Phase:     ProgressPhaseComponentInstall,
+			Component: "flux",
+			Message:   "finished",
+			Source:    InstallLogSourceAuditLog,
+		},
+		{
+			Timestamp: time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC),
+			Phase:     ProgressPhaseComponentInstall,
+			Component: "flux",
+			Message:   "started",
+			Source:    InstallLogSourceAuditLog,
+		},
+	}, entries)
+
+	require.NoError(t, os.WriteFile(path, []byte("not json\n"), 0o600))
+	_, err = b.GetInstallLog(context.Background())
+	assert.ErrorContains(t, err, "line 1")
+}
+
+func TestWriteAuditEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	b := &Bootstrap{options: options{auditLogPath: path}}
+
+	b.emit(ProgressEvent{Phase: ProgressPhaseComponentInstall, Component: "flux", Message: "started"})
+	b.emit(ProgressEvent{Phase: ProgressPhaseComponentInstall, Component: "flux", Message: "failed", Err: errors.New("boom")})
+
+	entries, err := readAuditLog(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "started", entries[0].Message)
+	assert.Equal(t, "failed", entries[1].Message)
+	assert.Equal(t, "boom", entries[1].Error)
+}
+
+func TestGetInstallLogFromEvents(t *testing.T) {
+	newEvent := func(name, kind, objName, reason, eventType string, ts time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "flux-system"},
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: objName, Namespace: "flux-system"},
+			Reason:         reason,
+			Message:        reason + " " + objName,
+			Type:           eventType,
+			LastTimestamp:  metav1.NewTime(ts),
+		}
+	}
+	start := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	kubeclient := fake.NewClientBuilder().WithObjects(
+		newEvent("a", "GitRepository", "flux-system", "NewArtifact", corev1.EventTypeNormal, start),
+		newEvent("b", "Kustomization", "flux-system", "ReconciliationFailed", corev1.EventTypeWarning, start.Add(time.Minute)),
+		newEvent("c", "Deployment", "source-controller", "ScalingReplicaSet", corev1.EventTypeNormal, start.Add(2*time.Minute)),
+	).Build()
+
+	b := &Bootstrap{options: options{kubeclient: kubeclient}}
+	entries, err := b.GetInstallLog(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "Kustomization/flux-system", entries[0].Component)
+	assert.Equal(t, "ReconciliationFailed", entries[0].Phase)
+	assert.Equal(t, "ReconciliationFailed flux-system", entries[0].Error)
+	assert.Equal(t, InstallLogSourceKubernetesEvent, entries[0].Source)
+	assert.Equal(t, "GitRepository/flux-system", entries[1].Component)
+	assert.Empty(t, entries[1].Error)
+}
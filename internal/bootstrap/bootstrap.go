@@ -9,13 +9,19 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/mpas/internal/bootstrap/provider"
 	"github.com/open-component-model/mpas/internal/env"
 	"github.com/open-component-model/mpas/internal/kubeutils"
 	"github.com/open-component-model/mpas/internal/ocm"
@@ -23,8 +29,13 @@ import (
 	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
 	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
 	"github.com/open-component-model/ocm/pkg/contexts/ocm/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	kustypes "sigs.k8s.io/kustomize/api/types"
 )
 
 var (
@@ -48,12 +59,116 @@ type options struct {
 	transportType         string
 	kubeclient            client.Client
 	restClientGetter      genericclioptions.RESTClientGetter
+	applier               applyFunc
 	components            []string
 	interval              time.Duration
 	timeout               time.Duration
 	printer               *printer.Printer
 	testURL               string
 	caFile                string
+	dryRun                bool
+	componentIntervals    map[string]time.Duration
+	destructiveUninstall  bool
+	skipCompletedPhases   bool
+	clusterOnly           bool
+	progressChan          chan<- ProgressEvent
+	skipPreflightChecks   bool
+	gitlabCI              *GitLabCISpec
+	componentNamespaces   map[string]string
+	fluxOCISource         bool
+	publicKeyPath         string
+	fallbackRegistries    []string
+	nodeArchitecture      string
+	imageMirrors          map[string]string
+	maxResourceSize       int64
+	imagePullSecrets      []string
+	logHandler            slog.Handler
+	metricsRegisterer     prometheus.Registerer
+	tracerProvider        trace.TracerProvider
+	// lockConfigMapName and lockConfigMapNamespace locate the ConfigMap storing the lock file if set
+	lockConfigMapName      string
+	lockConfigMapNamespace string
+	enforceLock            bool
+	fluxPDBMinAvailable    int
+	// signingKeyPath and signingKeyPassphrase sign the Flux component commits if set
+	signingKeyPath         string
+	signingKeyPassphrase   string
+	additionalFluxCRDPaths []string
+	kustomizationPatches   []kustypes.Patch
+	requiredSecrets        []SecretRef
+	fluxResourceFilter     map[string]string
+	// fluxSourceControllerPullPolicy is the imagePullPolicy of the source-controller if set
+	fluxSourceControllerPullPolicy corev1.PullPolicy
+	// force allows UpgradeToVersion to downgrade components
+	force bool
+	// allowDowngrade allows Upgrade to downgrade components
+	allowDowngrade bool
+	// cloneDepth limits the history of the management repository clone if positive
+	cloneDepth         int
+	preApplyValidation bool
+	// fluxOIDCProvider and fluxOIDCIdentity configure the workload identity of the source-controller
+	fluxOIDCProvider string
+	fluxOIDCIdentity string
+	fluxReceivers    []FluxReceiver
+	// scanner scans the flux images for vulnerabilities above maxSeverity if set
+	scanner     ScannerClient
+	maxSeverity string
+	// preInstallHooks run before any component is installed, postInstallHooks after all components are installed
+	preInstallHooks  []Hook
+	postInstallHooks []Hook
+	// auditLogPath is the file progress events are appended to as AuditEvent records if set
+	auditLogPath string
+	// sandboxedBuild builds the flux kustomization in memory instead of on disk
+	sandboxedBuild bool
+	// clusterName identifies the cluster in a MultiClusterBootstrap
+	clusterName string
+	// fluxVPAUpdateMode is the updateMode of the Flux VerticalPodAutoscalers, none are created if empty
+	fluxVPAUpdateMode string
+	// subgroupDepth limits the number of GitLab subgroups of the repository name if positive
+	subgroupDepth int
+	// fluxServiceAccountAnnotations are added to the ServiceAccounts of the Flux controllers
+	fluxServiceAccountAnnotations map[string]map[string]string
+	// commitAuthorName and commitAuthorEmail are the author of the component commits if set
+	commitAuthorName  string
+	commitAuthorEmail string
+	// githubActions is the GitHub Actions workflow committed to GitHub management repositories if set
+	githubActions *GHActionsSpec
+	// profile is the bootstrap profile applied, its requirements are validated if set
+	profile                      BootstrapProfile
+	requireSignatureVerification bool
+	requireCommitSigning         bool
+	// installConditions skip the installation of a component if they are not met
+	installConditions map[string]InstallCondition
+	// airGapBundle is the bundle served as the registry of the bootstrap if set
+	airGapBundle string
+	// versionFilter is the semver constraint the versions returned by ListComponentVersions match if set
+	versionFilter string
+	// fluxResourceLimits and fluxResourceRequests are the resources of the Flux controllers, keyed by controller name
+	fluxResourceLimits   map[string]corev1.ResourceList
+	fluxResourceRequests map[string]corev1.ResourceList
+	// codeOwners are the owners of the files of the management repository if set
+	codeOwners []string
+	// branchProtection protects the default branch of the management repository if set
+	branchProtection *BranchProtection
+	// minKubernetesVersion is the semver constraint the cluster version must satisfy if set
+	minKubernetesVersion string
+	// annotationPropagation adds the extra identity of the flux resource as annotations to the flux objects
+	annotationPropagation bool
+	// sshKeyPath and sshPassphrase authenticate the Flux GitRepository if the transport type is ssh
+	sshKeyPath    string
+	sshPassphrase string
+	// sshKnownHostsPath is the known_hosts file of the Flux GitRepository if set
+	sshKnownHostsPath string
+	// fluxAlerts send the reconciliation failures of the flux objects to the given providers
+	fluxAlerts []FluxAlert
+	// imageAutomation installs the flux image automation controllers and an ImageUpdateAutomation
+	imageAutomation bool
+	// bootstrapLock locks the management repository against concurrent bootstrap runs
+	bootstrapLock bool
+	// bootstrapLockTTL is the age after which the bootstrap lock is stale, defaultLockTTL if not set
+	bootstrapLockTTL time.Duration
+	// tokenFunc returns the token of the management repository instead of token if set
+	tokenFunc func(ctx context.Context) (string, error)
 }
 
 // Option is a function that sets an option on the bootstrap
@@ -66,6 +181,17 @@ type Bootstrap struct {
 	providerClient gitprovider.Client
 	repository     gitprovider.UserRepository
 	url            string
+	state          *bootstrapState
+	publicKey      []byte
+	// selectedRegistry is the reachable registry chosen for this run, guarded by registryMu
+	selectedRegistry string
+	registryMu       sync.Mutex
+	// metrics records the bootstrap metrics if a registerer is configured
+	metrics *Metrics
+	// sshURL is the SSH clone URL the flux GitRepository syncs from if the transport is ssh
+	sshURL string
+	// lockHolder identifies the bootstrap lock held by this run if set
+	lockHolder string
 	options
 }
 
@@ -96,6 +222,16 @@ func WithInterval(interval time.Duration) Option {
 	}
 }
 
+// WithComponentInterval sets the interval to use for the given component, overriding the default interval
+func WithComponentInterval(component string, interval time.Duration) Option {
+	return func(o *options) {
+		if o.componentIntervals == nil {
+			o.componentIntervals = make(map[string]time.Duration)
+		}
+		o.componentIntervals[component] = interval
+	}
+}
+
 // WithTimeout sets the timeout to use for the bootstrap component
 func WithTimeout(timeout time.Duration) Option {
 	return func(o *options) {
@@ -124,6 +260,14 @@ func WithDockerConfigPath(dockerConfigPath string) Option {
 	}
 }
 
+// WithSubgroupDepth limits the number of path segments of the repository name that are treated as
+// GitLab subgroups, e.g. 1 allows subgroup/repo but rejects group/subgroup/repo. It is not limited by default.
+func WithSubgroupDepth(depth int) Option {
+	return func(o *options) {
+		o.subgroupDepth = depth
+	}
+}
+
 // WithTarget sets the targetPath of the bootstrap component
 func WithTarget(targetPath string) Option {
 	return func(o *options) {
@@ -188,6 +332,15 @@ func WithToken(token string) Option {
 	}
 }
 
+// WithTokenFunc sets the function returning the token the management repository is cloned and pushed with.
+// It takes precedence over WithToken and is called before each git operation, so that short-lived tokens
+// like GitHub App installation tokens are refreshed, see provider.GitHubAppTokenFunc.
+func WithTokenFunc(tokenFunc func(ctx context.Context) (string, error)) Option {
+	return func(o *options) {
+		o.tokenFunc = tokenFunc
+	}
+}
+
 // WithRepositoryName sets the repository name of the management repository
 func WithRepositoryName(repositoryName string) Option {
 	return func(o *options) {
@@ -209,6 +362,29 @@ func WithRegistry(registry string) Option {
 	}
 }
 
+// WithDryRun enables the dry-run mode. In dry-run mode the bootstrap only prints the generated
+// manifests instead of committing, pushing and applying them.
+func WithDryRun(dryRun bool) Option {
+	return func(o *options) {
+		o.dryRun = dryRun
+	}
+}
+
+// WithComponentNamespaces sets the namespaces to install the given components into, keyed by component name.
+// Components that are not part of the map are installed into their default namespace.
+func WithComponentNamespaces(componentNamespaces map[string]string) Option {
+	return func(o *options) {
+		o.componentNamespaces = componentNamespaces
+	}
+}
+
+// WithFluxOCISource sets whether a Flux OCIRepository source for the bootstrap component is generated.
+func WithFluxOCISource(enabled bool) Option {
+	return func(o *options) {
+		o.fluxOCISource = enabled
+	}
+}
+
 // WithTransportType sets the transport type to use for git operations
 func WithTransportType(transportType string) Option {
 	return func(o *options) {
@@ -232,11 +408,77 @@ func New(providerClient gitprovider.Client, opts ...Option) (*Bootstrap, error)
 		return nil, err
 	}
 
+	if b.metricsRegisterer != nil {
+		metrics, err := NewMetrics(b.metricsRegisterer)
+		if err != nil {
+			return nil, err
+		}
+		b.metrics = metrics
+	}
+
+	if b.publicKeyPath != "" {
+		publicKey, err := os.ReadFile(b.publicKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key: %w", err)
+		}
+		b.publicKey = publicKey
+	}
+
 	return b, nil
 }
 
+// ListAvailableProviders returns the names of the available git providers.
+// The provider of the configured provider client is returned first, followed by
+// all other registered providers in alphabetical order.
+// Additional providers can be registered via provider.RegisterProvider.
+func (b *Bootstrap) ListAvailableProviders() []string {
+	var configured string
+	if b.providerClient != nil {
+		configured = string(b.providerClient.ProviderID())
+	}
+
+	available := provider.List()
+	names := make([]string, 0, len(available)+1)
+	if configured != "" {
+		names = append(names, configured)
+	}
+	for _, name := range available {
+		if name != configured {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// GetKubernetesClient returns the Kubernetes client set with WithKubeClient.
+// Bootstrap keeps no state in the client, so using it does not affect the bootstrap.
+func (b *Bootstrap) GetKubernetesClient() client.Client {
+	return b.kubeclient
+}
+
+// GetRESTClientGetter returns the REST client getter set with WithRESTClientGetter.
+// Bootstrap keeps no state in it, so using it does not affect the bootstrap.
+func (b *Bootstrap) GetRESTClientGetter() genericclioptions.RESTClientGetter {
+	return b.restClientGetter
+}
+
 // Run runs the bootstrap of mpas and returns an error if it fails.
-func (b *Bootstrap) Run(ctx context.Context) error {
+func (b *Bootstrap) Run(ctx context.Context) (err error) {
+	defer func(start time.Time) {
+		b.metrics.observeRun(start, err)
+	}(time.Now())
+
+	ctx, span := b.tracer().Start(ctx, "bootstrap.Run", trace.WithAttributes(
+		attribute.String("registry", b.registry),
+		attribute.String("repository", b.repositoryName),
+	))
+	defer span.End()
+
+	return endSpan(span, b.run(ctx))
+}
+
+func (b *Bootstrap) run(ctx context.Context) error {
 	octx := om.DefaultContext()
 	if _, err := utils.Configure(octx, ""); err != nil {
 		return fmt.Errorf("failed to configure ocm context: %w", err)
@@ -244,14 +486,63 @@ func (b *Bootstrap) Run(ctx context.Context) error {
 	// set default log level to 1 which is ERROR level to avoid printing INFO messages
 	octx.LoggingContext().SetDefaultLevel(1)
 
-	b.printer.Printf("Running %s ...\n",
-		printer.BoldBlue("mpas bootstrap"))
+	b.log().InfoContext(ctx, "Running mpas bootstrap ...", slog.String("command", "mpas bootstrap"))
 
-	if err := b.inSpinner(fmt.Sprintf("Preparing Management repository %s",
-		printer.BoldBlue(b.repositoryName)), func() error {
-		return b.reconcileManagementRepository(ctx)
-	}); err != nil {
-		return fmt.Errorf("failed to prepare management repository: %w", err)
+	if b.airGapBundle != "" {
+		cleanup, err := b.mountAirGapBundle(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to mount air-gap bundle: %w", err)
+		}
+		defer cleanup()
+	}
+
+	// the primary registry is probed when the repository is created if there are fallback registries
+	if len(b.fallbackRegistries) == 0 {
+		if err := b.inSpinner(fmt.Sprintf("Checking connectivity to registry %s", printer.BoldBlue(b.registry)), func() error {
+			return b.CheckRegistryConnectivity(ctx)
+		}); err != nil {
+			return fmt.Errorf("registry connectivity check failed: %w", err)
+		}
+	}
+
+	if !b.skipPreflightChecks {
+		if err := b.inSpinner("Running pre-flight checks", func() error {
+			var errs []error
+			for _, perr := range b.PreflightCheck(ctx) {
+				errs = append(errs, perr)
+			}
+			return errors.Join(errs...)
+		}); err != nil {
+			return fmt.Errorf("pre-flight checks failed: %w", err)
+		}
+	}
+
+	if err := b.runPreInstallHooks(ctx); err != nil {
+		return err
+	}
+
+	if !b.clusterOnly {
+		defer b.releaseBootstrapLockOnExit(ctx)
+		if err := b.inSpinner(fmt.Sprintf("Preparing Management repository %s",
+			printer.BoldBlue(b.repositoryName)), b.trackProgress(ProgressPhaseRepository, b.repositoryName, func() error {
+			return b.reconcileManagementRepository(ctx)
+		})); err != nil {
+			return fmt.Errorf("failed to prepare management repository: %w", err)
+		}
+	}
+
+	b.state = &bootstrapState{}
+	if b.skipCompletedPhases {
+		if err := b.inSpinner("Reading bootstrap state", func() error {
+			state, err := b.readBootstrapState(ctx)
+			if err != nil {
+				return err
+			}
+			b.state = state
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to read bootstrap state: %w", err)
+		}
 	}
 
 	if b.fromFile != "" {
@@ -281,6 +572,10 @@ func (b *Bootstrap) Run(ctx context.Context) error {
 		}
 	}
 
+	if err := b.resolveNodeArchitecture(ctx); err != nil {
+		return fmt.Errorf("failed to detect node architecture: %w", err)
+	}
+
 	var (
 		refs    map[string]compdesc.ComponentReference
 		ociRepo om.Repository
@@ -289,11 +584,23 @@ func (b *Bootstrap) Run(ctx context.Context) error {
 
 	if err := b.inSpinner(fmt.Sprintf("Fetching bootstrap component from %s",
 		printer.BoldBlue(b.registry)), func() error {
-		ociRepo, err = ocm.MakeRepositoryWithDockerConfig(octx, b.registry, b.dockerConfigPath)
+		if b.fromFile != "" {
+			ociRepo, err = ocm.MakeRepositoryWithDockerConfig(octx, b.registry, b.dockerConfigPath)
+		} else {
+			ociRepo, err = b.makeOCIRepositoryWithFallback(ctx, octx)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to fetch bootstrap component references: %w", err)
 		}
 
+		if b.enforceLock {
+			refs, err = b.readLockedReferences(ctx, ociRepo)
+			if err != nil {
+				return fmt.Errorf("failed to read locked component versions: %w", err)
+			}
+			return nil
+		}
+
 		refs, err = b.fetchBootstrapComponentReferences(ociRepo)
 		if err != nil {
 			return fmt.Errorf("failed to fetch bootstrap component references: %w", err)
@@ -304,6 +611,21 @@ func (b *Bootstrap) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to fetch bootstrap components: %w", err)
 	}
 
+	if err := b.skipConditionalComponents(ctx, refs); err != nil {
+		return err
+	}
+
+	if b.dryRun {
+		return b.runDryRun(ctx, ociRepo, refs)
+	}
+
+	if b.clusterOnly {
+		return b.runClusterOnly(ctx, ociRepo, refs)
+	}
+
+	// installInfrastructure removes the infrastructure components from refs, but all are locked
+	lockedRefs := maps.Clone(refs)
+
 	sha, err := b.installInfrastructure(ctx, ociRepo, refs)
 	if err != nil {
 		return fmt.Errorf("failed to install infrastructure: %w", err)
@@ -315,7 +637,7 @@ func (b *Bootstrap) Run(ctx context.Context) error {
 		return err
 	}
 
-	if err := b.inSpinner("Waiting for cert-manager to be available", func() error {
+	if err := b.inSpinner("Waiting for cert-manager to be available", b.trackProgress(ProgressPhaseHealthCheck, env.CertManagerName, func() error {
 		if err := kubeutils.ReportComponentsHealth(ctx, b.restClientGetter, b.timeout, []string{
 			certManager,
 			certManagerCAInjector,
@@ -325,27 +647,45 @@ func (b *Bootstrap) Run(ctx context.Context) error {
 		}
 
 		return nil
-	}); err != nil {
+	})); err != nil {
 		return fmt.Errorf("failed to wait for cert-manager to be available: %w", err)
 	}
 
 	compNs := make(map[string][]string)
 	var latestSHA string
-	// install components in order by using the ordered keys
-	comps := getOrderedKeys(refs)
+	// install components in dependency order
+	comps, err := sortComponents(refs)
+	if err != nil {
+		return err
+	}
 	for _, comp := range comps {
 		ref := refs[comp]
 
+		if b.skipCompletedPhases && b.state.completed(phaseComponentInstall, ref.GetComponentName(), ref.GetVersion()) {
+			b.log().InfoContext(ctx, fmt.Sprintf("Skipping %s with version %s, it has already been installed",
+				comp, ref.GetVersion()),
+				slog.String("component", comp),
+				slog.String("version", ref.GetVersion()),
+				slog.String("phase", ProgressPhaseComponentInstall))
+
+			ns, deployments, err := b.componentDeployments(comp)
+			if err != nil {
+				return err
+			}
+			compNs[ns] = append(compNs[ns], deployments...)
+			continue
+		}
+
 		if err := b.inSpinner(fmt.Sprintf("Generating %s manifest with version %s",
 			printer.BoldBlue(comp),
-			printer.BoldBlue(ref.GetVersion())), func() error {
+			printer.BoldBlue(ref.GetVersion())), b.trackProgress(ProgressPhaseComponentInstall, comp, func() error {
 			latestSHA, err = b.generateControllerManifest(ctx, ociRepo, comp, ref, compNs)
 			if err != nil {
 				return err
 			}
 
 			return nil
-		}); err != nil {
+		})); err != nil {
 			return fmt.Errorf("failed to generate manifest: %w", err)
 		}
 	}
@@ -370,7 +710,9 @@ func (b *Bootstrap) Run(ctx context.Context) error {
 
 	if err := b.inSpinner("Waiting for components to be ready", func() error {
 		for ns, comps := range compNs {
-			if err := kubeutils.ReportComponentsHealth(ctx, b.restClientGetter, b.timeout, comps, ns); err != nil {
+			if err := b.trackProgress(ProgressPhaseHealthCheck, ns, func() error {
+				return kubeutils.ReportComponentsHealth(ctx, b.restClientGetter, b.timeout, comps, ns)
+			})(); err != nil {
 				return fmt.Errorf("failed to report health, please try again in a few minutes: %w", err)
 			}
 		}
@@ -380,12 +722,108 @@ func (b *Bootstrap) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to wait for components to be ready: %w", err)
 	}
 
-	b.printer.Printf("\n")
-	b.printer.Printf("Bootstrap completed successfully!\n")
+	if err := b.lockComponentVersions(ctx, lockedRefs); err != nil {
+		return err
+	}
+
+	// the default branch is protected last, as the bootstrap pushes to it directly
+	if err := b.reconcileBranchProtection(ctx, b.repository); err != nil {
+		return err
+	}
+
+	if err := b.runPostInstallHooks(ctx); err != nil {
+		return err
+	}
+
+	b.reportReceivers(ctx)
+	b.logCompleted(ctx, "Bootstrap completed successfully!")
+	b.emit(ProgressEvent{Phase: ProgressPhaseComplete, Message: "bootstrap completed successfully"})
 
 	return nil
 }
 
+// runDryRun prints the manifests of all bootstrap components without committing, pushing
+// or applying them.
+func (b *Bootstrap) runDryRun(ctx context.Context, ociRepo om.Repository, refs map[string]compdesc.ComponentReference) error {
+	fluxRef, ok := refs[env.FluxName]
+	if !ok {
+		return fmt.Errorf("flux component not found")
+	}
+
+	if err := b.installFlux(ctx, ociRepo, fluxRef); err != nil {
+		return fmt.Errorf("failed to generate flux manifests: %w", err)
+	}
+
+	comps, err := sortComponents(refs)
+	if err != nil {
+		return err
+	}
+
+	for _, comp := range comps {
+		if comp == env.FluxName {
+			continue
+		}
+
+		if err := b.printComponentManifest(ctx, ociRepo, comp, refs[comp]); err != nil {
+			return fmt.Errorf("failed to generate %s manifests: %w", comp, err)
+		}
+	}
+
+	b.logCompleted(ctx, "Dry-run completed successfully, no changes were made.")
+
+	return nil
+}
+
+// printComponentManifest generates the manifest of the given component and prints it as a dry-run preview.
+func (b *Bootstrap) printComponentManifest(ctx context.Context, ociRepo om.Repository, comp string, ref compdesc.ComponentReference) error {
+	data, err := b.generateComponentManifest(ctx, ociRepo, comp, ref)
+	if err != nil {
+		return err
+	}
+
+	printDryRunPreview(b.printer, fmt.Sprintf("%s %s", comp, ref.GetVersion()), data)
+
+	return nil
+}
+
+// generateComponentManifest generates the kustomized manifest of the given component
+// without committing it to the management repository.
+func (b *Bootstrap) generateComponentManifest(ctx context.Context, ociRepo om.Repository, comp string, ref compdesc.ComponentReference) ([]byte, error) {
+	dir, err := mkdirTempDir(fmt.Sprintf("%s-manifest", comp))
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	host, resource := env.DefaultOCMHost, fmt.Sprintf("%s-file", comp)
+	switch comp {
+	case env.FluxName:
+		host, resource = env.DefaultFluxHost, env.FluxName
+	case env.CertManagerName:
+		host, resource = env.DefaultCertManagerHost, env.CertManagerName
+	case env.ExternalSecretsName:
+		host, resource = env.DefaultExternalSecretsHost, env.ExternalSecretsName
+	}
+
+	kustomizer := NewKustomizer(&kustomizerOptions{
+		componentName: ref.GetComponentName(),
+		version:       ref.GetVersion(),
+		repository:    ociRepo,
+		dir:           dir,
+		host:          host,
+		namespace:     b.componentNamespaces[comp],
+		publicKey:     b.publicKey,
+	})
+
+	return kustomizer.GenerateKustomizedResourceData(ctx, resource)
+}
+
+// printDryRunPreview prints the given manifest clearly marked as a dry-run preview.
+func printDryRunPreview(p *printer.Printer, name string, data []byte) {
+	p.Printf("--- [dry-run] %s ---\n", name)
+	p.Printf("%s\n", data)
+}
+
 func (b *Bootstrap) inSpinner(msg string, f func() error) (err error) {
 	if err := b.printer.PrintSpinner(msg); err != nil {
 		return err
@@ -404,19 +842,20 @@ func (b *Bootstrap) inSpinner(msg string, f func() error) (err error) {
 
 func (b *Bootstrap) syncManagementRepository(ctx context.Context, latestSHA string) error {
 	expectedRevision := fmt.Sprintf("%s@sha1:%s", b.defaultBranch, latestSHA)
-	if err := kubeutils.ReconcileGitrepository(ctx, b.kubeclient, env.DefaultFluxNamespace, env.DefaultFluxNamespace); err != nil {
+	namespace := b.componentNamespace(env.FluxName, env.DefaultFluxNamespace)
+	if err := kubeutils.ReconcileGitrepository(ctx, b.kubeclient, namespace, namespace); err != nil {
 		return err
 	}
 
-	if err := kubeutils.ReportGitrepositoryHealth(ctx, b.kubeclient, env.DefaultFluxNamespace, env.DefaultFluxNamespace, expectedRevision, env.DefaultPollInterval, b.timeout); err != nil {
+	if err := kubeutils.ReportGitrepositoryHealth(ctx, b.kubeclient, namespace, namespace, expectedRevision, env.DefaultPollInterval, b.timeout); err != nil {
 		return fmt.Errorf("failed to report gitrepository health: %w", err)
 	}
 
-	if err := kubeutils.ReconcileKustomization(ctx, b.kubeclient, env.DefaultFluxNamespace, env.DefaultFluxNamespace); err != nil {
+	if err := kubeutils.ReconcileKustomization(ctx, b.kubeclient, namespace, namespace); err != nil {
 		return err
 	}
 
-	if err := kubeutils.ReportKustomizationHealth(ctx, b.kubeclient, env.DefaultFluxNamespace, env.DefaultFluxNamespace, expectedRevision, env.DefaultPollInterval, b.timeout); err != nil {
+	if err := kubeutils.ReportKustomizationHealth(ctx, b.kubeclient, namespace, namespace, expectedRevision, env.DefaultPollInterval, b.timeout); err != nil {
 		return fmt.Errorf("failed to report kustomization health: %w", err)
 	}
 
@@ -429,19 +868,27 @@ func (b *Bootstrap) installComponent(ctx context.Context, ociRepo om.Repository,
 		return "", err
 	}
 	defer os.RemoveAll(dir)
+	defer b.metrics.observeComponent(comp, time.Now())
+
 	opts := &componentOptions{
 		gitRepository:         b.repository,
 		branch:                b.defaultBranch,
 		targetPath:            b.targetPath,
 		commitMessageAppendix: b.commitMessageAppendix,
 		namespace:             ns,
+		kustomizeNamespace:    b.componentNamespaces[comp],
 		provider:              string(b.providerClient.ProviderID()),
 		dir:                   dir,
 		timeout:               b.timeout,
 		installedNS:           compNs,
+		state:                 b.state,
+		publicKey:             b.publicKey,
+		nodeArchitecture:      b.nodeArchitecture,
+		imageMirrors:          b.imageMirrors,
+		metrics:               b.metrics,
 	}
 
-	inst, err := newComponentInstall(ref.GetComponentName(), ref.GetVersion(), ociRepo, opts)
+	inst, err := newComponentInstall(ref.GetComponentName(), ref.GetVersion(), ociRepo, opts, withImagePullSecrets(b.imagePullSecrets))
 	if err != nil {
 		return "", err
 	}
@@ -458,6 +905,7 @@ func (b *Bootstrap) installFlux(ctx context.Context, ociRepo om.Repository, ref
 		return err
 	}
 	defer os.RemoveAll(dir)
+	defer b.metrics.observeComponent(env.FluxName, time.Now())
 
 	var caBundle []byte
 	if b.caFile != "" {
@@ -467,10 +915,42 @@ func (b *Bootstrap) installFlux(ctx context.Context, ociRepo om.Repository, ref
 		}
 	}
 
+	opts, fopts := b.newFluxOptions(dir, caBundle)
+	if opts.token, err = b.gitToken(ctx); err != nil {
+		return err
+	}
+	inst, err := newFluxInstall(ref.GetComponentName(), ref.GetVersion(), b.owner, ociRepo, opts, fopts...)
+	if err != nil {
+		return err
+	}
+	if err := inst.Install(ctx, "flux"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// gitToken returns the token the management repository is cloned and pushed with.
+func (b *Bootstrap) gitToken(ctx context.Context) (string, error) {
+	if b.tokenFunc == nil {
+		return b.token, nil
+	}
+
+	token, err := b.tokenFunc(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get token of the management repository: %w", err)
+	}
+
+	return token, nil
+}
+
+// newFluxOptions returns the options to install flux with.
+func (b *Bootstrap) newFluxOptions(dir string, caBundle []byte) (*fluxOptions, []fluxOption) {
 	opts := &fluxOptions{
 		kubeClient:            b.kubeclient,
 		restClientGetter:      b.restClientGetter,
+		applier:               b.applier,
 		url:                   b.url,
+		sshURL:                b.sshURL,
 		testURL:               b.testURL,
 		transport:             b.transportType,
 		branch:                b.defaultBranch,
@@ -480,17 +960,57 @@ func (b *Bootstrap) installFlux(ctx context.Context, ociRepo om.Repository, ref
 		interval:              b.interval,
 		timeout:               b.timeout,
 		token:                 b.token,
-		namespace:             env.DefaultFluxNamespace,
+		namespace:             b.componentNamespace(env.FluxName, env.DefaultFluxNamespace),
 		caFile:                caBundle,
-	}
-	inst, err := newFluxInstall(ref.GetComponentName(), ref.GetVersion(), b.owner, ociRepo, opts)
-	if err != nil {
-		return err
-	}
-	if err := inst.Install(ctx, "flux"); err != nil {
-		return err
-	}
-	return nil
+		dryRun:                b.dryRun,
+		clusterOnly:           b.clusterOnly,
+		printer:               b.printer,
+		registry:              b.activeRegistry(),
+		metrics:               b.metrics,
+	}
+	fopts := []fluxOption{
+		withFluxOCISource(b.fluxOCISource),
+		withVerifySignatures(b.publicKey),
+		withNodeArchitecture(b.nodeArchitecture),
+		withImageMirrors(b.imageMirrors),
+		withMaxResourceSize(b.maxResourceSize),
+		withFluxPodDisruptionBudgets(b.fluxPDBMinAvailable),
+		withSigningKey(b.signingKeyPath, b.signingKeyPassphrase),
+		withAdditionalCRDPaths(b.additionalFluxCRDPaths...),
+		withKustomizationPatches(b.kustomizationPatches),
+		withResourceFilter(b.fluxResourceFilter),
+		withFluxSourceControllerImagePullPolicy(b.fluxSourceControllerPullPolicy),
+		withCloneDepth(b.cloneDepth),
+		withPreApplyValidation(b.preApplyValidation),
+		withFluxOIDCProvider(b.fluxOIDCProvider, b.fluxOIDCIdentity),
+		withSandboxedBuild(b.sandboxedBuild),
+		withFluxVPA(b.fluxVPAUpdateMode),
+		withCommitAuthor(b.commitAuthorName, b.commitAuthorEmail),
+		withFluxResources(b.fluxResourceLimits, b.fluxResourceRequests),
+		withAnnotationPropagation(b.annotationPropagation),
+		withSSHKeyPath(b.sshKeyPath),
+		withSSHPassphrase(b.sshPassphrase),
+		withKnownHostsPath(b.sshKnownHostsPath),
+		withFluxAlerts(b.fluxAlerts),
+		withImageAutomation(b.imageAutomation),
+	}
+	if b.scanner != nil {
+		fopts = append(fopts, withVulnerabilityScan(b.scanner, b.maxSeverity))
+	}
+	for _, r := range b.fluxReceivers {
+		fopts = append(fopts, withFluxReceiver(r.Name, r.Type, r.Secret, r.Resources))
+	}
+	if b.logHandler != nil {
+		fopts = append(fopts, withLogger(b.log()))
+	}
+	for comp, interval := range b.componentIntervals {
+		fopts = append(fopts, withComponentInterval(comp, interval))
+	}
+	for controller, annotations := range b.fluxServiceAccountAnnotations {
+		fopts = append(fopts, withFluxServiceAccountAnnotations(controller, annotations))
+	}
+
+	return opts, fopts
 }
 
 func (b *Bootstrap) installCertManager(ctx context.Context, ociRepo om.Repository, ref compdesc.ComponentReference) (string, error) {
@@ -499,6 +1019,7 @@ func (b *Bootstrap) installCertManager(ctx context.Context, ociRepo om.Repositor
 		return "", err
 	}
 	defer os.RemoveAll(dir)
+	defer b.metrics.observeComponent(env.CertManagerName, time.Now())
 
 	opts := &certManagerOptions{
 		gitRepository:         b.repository,
@@ -509,6 +1030,7 @@ func (b *Bootstrap) installCertManager(ctx context.Context, ociRepo om.Repositor
 		provider:              string(b.providerClient.ProviderID()),
 		timeout:               b.timeout,
 		commitMessageAppendix: b.commitMessageAppendix,
+		publicKey:             b.publicKey,
 	}
 
 	inst, err := newCertManagerInstall(ref.GetComponentName(), ref.GetVersion(), ociRepo, opts)
@@ -528,6 +1050,7 @@ func (b *Bootstrap) installExternalSecrets(ctx context.Context, ociRepo om.Repos
 		return "", err
 	}
 	defer os.RemoveAll(dir)
+	defer b.metrics.observeComponent(env.ExternalSecretsName, time.Now())
 
 	opts := &externalSecretOptions{
 		gitRepository:         b.repository,
@@ -538,6 +1061,7 @@ func (b *Bootstrap) installExternalSecrets(ctx context.Context, ociRepo om.Repos
 		provider:              string(b.providerClient.ProviderID()),
 		timeout:               b.timeout,
 		commitMessageAppendix: b.commitMessageAppendix,
+		publicKey:             b.publicKey,
 	}
 
 	inst, err := newExternalSecretInstall(ref.GetComponentName(), ref.GetVersion(), ociRepo, opts)
@@ -560,11 +1084,43 @@ func (b *Bootstrap) fetchBootstrapComponentReferences(ociRepo om.Repository) (ma
 	}
 	defer cv.Close()
 
+	// the references select the versions of all installed components, they are only trusted
+	// if the bootstrap component itself is signed with the configured key
+	if err := verifyComponentVersion(cv, b.publicKey); err != nil {
+		return nil, err
+	}
+
 	return ocm.FetchComponentReferences(cv, b.components)
 }
 
-// reconcileManagementRepository reconciles the management repository. It creates it if it does not exist.
-func (b *Bootstrap) reconcileManagementRepository(ctx context.Context) error {
+// reconcileManagementRepository reconciles the management repository. It creates it if it does not exist
+// and commits the CI config and CODEOWNERS files to the default branch.
+// If the bootstrap lock is enabled, it is acquired here and held when this returns without an error;
+// the callers release it with releaseBootstrapLockOnExit.
+func (b *Bootstrap) reconcileManagementRepository(ctx context.Context) (err error) {
+	if err := b.prepareManagementRepository(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			b.releaseBootstrapLockOnExit(ctx)
+		}
+	}()
+
+	if err := b.reconcileGitHubActions(ctx); err != nil {
+		return err
+	}
+
+	if err := b.reconcileGitLabCI(ctx); err != nil {
+		return err
+	}
+
+	return b.reconcileCodeOwners(ctx, b.repository, b.codeOwners)
+}
+
+// prepareManagementRepository creates the management repository if it does not exist and acquires
+// the bootstrap lock if it is enabled. Nothing is committed to the repository except the lock.
+func (b *Bootstrap) prepareManagementRepository(ctx context.Context) (err error) {
 	repo, err := b.reconcileRepository(ctx, b.personal)
 	if err != nil && !errors.Is(err, errReconciledWithWarning) {
 		return err
@@ -578,6 +1134,25 @@ func (b *Bootstrap) reconcileManagementRepository(ctx context.Context) error {
 	b.repository = repo
 	b.url = cloneURL
 
+	// the lock is acquired as soon as the repository exists, before the bootstrap writes to it
+	if b.bootstrapLock && !b.dryRun {
+		if err := b.acquireBootstrapLock(ctx); err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				b.releaseBootstrapLockOnExit(ctx)
+			}
+		}()
+	}
+
+	// the bootstrap itself pushes over HTTPS with the token, only flux syncs over SSH
+	if b.transportType == string(gitprovider.TransportTypeSSH) {
+		if b.sshURL, err = b.getCloneURL(repo, gitprovider.TransportTypeSSH); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -600,7 +1175,8 @@ func (b *Bootstrap) reconcileRepository(ctx context.Context, personal bool) (git
 		err  error
 	)
 	subOrgs, repoName := splitSubOrganizationsFromRepositoryName(b.repositoryName)
-	if personal {
+	// Azure DevOps repositories always belong to a project
+	if personal && string(b.providerClient.ProviderID()) != env.ProviderAzureDevOps {
 		userRef := newUserRef(b.providerClient.SupportedDomain(), b.owner)
 		repoRef := newUserRepositoryRef(userRef, repoName)
 		repoInfo := newRepositoryInfo(b.description, b.defaultBranch, b.visibility)
@@ -615,6 +1191,9 @@ func (b *Bootstrap) reconcileRepository(ctx context.Context, personal bool) (git
 			}
 		}
 	} else {
+		if err := b.validateSubgroups(subOrgs); err != nil {
+			return nil, err
+		}
 		orgRef, err := b.getOrganization(ctx, subOrgs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to reconcile Git repository %q: %w", b.repositoryName, err)
@@ -636,6 +1215,21 @@ func (b *Bootstrap) reconcileRepository(ctx context.Context, personal bool) (git
 	return repo, nil
 }
 
+// validateSubgroups verifies the number of GitLab subgroups the repository is nested in
+// against the configured subgroup depth. Other providers are not restricted.
+func (b *Bootstrap) validateSubgroups(subOrgs []string) error {
+	if string(b.providerClient.ProviderID()) != env.ProviderGitlab || b.subgroupDepth <= 0 {
+		return nil
+	}
+
+	if len(subOrgs) > b.subgroupDepth {
+		return fmt.Errorf("repository %q is nested in %d subgroups, at most %d are allowed",
+			b.repositoryName, len(subOrgs), b.subgroupDepth)
+	}
+
+	return nil
+}
+
 func (b *Bootstrap) getOrganization(ctx context.Context, subOrgs []string) (*gitprovider.OrganizationRef, error) {
 	return &gitprovider.OrganizationRef{
 		Domain:           b.providerClient.SupportedDomain(),
@@ -652,10 +1246,6 @@ func (b *Bootstrap) getCloneURL(repository gitprovider.UserRepository, transport
 		url = repository.Repository().GetCloneURL(transport)
 	}
 
-	if transport == gitprovider.TransportTypeSSH {
-		return "", fmt.Errorf("SSH transport is not supported")
-	}
-
 	return url, nil
 }
 
@@ -667,10 +1257,9 @@ func (b *Bootstrap) installInfrastructure(ctx context.Context, ociRepo om.Reposi
 
 	if err := b.inSpinner(fmt.Sprintf("Installing %s with version %s",
 		printer.BoldBlue(env.FluxName),
-		printer.BoldBlue(fluxRef.GetVersion())), func() error {
-
+		printer.BoldBlue(fluxRef.GetVersion())), b.trackProgress(ProgressPhaseComponentInstall, env.FluxName, func() error {
 		return b.installFlux(ctx, ociRepo, fluxRef)
-	}); err != nil {
+	})); err != nil {
 		return "", fmt.Errorf("failed to install flux: %w", err)
 	}
 
@@ -687,14 +1276,14 @@ func (b *Bootstrap) installInfrastructure(ctx context.Context, ociRepo om.Reposi
 	)
 	if err := b.inSpinner(fmt.Sprintf("Installing %s with version %s",
 		printer.BoldBlue(env.CertManagerName),
-		printer.BoldBlue(certManagerRef.GetVersion())), func() error {
+		printer.BoldBlue(certManagerRef.GetVersion())), b.trackProgress(ProgressPhaseComponentInstall, env.CertManagerName, func() error {
 		sha, err = b.installCertManager(ctx, ociRepo, certManagerRef)
 		if err != nil {
 			return err
 		}
 
 		return nil
-	}); err != nil {
+	})); err != nil {
 		return "", fmt.Errorf("failed to install cert-manager: %w", err)
 	}
 
@@ -704,36 +1293,60 @@ func (b *Bootstrap) installInfrastructure(ctx context.Context, ociRepo om.Reposi
 }
 
 func (b *Bootstrap) generateControllerManifest(ctx context.Context, ociRepo om.Repository, comp string, ref compdesc.ComponentReference, compNs map[string][]string) (string, error) {
+	ns, deployments, err := b.componentDeployments(comp)
+	if err != nil {
+		return "", err
+	}
+
 	var latestSHA string
 	switch comp {
-	case env.OcmControllerName, env.GitControllerName, env.ReplicationControllerName:
-		sha, err := b.installComponent(ctx, ociRepo, ref, comp, env.DefaultOCMNamespace, compNs)
-		if err != nil {
-			return "", err
-		}
-		latestSHA = sha
-		compNs[env.DefaultOCMNamespace] = append(compNs[env.DefaultOCMNamespace], comp)
-	case env.MpasProductControllerName, env.MpasProjectControllerName:
-		sha, err := b.installComponent(ctx, ociRepo, ref, comp, "mpas-system", compNs)
-		if err != nil {
-			return "", err
-		}
-		latestSHA = sha
-		compNs["mpas-system"] = append(compNs["mpas-system"], comp)
 	case env.ExternalSecretsName:
-		sha, err := b.installExternalSecrets(ctx, ociRepo, ref)
-		if err != nil {
-			return "", err
-		}
-		latestSHA = sha
-		compNs["default"] = append(compNs["default"], externalSecret, externalSecretCertController, externalSecretWebhook)
+		latestSHA, err = b.installExternalSecrets(ctx, ociRepo, ref)
 	default:
-		return "", fmt.Errorf("unknown component %q", comp)
+		latestSHA, err = b.installComponent(ctx, ociRepo, ref, comp, ns, compNs)
 	}
+	if err != nil {
+		return "", err
+	}
+
+	compNs[ns] = append(compNs[ns], deployments...)
 
 	return latestSHA, nil
 }
 
+// componentDeployments returns the namespace and the deployments of the given component.
+// The namespace can be overridden with WithComponentNamespaces.
+func (b *Bootstrap) componentDeployments(comp string) (string, []string, error) {
+	ns, deployments, err := defaultComponentDeployments(comp)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return b.componentNamespace(comp, ns), deployments, nil
+}
+
+// componentNamespace returns the namespace configured for the given component or defaultNamespace.
+func (b *Bootstrap) componentNamespace(comp, defaultNamespace string) string {
+	if ns, ok := b.componentNamespaces[comp]; ok && ns != "" {
+		return ns
+	}
+
+	return defaultNamespace
+}
+
+func defaultComponentDeployments(comp string) (string, []string, error) {
+	switch comp {
+	case env.OcmControllerName, env.GitControllerName, env.ReplicationControllerName:
+		return env.DefaultOCMNamespace, []string{comp}, nil
+	case env.MpasProductControllerName, env.MpasProjectControllerName:
+		return env.DefaultMPASNamespace, []string{comp}, nil
+	case env.ExternalSecretsName:
+		return env.DefaultExternalSecretsNamespace, []string{externalSecret, externalSecretCertController, externalSecretWebhook}, nil
+	default:
+		return "", nil, fmt.Errorf("unknown component %q", comp)
+	}
+}
+
 func (b *Bootstrap) generateCertificateManifests(ctx context.Context) (string, error) {
 	installer := newCertificateManifestInstaller(&certificateManifestOptions{
 		gitRepository:         b.repository,
@@ -812,7 +1425,7 @@ func setDefaults(b *Bootstrap) {
 }
 
 func validateOptions(opts *options) error {
-	if opts.repositoryName == "" {
+	if opts.repositoryName == "" && !opts.clusterOnly {
 		return fmt.Errorf("repository name must be set")
 	}
 
@@ -828,6 +1441,95 @@ func validateOptions(opts *options) error {
 		return fmt.Errorf("printer must be set")
 	}
 
+	if opts.cloneDepth < 0 || opts.cloneDepth > 1 {
+		return fmt.Errorf("clone depth must be 0 or 1, got %d", opts.cloneDepth)
+	}
+
+	if opts.subgroupDepth < 0 {
+		return fmt.Errorf("subgroup depth must not be negative, got %d", opts.subgroupDepth)
+	}
+
+	if opts.fluxVPAUpdateMode != "" {
+		if err := validateVPAUpdateMode(opts.fluxVPAUpdateMode); err != nil {
+			return err
+		}
+	}
+
+	if opts.fluxOIDCProvider != "" {
+		if _, err := oidcServiceAccountAnnotation(opts.fluxOIDCProvider); err != nil {
+			return err
+		}
+	}
+
+	if opts.maxSeverity != "" && severityRank(opts.maxSeverity) < 0 {
+		return fmt.Errorf("unknown severity %q, must be one of %s", opts.maxSeverity, strings.Join(severities, ", "))
+	}
+
+	for _, r := range opts.fluxReceivers {
+		if r.Name == "" || r.Type == "" || r.Secret == "" {
+			return fmt.Errorf("flux receiver name, type and secret must be set")
+		}
+	}
+
+	for _, a := range opts.fluxAlerts {
+		if !slices.Contains(alertProviders, a.Provider) {
+			return fmt.Errorf("unknown flux alert provider %q, must be one of %s", a.Provider, strings.Join(alertProviders, ", "))
+		}
+		if a.Address == "" {
+			return fmt.Errorf("flux alert address must be set")
+		}
+	}
+
+	if opts.gitlabCI != nil && opts.gitlabCI.Image == "" {
+		return fmt.Errorf("gitlab ci image must be set")
+	}
+
+	if opts.githubActions != nil && opts.githubActions.Image == "" {
+		return fmt.Errorf("github actions image must be set")
+	}
+
+	if opts.minKubernetesVersion != "" {
+		if _, err := semver.NewConstraint(opts.minKubernetesVersion); err != nil {
+			return fmt.Errorf("invalid minimum Kubernetes version %q: %w", opts.minKubernetesVersion, err)
+		}
+	}
+
+	if opts.branchProtection != nil && opts.branchProtection.RequiredApprovals < 0 {
+		return fmt.Errorf("required approvals must not be negative")
+	}
+
+	if opts.skipCompletedPhases && opts.clusterOnly {
+		return fmt.Errorf("skipping completed phases requires the state file in the management repository, it cannot be used with cluster only")
+	}
+
+	if opts.bootstrapLock && opts.clusterOnly {
+		return fmt.Errorf("the bootstrap lock requires a management repository, it cannot be used with cluster only")
+	}
+
+	if opts.bootstrapLockTTL < 0 {
+		return fmt.Errorf("the bootstrap lock TTL must not be negative")
+	}
+
+	if opts.transportType == sshTransport && opts.sshKeyPath == "" {
+		return fmt.Errorf("an SSH key must be set for the ssh transport")
+	}
+
+	if opts.profile != "" {
+		if err := validateProfile(opts); err != nil {
+			return err
+		}
+	}
+
+	if opts.versionFilter != "" {
+		if _, err := parseVersionFilter(opts.versionFilter); err != nil {
+			return err
+		}
+	}
+
+	if opts.lockConfigMapName != "" && opts.lockConfigMapNamespace == "" {
+		return fmt.Errorf("lock ConfigMap namespace must be set")
+	}
+
 	return nil
 }
 
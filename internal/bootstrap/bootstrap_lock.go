@@ -0,0 +1,275 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// bootstrapLockFileName is the name of the lock file in the root of the management repository.
+	bootstrapLockFileName = "bootstrap.lock"
+	// defaultLockTTL is the age after which a lock is stale, e.g. because its bootstrap run crashed,
+	// and is taken over by the next run. It is used if WithBootstrapLockTTL is not set.
+	defaultLockTTL = 30 * time.Minute
+)
+
+// ErrBootstrapLocked is returned if another bootstrap run holds the lock of the management repository.
+var ErrBootstrapLocked = errors.New("management repository is locked by another bootstrap run")
+
+// bootstrapLock is the content of the lock file.
+type bootstrapLock struct {
+	// Holder identifies the bootstrap run holding the lock.
+	Holder string `json:"holder"`
+	// Timestamp is the time the lock was acquired.
+	Timestamp time.Time `json:"timestamp"`
+	// TTL is the age after which the lock is stale. Locks without a TTL use defaultLockTTL.
+	TTL metav1.Duration `json:"ttl,omitempty"`
+}
+
+// stale returns true if the lock is older than its TTL.
+func (l *bootstrapLock) stale(now time.Time) bool {
+	ttl := l.TTL.Duration
+	if ttl <= 0 {
+		ttl = defaultLockTTL
+	}
+	return now.Sub(l.Timestamp) > ttl
+}
+
+// WithBootstrapLock commits a lock file to the management repository before the bootstrap writes
+// to it and removes it when the bootstrap completes. A concurrent bootstrap run against the same
+// repository fails with ErrBootstrapLocked instead of creating conflicting commits.
+func WithBootstrapLock(enabled bool) Option {
+	return func(o *options) {
+		o.bootstrapLock = enabled
+	}
+}
+
+// WithBootstrapLockTTL sets the age after which the bootstrap lock is stale and taken over by
+// the next run. It must exceed the duration of the longest bootstrap run, defaults to 30 minutes.
+func WithBootstrapLockTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.bootstrapLockTTL = ttl
+	}
+}
+
+// acquireBootstrapLock commits the lock file on top of the current HEAD of the management repository.
+// The push is rejected if HEAD moved in the meantime, e.g. because another run committed its lock,
+// so that only one of the concurrent runs acquires the lock.
+func (b *Bootstrap) acquireBootstrapLock(ctx context.Context) error {
+	dir, err := mkdirTempDir("bootstrap-lock")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	repo, auth, err := b.cloneManagementRepository(ctx, dir)
+	if err != nil {
+		return err
+	}
+
+	held, err := readBootstrapLock(dir)
+	if err != nil {
+		return err
+	}
+	if err := checkBootstrapLock(held); err != nil {
+		return err
+	}
+
+	holder, err := newLockHolder()
+	if err != nil {
+		return err
+	}
+	lock := &bootstrapLock{Holder: holder, Timestamp: time.Now().UTC(), TTL: metav1.Duration{Duration: b.bootstrapLockTTL}}
+	data, err := json.Marshal(lock)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bootstrap lock: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, bootstrapLockFileName), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write bootstrap lock: %w", err)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if _, err := w.Add(bootstrapLockFileName); err != nil {
+		return fmt.Errorf("failed to add bootstrap lock: %w", err)
+	}
+	if err := b.commitBootstrapLock(w, "Lock management repository for bootstrap"); err != nil {
+		return err
+	}
+
+	if err := repo.PushContext(ctx, &gogit.PushOptions{Auth: auth}); err != nil {
+		// the push of a concurrent run won, report its lock instead of the rejected push
+		if held, readErr := b.readRemoteBootstrapLock(ctx); readErr == nil {
+			if lockErr := checkBootstrapLock(held); lockErr != nil {
+				return lockErr
+			}
+		}
+		if errors.Is(err, gogit.ErrNonFastForwardUpdate) {
+			return fmt.Errorf("%w: the repository changed while acquiring the lock", ErrBootstrapLocked)
+		}
+		return fmt.Errorf("failed to push bootstrap lock: %w", err)
+	}
+
+	b.lockHolder = holder
+	return nil
+}
+
+// releaseBootstrapLock removes the lock file from the management repository. Nothing is done if
+// the lock is not held by this run, e.g. because it became stale and was taken over.
+func (b *Bootstrap) releaseBootstrapLock(ctx context.Context) error {
+	if b.lockHolder == "" {
+		return nil
+	}
+
+	dir, err := mkdirTempDir("bootstrap-lock")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	repo, auth, err := b.cloneManagementRepository(ctx, dir)
+	if err != nil {
+		return err
+	}
+
+	held, err := readBootstrapLock(dir)
+	if err != nil {
+		return err
+	}
+	if held == nil || held.Holder != b.lockHolder {
+		b.lockHolder = ""
+		return nil
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if _, err := w.Remove(bootstrapLockFileName); err != nil {
+		return fmt.Errorf("failed to remove bootstrap lock: %w", err)
+	}
+	if err := b.commitBootstrapLock(w, "Unlock management repository after bootstrap"); err != nil {
+		return err
+	}
+	if err := repo.PushContext(ctx, &gogit.PushOptions{Auth: auth}); err != nil {
+		return fmt.Errorf("failed to push bootstrap lock removal: %w", err)
+	}
+
+	b.lockHolder = ""
+	return nil
+}
+
+// releaseBootstrapLockOnExit releases the lock, logging instead of returning a failure,
+// as it runs after the bootstrap result is known. A lock that is not released becomes stale after its TTL.
+func (b *Bootstrap) releaseBootstrapLockOnExit(ctx context.Context) {
+	if err := b.releaseBootstrapLock(ctx); err != nil {
+		b.log().WarnContext(ctx, "Failed to release bootstrap lock", slog.Any("error", err))
+	}
+}
+
+// commitBootstrapLock commits the staged lock file changes with the given message. The commit
+// has the author and signing key of the component commits, so that branch protection rules
+// requiring signed commits accept it.
+func (b *Bootstrap) commitBootstrapLock(w *gogit.Worktree, msg string) error {
+	if b.commitMessageAppendix != "" {
+		msg = msg + "\n\n" + b.commitMessageAppendix
+	}
+
+	author := &object.Signature{Name: b.commitAuthorName, Email: b.commitAuthorEmail, When: time.Now()}
+	if author.Name == "" {
+		author.Name = defaultCommitAuthorName
+	}
+	if author.Email == "" {
+		author.Email = defaultCommitAuthorEmail
+	}
+	opts := &gogit.CommitOptions{Author: author}
+	if b.signingKeyPath != "" {
+		signer, err := loadSigningEntity(b.signingKeyPath, b.signingKeyPassphrase)
+		if err != nil {
+			return err
+		}
+		opts.SignKey = signer
+	}
+
+	if _, err := w.Commit(msg, opts); err != nil {
+		return fmt.Errorf("failed to commit bootstrap lock: %w", err)
+	}
+
+	return nil
+}
+
+// readRemoteBootstrapLock returns the lock file at the HEAD of the management repository.
+func (b *Bootstrap) readRemoteBootstrapLock(ctx context.Context) (*bootstrapLock, error) {
+	dir, err := mkdirTempDir("bootstrap-lock")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if _, _, err := b.cloneManagementRepository(ctx, dir); err != nil {
+		return nil, err
+	}
+
+	return readBootstrapLock(dir)
+}
+
+// readBootstrapLock reads the lock file from the repository checked out in dir.
+// It returns nil if there is no lock file.
+func readBootstrapLock(dir string) (*bootstrapLock, error) {
+	data, err := os.ReadFile(filepath.Join(dir, bootstrapLockFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read bootstrap lock: %w", err)
+	}
+
+	lock := &bootstrapLock{}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse bootstrap lock: %w", err)
+	}
+
+	return lock, nil
+}
+
+// checkBootstrapLock returns ErrBootstrapLocked if the lock is held and not stale.
+func checkBootstrapLock(lock *bootstrapLock) error {
+	if lock == nil || lock.stale(time.Now()) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: held by %s since %s", ErrBootstrapLocked, lock.Holder, lock.Timestamp.Format(time.RFC3339))
+}
+
+// newLockHolder returns an identifier of this bootstrap run, unique across the runs of a host.
+func newLockHolder() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate lock holder id: %w", err)
+	}
+
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(id)), nil
+}
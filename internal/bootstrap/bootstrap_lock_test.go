@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newLockingBootstrap returns a Bootstrap locking the management repository at url.
+func newLockingBootstrap(t *testing.T, url string) *Bootstrap {
+	p, err := printer.Newprinter(io.Discard)
+	require.NoError(t, err)
+
+	b := &Bootstrap{
+		repository: &mockGitRepository{},
+		url:        url,
+		options: options{
+			defaultBranch: "main",
+			printer:       p,
+		},
+	}
+	WithBootstrapLock(true)(&b.options)
+	return b
+}
+
+// remoteBootstrapLock returns the lock file at the HEAD of main of the bare repository, nil if there is none.
+func remoteBootstrapLock(t *testing.T, bare string) *bootstrapLock {
+	repo, err := gogit.PlainOpen(bare)
+	require.NoError(t, err)
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName("main"), true)
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(ref.Hash())
+	require.NoError(t, err)
+
+	file, err := commit.File(bootstrapLockFileName)
+	if err != nil {
+		return nil
+	}
+	content, err := file.Contents()
+	require.NoError(t, err)
+
+	lock := &bootstrapLock{}
+	require.NoError(t, json.Unmarshal([]byte(content), lock))
+	return lock
+}
+
+func TestBootstrapLockRace(t *testing.T) {
+	bare := newBareRepository(t, 1)
+	runs := []*Bootstrap{newLockingBootstrap(t, bare), newLockingBootstrap(t, bare)}
+
+	errs := make([]error, len(runs))
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, b := range runs {
+		i, b := i, b
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = b.acquireBootstrapLock(context.Background())
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	var winner, loser *Bootstrap
+	for i, err := range errs {
+		if err == nil {
+			require.Nil(t, winner, "both runs acquired the lock")
+			winner = runs[i]
+			continue
+		}
+		assert.ErrorIs(t, err, ErrBootstrapLocked)
+		loser = runs[i]
+	}
+	require.NotNil(t, winner, "no run acquired the lock")
+	require.NotNil(t, loser, "both runs acquired the lock")
+
+	lock := remoteBootstrapLock(t, bare)
+	require.NotNil(t, lock)
+	assert.Equal(t, winner.lockHolder, lock.Holder)
+	assert.Empty(t, loser.lockHolder)
+
+	// the loser does not remove the lock it does not hold
+	require.NoError(t, loser.releaseBootstrapLock(context.Background()))
+	assert.NotNil(t, remoteBootstrapLock(t, bare))
+
+	require.NoError(t, winner.releaseBootstrapLock(context.Background()))
+	assert.Nil(t, remoteBootstrapLock(t, bare))
+	assert.Empty(t, winner.lockHolder)
+
+	// the lock is free again once it is released
+	require.NoError(t, loser.acquireBootstrapLock(context.Background()))
+	require.NoError(t, loser.releaseBootstrapLock(context.Background()))
+}
+
+func TestCheckBootstrapLock(t *testing.T) {
+	assert.NoError(t, checkBootstrapLock(nil))
+
+	held := &bootstrapLock{Holder: "ci-1234", Timestamp: time.Now().Add(-time.Minute)}
+	err := checkBootstrapLock(held)
+	assert.ErrorIs(t, err, ErrBootstrapLocked)
+	assert.ErrorContains(t, err, "held by ci-1234")
+
+	stale := &bootstrapLock{Holder: "ci-1234", Timestamp: time.Now().Add(-defaultLockTTL - time.Minute)}
+	assert.NoError(t, checkBootstrapLock(stale))
+
+	// the TTL of the lock overrides the default
+	long := &bootstrapLock{Holder: "ci-1234", Timestamp: stale.Timestamp, TTL: metav1.Duration{Duration: 2 * time.Hour}}
+	assert.ErrorIs(t, checkBootstrapLock(long), ErrBootstrapLocked)
+}
+
+func TestBootstrapLockCommitAuthor(t *testing.T) {
+	bare := newBareRepository(t, 1)
+	b := newLockingBootstrap(t, bare)
+	WithCommitAuthor("release-bot", "release-bot@example.com")(&b.options)
+	WithBootstrapLockTTL(time.Hour)(&b.options)
+
+	require.NoError(t, b.acquireBootstrapLock(context.Background()))
+	lock := remoteBootstrapLock(t, bare)
+	require.NotNil(t, lock)
+	assert.Equal(t, time.Hour, lock.TTL.Duration)
+
+	repo, err := gogit.PlainOpen(bare)
+	require.NoError(t, err)
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName("main"), true)
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(ref.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, "release-bot", commit.Author.Name)
+	assert.Equal(t, "release-bot@example.com", commit.Author.Email)
+
+	require.NoError(t, b.releaseBootstrapLock(context.Background()))
+}
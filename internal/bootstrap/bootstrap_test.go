@@ -5,10 +5,17 @@
 package bootstrap
 
 import (
+	"bytes"
+	"context"
 	"testing"
 
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	cfd "github.com/open-component-model/ocm-controller/pkg/configdata"
 	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_GetOrderedKeys(t *testing.T) {
@@ -65,3 +72,151 @@ func Test_GetOrderedKeys(t *testing.T) {
 		})
 	}
 }
+
+func Test_ListAvailableProviders(t *testing.T) {
+	testCases := []struct {
+		name           string
+		providerClient gitprovider.Client
+		expected       []string
+	}{
+		{
+			name:     "no provider client",
+			expected: []string{"gitea", "github", "gitlab"},
+		},
+		{
+			name:           "configured provider is listed first",
+			providerClient: &mockProviderClient{providerID: "gitlab"},
+			expected:       []string{"gitlab", "gitea", "github"},
+		},
+		{
+			name:           "configured provider is not registered",
+			providerClient: &mockProviderClient{providerID: "custom"},
+			expected:       []string{"custom", "gitea", "github", "gitlab"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &Bootstrap{providerClient: tc.providerClient}
+			assert.Equal(t, tc.expected, b.ListAvailableProviders())
+		})
+	}
+}
+
+func Test_ComponentNamespaces(t *testing.T) {
+	b := &Bootstrap{}
+	WithComponentNamespaces(map[string]string{
+		"flux":           "gitops",
+		"ocm-controller": "ocm",
+	})(&b.options)
+
+	ns, deployments, err := b.componentDeployments("ocm-controller")
+	require.NoError(t, err)
+	assert.Equal(t, "ocm", ns)
+	assert.Equal(t, []string{"ocm-controller"}, deployments)
+
+	ns, _, err = b.componentDeployments("git-controller")
+	require.NoError(t, err)
+	assert.Equal(t, "ocm-system", ns, "components absent from the map use the default namespace")
+
+	opts, _ := b.newFluxOptions(t.TempDir(), nil)
+	assert.Equal(t, "gitops", opts.namespace)
+
+	f := &fluxInstall{fluxOptions: opts}
+	kfile, kus, err := f.generateKustomization(bytes.NewReader(kustomizedDeployment))
+	require.NoError(t, err)
+	res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+	require.NoError(t, err)
+	assert.Contains(t, string(res), "namespace: gitops")
+	assert.NotContains(t, string(res), "namespace: ocm-system")
+}
+
+func Test_GetKubernetesClient(t *testing.T) {
+	kubeClient := fake.NewClientBuilder().Build()
+	restClientGetter := genericclioptions.NewConfigFlags(false)
+
+	b := &Bootstrap{}
+	assert.Nil(t, b.GetKubernetesClient())
+	assert.Nil(t, b.GetRESTClientGetter())
+
+	WithKubeClient(kubeClient)(&b.options)
+	WithRESTClientGetter(restClientGetter)(&b.options)
+	assert.Same(t, kubeClient, b.GetKubernetesClient())
+	assert.Same(t, restClientGetter, b.GetRESTClientGetter())
+}
+
+func Test_ReconcileRepositorySubgroups(t *testing.T) {
+	testCases := []struct {
+		name           string
+		providerID     gitprovider.ProviderID
+		domain         string
+		repositoryName string
+		subgroupDepth  int
+		expected       gitprovider.OrgRepositoryRef
+		expectedErr    string
+	}{
+		{
+			name:           "gitlab subgroup",
+			providerID:     "gitlab",
+			domain:         "gitlab.com",
+			repositoryName: "platform/mpas",
+			expected: gitprovider.OrgRepositoryRef{
+				OrganizationRef: gitprovider.OrganizationRef{Domain: "gitlab.com", Organization: "owner", SubOrganizations: []string{"platform"}},
+				RepositoryName:  "mpas",
+			},
+		},
+		{
+			name:           "gitlab nested subgroups within depth",
+			providerID:     "gitlab",
+			domain:         "gitlab.com",
+			repositoryName: "platform/teams/mpas",
+			subgroupDepth:  2,
+			expected: gitprovider.OrgRepositoryRef{
+				OrganizationRef: gitprovider.OrganizationRef{Domain: "gitlab.com", Organization: "owner", SubOrganizations: []string{"platform", "teams"}},
+				RepositoryName:  "mpas",
+			},
+		},
+		{
+			name:           "gitlab subgroups exceed depth",
+			providerID:     "gitlab",
+			domain:         "gitlab.com",
+			repositoryName: "platform/teams/mpas",
+			subgroupDepth:  1,
+			expectedErr:    `repository "platform/teams/mpas" is nested in 2 subgroups, at most 1 are allowed`,
+		},
+		{
+			name:           "github repository",
+			providerID:     "github",
+			domain:         "github.com",
+			repositoryName: "mpas",
+			subgroupDepth:  1,
+			expected: gitprovider.OrgRepositoryRef{
+				OrganizationRef: gitprovider.OrganizationRef{Domain: "github.com", Organization: "owner"},
+				RepositoryName:  "mpas",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			orgRepositories := &mockOrgRepositoriesClient{}
+			b := &Bootstrap{
+				providerClient: &mockProviderClient{providerID: tc.providerID, domain: tc.domain, orgRepositories: orgRepositories},
+				options: options{
+					owner:          "owner",
+					repositoryName: tc.repositoryName,
+					subgroupDepth:  tc.subgroupDepth,
+				},
+			}
+
+			_, err := b.reconcileRepository(context.Background(), false)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Empty(t, orgRepositories.refs)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []gitprovider.OrgRepositoryRef{tc.expected}, orgRepositories.refs)
+		})
+	}
+}
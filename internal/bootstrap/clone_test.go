@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/gogit"
+	gogitv5 "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCloneRepositoryShallow(t *testing.T) {
+	bare := newBareRepository(t, 5)
+	remote, err := gogitv5.PlainOpen(bare)
+	require.NoError(t, err)
+	main, err := remote.Reference(plumbing.NewBranchReferenceName("main"), true)
+	require.NoError(t, err)
+
+	gitClient, err := gogit.NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP}, gogit.WithDiskStorage())
+	require.NoError(t, err)
+
+	f := &fluxInstall{
+		version: "v2.0.0",
+		fluxOptions: &fluxOptions{
+			gitClient: gitClient,
+			url:       bare,
+			branch:    "main",
+		},
+	}
+	withCloneDepth(1)(f.fluxOptions)
+	require.NoError(t, f.cloneRepository(context.Background()))
+
+	head, err := gitClient.Head()
+	require.NoError(t, err)
+	assert.Equal(t, main.Hash().String(), head)
+
+	// only the HEAD commit is fetched
+	clone, err := gogitv5.PlainOpen(gitClient.Path())
+	require.NoError(t, err)
+	assert.Len(t, historyOf(t, clone), 1)
+	_, err = os.Stat(filepath.Join(gitClient.Path(), ".git", "shallow"))
+	require.NoError(t, err)
+
+	// new commits are pushed on top of the remote history
+	require.NoError(t, f.commitAndPushComponents(context.Background(), "flux-system/gotk-components.yaml", "content"))
+	assert.Len(t, historyOf(t, remote), 6)
+}
+
+func TestValidateCloneDepth(t *testing.T) {
+	p, err := printer.Newprinter(io.Discard)
+	require.NoError(t, err)
+
+	for depth, valid := range map[int]bool{-1: false, 0: true, 1: true, 2: false} {
+		err := validateOptions(&options{
+			repositoryName:   "mpas",
+			restClientGetter: genericclioptions.NewConfigFlags(false),
+			kubeclient:       fake.NewClientBuilder().Build(),
+			printer:          p,
+			cloneDepth:       depth,
+		})
+		if valid {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.EqualError(t, err, fmt.Sprintf("clone depth must be 0 or 1, got %d", depth))
+	}
+}
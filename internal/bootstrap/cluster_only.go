@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/open-component-model/mpas/internal/printer"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// applyFunc applies the manifest at manifestPath to the cluster, see kubeutils.Apply.
+type applyFunc func(ctx context.Context, rcg genericclioptions.RESTClientGetter, root, manifestPath string) (string, error)
+
+// apply applies the manifest with the applier, or with kubeutils.Apply if none is set.
+func (a applyFunc) apply(ctx context.Context, rcg genericclioptions.RESTClientGetter, root, manifestPath string) (string, error) {
+	if a == nil {
+		return kubeutils.Apply(ctx, rcg, root, manifestPath)
+	}
+	return a(ctx, rcg, root, manifestPath)
+}
+
+// WithClusterOnlyMode enables the cluster-only mode. In cluster-only mode no management repository is used,
+// all git operations are skipped and the generated manifests are applied directly to the cluster.
+func WithClusterOnlyMode(clusterOnly bool) Option {
+	return func(o *options) {
+		o.clusterOnly = clusterOnly
+	}
+}
+
+// runClusterOnly installs all bootstrap components by applying their manifests directly to the cluster.
+func (b *Bootstrap) runClusterOnly(ctx context.Context, ociRepo om.Repository, refs map[string]compdesc.ComponentReference) error {
+	fluxRef, ok := refs[env.FluxName]
+	if !ok {
+		return fmt.Errorf("flux component not found")
+	}
+
+	if err := b.inSpinner(fmt.Sprintf("Installing %s with version %s",
+		printer.BoldBlue(env.FluxName),
+		printer.BoldBlue(fluxRef.GetVersion())), b.trackProgress(ProgressPhaseComponentInstall, env.FluxName, func() error {
+		return b.installFlux(ctx, ociRepo, fluxRef)
+	})); err != nil {
+		return fmt.Errorf("failed to install flux: %w", err)
+	}
+
+	certManagerRef, ok := refs[env.CertManagerName]
+	if !ok {
+		return fmt.Errorf("cert-manager component not found")
+	}
+
+	if err := b.inSpinner(fmt.Sprintf("Installing %s with version %s",
+		printer.BoldBlue(env.CertManagerName),
+		printer.BoldBlue(certManagerRef.GetVersion())), b.trackProgress(ProgressPhaseComponentInstall, env.CertManagerName, func() error {
+		if err := b.applyComponent(ctx, ociRepo, env.CertManagerName, certManagerRef); err != nil {
+			return err
+		}
+
+		return kubeutils.ReportComponentsHealth(ctx, b.restClientGetter, b.timeout, []string{
+			certManager,
+			certManagerCAInjector,
+			certManagerWebhook,
+		}, env.DefaultCertManagerNamespace)
+	})); err != nil {
+		return fmt.Errorf("failed to install cert-manager: %w", err)
+	}
+
+	comps, err := sortComponents(refs)
+	if err != nil {
+		return err
+	}
+
+	compNs := make(map[string][]string)
+	for _, comp := range comps {
+		if comp == env.FluxName || comp == env.CertManagerName {
+			continue
+		}
+
+		ref := refs[comp]
+		ns, deployments, err := b.componentDeployments(comp)
+		if err != nil {
+			return err
+		}
+
+		if err := b.inSpinner(fmt.Sprintf("Installing %s with version %s",
+			printer.BoldBlue(comp),
+			printer.BoldBlue(ref.GetVersion())), b.trackProgress(ProgressPhaseComponentInstall, comp, func() error {
+			return b.applyComponent(ctx, ociRepo, comp, ref)
+		})); err != nil {
+			return fmt.Errorf("failed to install %s: %w", comp, err)
+		}
+
+		compNs[ns] = append(compNs[ns], deployments...)
+	}
+
+	if err := b.inSpinner("Applying certificate manifests", func() error {
+		for name, data := range map[string][]byte{
+			"cluster_issuer":   clusterIssuer,
+			"ocm_certificate":  ocmCertificate,
+			"mpas_certificate": mpasCertificate,
+		} {
+			if err := b.applyManifest(ctx, name, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to apply certificate manifests: %w", err)
+	}
+
+	if err := b.inSpinner("Waiting for components to be ready", func() error {
+		for ns, comps := range compNs {
+			if err := b.trackProgress(ProgressPhaseHealthCheck, ns, func() error {
+				return kubeutils.ReportComponentsHealth(ctx, b.restClientGetter, b.timeout, comps, ns)
+			})(); err != nil {
+				return fmt.Errorf("failed to report health, please try again in a few minutes: %w", err)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to wait for components to be ready: %w", err)
+	}
+
+	if err := b.lockComponentVersions(ctx, refs); err != nil {
+		return err
+	}
+
+	if err := b.runPostInstallHooks(ctx); err != nil {
+		return err
+	}
+
+	b.logCompleted(ctx, "Bootstrap completed successfully!")
+	b.emit(ProgressEvent{Phase: ProgressPhaseComplete, Message: "bootstrap completed successfully"})
+
+	return nil
+}
+
+func (b *Bootstrap) applyComponent(ctx context.Context, ociRepo om.Repository, comp string, ref compdesc.ComponentReference) error {
+	data, err := b.generateComponentManifest(ctx, ociRepo, comp, ref)
+	if err != nil {
+		return fmt.Errorf("failed to generate manifest: %w", err)
+	}
+
+	return b.applyManifest(ctx, comp, data)
+}
+
+func (b *Bootstrap) applyManifest(ctx context.Context, name string, data []byte) error {
+	dir, err := mkdirTempDir(fmt.Sprintf("%s-apply", name))
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	return applyManifest(ctx, b.applier, b.restClientGetter, dir, fmt.Sprintf("%s.yaml", name), data)
+}
+
+// applyManifest writes the given manifest to dir and applies it to the cluster with applier.
+func applyManifest(ctx context.Context, applier applyFunc, rcg genericclioptions.RESTClientGetter, dir, name string, data []byte) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	if _, err := applier.apply(ctx, rcg, dir, path); err != nil {
+		return fmt.Errorf("failed to apply %s: %w", name, err)
+	}
+
+	return nil
+}
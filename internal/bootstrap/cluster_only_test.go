@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRunClusterOnlyDoesNotUseGitProvider(t *testing.T) {
+	p, err := printer.Newprinter(io.Discard)
+	require.NoError(t, err)
+
+	// the registry is reachable, but serves no components
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	dockerConfig := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(dockerConfig, []byte(`{"auths": {}}`), 0o600))
+
+	// the component versions are read from the lock file to get as far as installing flux
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+	kubeclient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "mpas-lock", Namespace: "mpas-system"},
+		Data: map[string]string{lockFileName: `components:
+- name: flux
+  componentName: ocm.software/mpas/flux
+  version: v2.0.0
+`},
+	}).Build()
+
+	var applied []string
+	providerClient := &recordingProviderClient{}
+	b, err := New(providerClient,
+		WithClusterOnlyMode(true),
+		WithSkipPreflightChecks(true),
+		WithComponents([]string{env.FluxName}),
+		WithEnforceLock(true),
+		WithLockConfigMap("mpas-lock", "mpas-system"),
+		WithRegistry(server.URL+"/mpas"),
+		WithDockerConfigPath(dockerConfig),
+		WithKubeClient(kubeclient),
+		WithRESTClientGetter(genericclioptions.NewConfigFlags(false)),
+		WithPrinter(p),
+	)
+	require.NoError(t, err)
+	b.applier = func(_ context.Context, _ genericclioptions.RESTClientGetter, _, manifestPath string) (string, error) {
+		applied = append(applied, filepath.Base(manifestPath))
+		return "", nil
+	}
+
+	err = b.Run(context.Background())
+	require.ErrorContains(t, err, "failed to install flux")
+	assert.Empty(t, providerClient.calls, "the git provider must not be used in cluster-only mode")
+	assert.Nil(t, b.repository)
+	assert.Empty(t, b.url)
+	assert.Empty(t, applied)
+}
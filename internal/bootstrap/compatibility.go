@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	metav1 "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/meta/v1"
+	"k8s.io/client-go/discovery"
+)
+
+// minKubernetesVersionLabel is the component descriptor label holding the minimum
+// Kubernetes version required by the component, e.g. 1.26.0.
+const minKubernetesVersionLabel = "mpas.ocm.software/min-kubernetes-version"
+
+// VerifyClusterCompatibility verifies that the cluster's Kubernetes version is at least the
+// minimum version required by the given component version. Components without a minimum
+// version label are compatible with any cluster.
+func (b *Bootstrap) VerifyClusterCompatibility(ctx context.Context, componentName, componentVersion string) error {
+	ociRepo, err := b.makeOCIRepositoryWithFallback(ctx, om.DefaultContext())
+	if err != nil {
+		return err
+	}
+	defer ociRepo.Close()
+
+	cv, err := getComponentVersion(ociRepo, componentName, componentVersion)
+	if err != nil {
+		return fmt.Errorf("failed to get component version: %w", err)
+	}
+	defer cv.Close()
+
+	dc, err := b.restClientGetter.ToDiscoveryClient()
+	if err != nil {
+		return fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	if err := checkMinKubernetesVersion(dc, cv.GetDescriptor().Labels); err != nil {
+		return fmt.Errorf("component %s:%s is not compatible with the cluster: %w", componentName, cv.GetVersion(), err)
+	}
+
+	return nil
+}
+
+// checkMinKubernetesVersion verifies the cluster version against the minimum version label.
+func checkMinKubernetesVersion(dc discovery.ServerVersionInterface, labels metav1.Labels) error {
+	var minVersion string
+	ok, err := labels.GetValue(minKubernetesVersionLabel, &minVersion)
+	if err != nil {
+		return fmt.Errorf("failed to read label %s: %w", minKubernetesVersionLabel, err)
+	}
+	if !ok {
+		return nil
+	}
+
+	required, err := semver.NewVersion(minVersion)
+	if err != nil {
+		return fmt.Errorf("failed to parse minimum Kubernetes version %q: %w", minVersion, err)
+	}
+
+	v, err := serverVersion(dc)
+	if err != nil {
+		return err
+	}
+
+	// ignore pre-release and build metadata of the cluster version, e.g. v1.27.3-gke.100
+	release, err := semver.NewVersion(fmt.Sprintf("%d.%d.%d", v.Major(), v.Minor(), v.Patch()))
+	if err != nil {
+		return err
+	}
+
+	if release.LessThan(required) {
+		return fmt.Errorf("kubernetes version %s is older than the required minimum version %s", v.Original(), minVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"testing"
+
+	metav1 "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/meta/v1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestCheckMinKubernetesVersion(t *testing.T) {
+	testCases := []struct {
+		name        string
+		gitVersion  string
+		minVersion  string
+		expectedErr string
+	}{
+		{
+			name:       "cluster meets the minimum version",
+			gitVersion: "v1.27.3",
+			minVersion: "1.26.0",
+		},
+		{
+			name:       "cluster version with vendor suffix",
+			gitVersion: "v1.26.0-gke.100",
+			minVersion: "1.26.0",
+		},
+		{
+			name:       "no minimum version",
+			gitVersion: "v1.20.0",
+		},
+		{
+			name:        "cluster too old",
+			gitVersion:  "v1.25.9",
+			minVersion:  "1.26.0",
+			expectedErr: "kubernetes version v1.25.9 is older than the required minimum version 1.26.0",
+		},
+		{
+			name:        "invalid minimum version",
+			gitVersion:  "v1.27.3",
+			minVersion:  "latest",
+			expectedErr: "failed to parse minimum Kubernetes version",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var labels metav1.Labels
+			if tc.minVersion != "" {
+				require.NoError(t, labels.Set(minKubernetesVersionLabel, tc.minVersion))
+			}
+			dc := &fakediscovery.FakeDiscovery{
+				Fake:               &clienttesting.Fake{},
+				FakedServerVersion: &version.Info{GitVersion: tc.gitVersion},
+			}
+
+			err := checkMinKubernetesVersion(dc, labels)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/utils"
+)
+
+// WithVersionFilter limits the versions returned by ListComponentVersions to those
+// matching the semver constraint, e.g. ">=v0.2.0".
+func WithVersionFilter(constraint string) Option {
+	return func(o *options) {
+		o.versionFilter = constraint
+	}
+}
+
+// ListComponentVersions lists the versions of the OCM component with the given name, e.g.
+// ocm.software/mpas/ocm-controller, available in the registry without installing anything.
+// The versions are sorted with the latest version first.
+func (b *Bootstrap) ListComponentVersions(ctx context.Context, component string) ([]string, error) {
+	octx := om.DefaultContext()
+	if _, err := utils.Configure(octx, ""); err != nil {
+		return nil, fmt.Errorf("failed to configure ocm context: %w", err)
+	}
+	octx.LoggingContext().SetDefaultLevel(1)
+
+	ociRepo, err := b.makeOCIRepositoryWithFallback(ctx, octx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository: %w", err)
+	}
+	defer ociRepo.Close()
+
+	return listComponentVersions(ociRepo, component, b.versionFilter)
+}
+
+// listComponentVersions returns the versions of the component in descending semver order.
+// Only versions matching the constraint are returned if it is set.
+func listComponentVersions(repository om.Repository, component, constraint string) ([]string, error) {
+	var filter *semver.Constraints
+	if constraint != "" {
+		var err error
+		if filter, err = parseVersionFilter(constraint); err != nil {
+			return nil, err
+		}
+	}
+
+	c, err := repository.LookupComponent(component)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up component %s: %w", component, err)
+	}
+
+	vnames, err := c.ListVersions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of component %s: %w", component, err)
+	}
+
+	versions := make(semver.Collection, 0, len(vnames))
+	for _, vname := range vnames {
+		v, err := semver.NewVersion(vname)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q of component %s: %w", vname, component, err)
+		}
+		if filter == nil || filter.Check(v) {
+			versions = append(versions, v)
+		}
+	}
+	sort.Sort(sort.Reverse(versions))
+
+	result := make([]string, 0, len(versions))
+	for _, v := range versions {
+		result = append(result, v.Original())
+	}
+
+	return result, nil
+}
+
+// parseVersionFilter parses the semver constraint of a version filter.
+func parseVersionFilter(constraint string) (*semver.Constraints, error) {
+	filter, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version filter %q: %w", constraint, err)
+	}
+
+	return filter, nil
+}
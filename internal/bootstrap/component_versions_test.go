@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListComponentVersions(t *testing.T) {
+	repository := &mockRepository{
+		cv: []*mockComponentAccess{
+			{
+				name:     "ocm.software/mpas/ocm-controller",
+				versions: []string{"v0.2.0", "v0.10.0", "v0.1.0", "v1.0.0-rc.1", "v0.9.1"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		component   string
+		constraint  string
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:      "all versions",
+			component: "ocm.software/mpas/ocm-controller",
+			expected:  []string{"v1.0.0-rc.1", "v0.10.0", "v0.9.1", "v0.2.0", "v0.1.0"},
+		},
+		{
+			name:       "filtered versions",
+			component:  "ocm.software/mpas/ocm-controller",
+			constraint: ">=v0.2.0 <v1.0.0",
+			expected:   []string{"v0.10.0", "v0.9.1", "v0.2.0"},
+		},
+		{
+			name:        "invalid filter",
+			component:   "ocm.software/mpas/ocm-controller",
+			constraint:  "not-a-version",
+			expectedErr: `invalid version filter "not-a-version"`,
+		},
+		{
+			name:        "unknown component",
+			component:   "ocm.software/mpas/git-controller",
+			expectedErr: "failed to look up component ocm.software/mpas/git-controller",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			versions, err := listComponentVersions(repository, tc.component, tc.constraint)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, versions)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// InstallCondition reports whether a component should be installed on the cluster.
+type InstallCondition func(ctx context.Context, kubeclient client.Client) (bool, error)
+
+// WithConditionalInstall installs the component only if the condition returns true.
+// The condition is evaluated before any component is installed.
+func WithConditionalInstall(component string, condition InstallCondition) Option {
+	return func(o *options) {
+		if o.installConditions == nil {
+			o.installConditions = make(map[string]InstallCondition)
+		}
+		o.installConditions[component] = condition
+	}
+}
+
+// CRDInstalled is an InstallCondition that is met if the CustomResourceDefinition with the given name exists.
+func CRDInstalled(name string) InstallCondition {
+	return func(ctx context.Context, kubeclient client.Client) (bool, error) {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := kubeclient.Get(ctx, client.ObjectKey{Name: name}, crd); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get CustomResourceDefinition %s: %w", name, err)
+		}
+		return true, nil
+	}
+}
+
+// skipConditionalComponents removes the components whose install condition is not met from refs.
+func (b *Bootstrap) skipConditionalComponents(ctx context.Context, refs map[string]compdesc.ComponentReference) error {
+	comps := make([]string, 0, len(b.installConditions))
+	for comp := range b.installConditions {
+		comps = append(comps, comp)
+	}
+	sort.Strings(comps)
+
+	for _, comp := range comps {
+		if _, ok := refs[comp]; !ok {
+			continue
+		}
+
+		install, err := b.installConditions[comp](ctx, b.kubeclient)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate install condition of %s: %w", comp, err)
+		}
+		if install {
+			continue
+		}
+
+		b.log().InfoContext(ctx, fmt.Sprintf("Skipping %s, its install condition is not met", comp),
+			slog.String("component", comp),
+			slog.String("phase", ProgressPhaseComponentInstall))
+		delete(refs, comp)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSkipConditionalComponents(t *testing.T) {
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+	kubeclient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "ocirepositories.source.toolkit.fluxcd.io"}},
+	).Build()
+
+	ref := func(name string) compdesc.ComponentReference {
+		return compdesc.ComponentReference{ElementMeta: compdesc.ElementMeta{Name: name}}
+	}
+
+	testCases := []struct {
+		name        string
+		opts        []Option
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:     "no conditions",
+			expected: []string{"git-controller", "ocm-controller", "replication-controller"},
+		},
+		{
+			name: "CRD exists",
+			opts: []Option{
+				WithConditionalInstall("replication-controller", CRDInstalled("ocirepositories.source.toolkit.fluxcd.io")),
+			},
+			expected: []string{"git-controller", "ocm-controller", "replication-controller"},
+		},
+		{
+			name: "CRD is missing",
+			opts: []Option{
+				WithConditionalInstall("replication-controller", CRDInstalled("imagepolicies.image.toolkit.fluxcd.io")),
+				WithConditionalInstall("mpas-product-controller", CRDInstalled("imagepolicies.image.toolkit.fluxcd.io")),
+			},
+			expected: []string{"git-controller", "ocm-controller"},
+		},
+		{
+			name: "condition fails",
+			opts: []Option{
+				WithConditionalInstall("git-controller", func(context.Context, client.Client) (bool, error) {
+					return false, errors.New("boom")
+				}),
+			},
+			expectedErr: "failed to evaluate install condition of git-controller: boom",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := printer.Newprinter(&bytes.Buffer{})
+			require.NoError(t, err)
+
+			b := &Bootstrap{options: options{kubeclient: kubeclient, printer: p}}
+			for _, opt := range tc.opts {
+				opt(&b.options)
+			}
+
+			refs := map[string]compdesc.ComponentReference{
+				"git-controller":         ref("git-controller"),
+				"ocm-controller":         ref("ocm-controller"),
+				"replication-controller": ref("replication-controller"),
+			}
+			err = b.skipConditionalComponents(context.Background(), refs)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expected, getOrderedKeys(refs))
+		})
+	}
+}
@@ -0,0 +1,360 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// envVarPattern matches the ${VAR} references substituted in the string fields of a config file.
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// BootstrapConfig is the content of a .mpas.yaml config file. Each field corresponds to the
+// Option of the same name; unset fields leave the option at its default.
+type BootstrapConfig struct {
+	Description              string                     `json:"description,omitempty"`
+	DefaultBranch            string                     `json:"defaultBranch,omitempty"`
+	Visibility               string                     `json:"visibility,omitempty"`
+	Personal                 bool                       `json:"personal,omitempty"`
+	Owner                    string                     `json:"owner,omitempty"`
+	Token                    string                     `json:"token,omitempty"`
+	RepositoryName           string                     `json:"repositoryName,omitempty"`
+	TargetPath               string                     `json:"targetPath,omitempty"`
+	CommitMessageAppendix    string                     `json:"commitMessageAppendix,omitempty"`
+	FromFile                 string                     `json:"fromFile,omitempty"`
+	Registry                 string                     `json:"registry,omitempty"`
+	FallbackRegistries       []string                   `json:"fallbackRegistries,omitempty"`
+	DockerConfigPath         string                     `json:"dockerConfigPath,omitempty"`
+	TransportType            string                     `json:"transportType,omitempty"`
+	Components               []string                   `json:"components,omitempty"`
+	Interval                 metav1.Duration            `json:"interval,omitempty"`
+	ComponentIntervals       map[string]metav1.Duration `json:"componentIntervals,omitempty"`
+	Timeout                  metav1.Duration            `json:"timeout,omitempty"`
+	TestURL                  string                     `json:"testURL,omitempty"`
+	CAFile                   string                     `json:"caFile,omitempty"`
+	DryRun                   bool                       `json:"dryRun,omitempty"`
+	DestructiveUninstall     bool                       `json:"destructiveUninstall,omitempty"`
+	SkipCompletedPhases      bool                       `json:"skipCompletedPhases,omitempty"`
+	ClusterOnly              bool                       `json:"clusterOnly,omitempty"`
+	SkipPreflightChecks      bool                       `json:"skipPreflightChecks,omitempty"`
+	GitLabCI                 *GitLabCISpec              `json:"gitlabCI,omitempty"`
+	ComponentNamespaces      map[string]string          `json:"componentNamespaces,omitempty"`
+	FluxOCISource            bool                       `json:"fluxOCISource,omitempty"`
+	PublicKeyPath            string                     `json:"publicKeyPath,omitempty"`
+	NodeArchitecture         string                     `json:"nodeArchitecture,omitempty"`
+	ImageMirrors             map[string]string          `json:"imageMirrors,omitempty"`
+	MaxResourceSize          int64                      `json:"maxResourceSize,omitempty"`
+	ImagePullSecrets         []string                   `json:"imagePullSecrets,omitempty"`
+	LockConfigMap            *LockConfigMapRef          `json:"lockConfigMap,omitempty"`
+	EnforceLock              bool                       `json:"enforceLock,omitempty"`
+	FluxPodDisruptionBudgets int                        `json:"fluxPodDisruptionBudgets,omitempty"`
+	SigningKeyPath           string                     `json:"signingKeyPath,omitempty"`
+	SigningKeyPassphrase     string                     `json:"signingKeyPassphrase,omitempty"`
+	AdditionalFluxCRDs       []string                   `json:"additionalFluxCRDs,omitempty"`
+	RequiredSecrets          []SecretRef                `json:"requiredSecrets,omitempty"`
+	FluxResourceFilter       map[string]string          `json:"fluxResourceFilter,omitempty"`
+	FluxSourcePullPolicy     corev1.PullPolicy          `json:"fluxSourcePullPolicy,omitempty"`
+	CloneDepth               int                        `json:"cloneDepth,omitempty"`
+	PreApplyValidation       bool                       `json:"preApplyValidation,omitempty"`
+	FluxOIDCProvider         string                     `json:"fluxOIDCProvider,omitempty"`
+	FluxOIDCIdentity         string                     `json:"fluxOIDCIdentity,omitempty"`
+	FluxReceivers            []FluxReceiver             `json:"fluxReceivers,omitempty"`
+	AuditLogPath             string                     `json:"auditLogPath,omitempty"`
+	SandboxedBuild           bool                       `json:"sandboxedBuild,omitempty"`
+	FluxVPAUpdateMode        string                     `json:"fluxVPAUpdateMode,omitempty"`
+	// FluxServiceAccountAnnotations are keyed by the name of the Flux controller
+	FluxServiceAccountAnnotations map[string]map[string]string `json:"fluxServiceAccountAnnotations,omitempty"`
+	// CommitAuthorName and CommitAuthorEmail are the author of the component commits
+	CommitAuthorName  string `json:"commitAuthorName,omitempty"`
+	CommitAuthorEmail string `json:"commitAuthorEmail,omitempty"`
+	// GitHubActions is the workflow committed to GitHub management repositories
+	GitHubActions *GHActionsSpec `json:"githubActions,omitempty"`
+	// Profile is applied before all other fields, which override its options
+	Profile string `json:"profile,omitempty"`
+	// AirGapBundle is the bundle served as the registry of the bootstrap
+	AirGapBundle string `json:"airGapBundle,omitempty"`
+	// FluxResourceLimits and FluxResourceRequests are keyed by the name of the Flux controller
+	FluxResourceLimits   map[string]corev1.ResourceList `json:"fluxResourceLimits,omitempty"`
+	FluxResourceRequests map[string]corev1.ResourceList `json:"fluxResourceRequests,omitempty"`
+	// CodeOwners are the owners of the files of the management repository
+	CodeOwners []string `json:"codeOwners,omitempty"`
+	// BranchProtection protects the default branch of the management repository
+	BranchProtection *BranchProtection `json:"branchProtection,omitempty"`
+	// MinKubernetesVersion is the semver constraint the cluster version must satisfy
+	MinKubernetesVersion string `json:"minKubernetesVersion,omitempty"`
+	// AnnotationPropagation adds the extra identity of the flux resource as annotations to the Flux objects
+	AnnotationPropagation bool `json:"annotationPropagation,omitempty"`
+	// SSHKeyPath and SSHPassphrase authenticate the Flux GitRepository if the transport type is ssh
+	SSHKeyPath    string `json:"sshKeyPath,omitempty"`
+	SSHPassphrase string `json:"sshPassphrase,omitempty"`
+	// SSHKnownHostsPath is the known_hosts file of the Flux GitRepository
+	SSHKnownHostsPath string `json:"sshKnownHostsPath,omitempty"`
+	// FluxAlerts send the reconciliation failures of the Flux objects to the given providers
+	FluxAlerts []FluxAlert `json:"fluxAlerts,omitempty"`
+	// ImageAutomation installs the Flux image automation controllers and an ImageUpdateAutomation
+	ImageAutomation bool `json:"imageAutomation,omitempty"`
+	// BootstrapLock locks the management repository against concurrent bootstrap runs
+	BootstrapLock bool `json:"bootstrapLock,omitempty"`
+	// BootstrapLockTTL is the age after which the bootstrap lock is stale
+	BootstrapLockTTL metav1.Duration `json:"bootstrapLockTTL,omitempty"`
+}
+
+// LockConfigMapRef references the ConfigMap storing the lock file.
+type LockConfigMapRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// LoadConfig reads the config file at path and returns the options it configures.
+// ${VAR} references in string values are replaced with the value of the environment variable.
+// Unknown keys are rejected.
+func LoadConfig(path string) ([]Option, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	config := &BootstrapConfig{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	config.expandEnv()
+
+	return config.Options(), nil
+}
+
+// Options returns the options configured by the config.
+func (c *BootstrapConfig) Options() []Option {
+	var opts []Option
+	if c.Profile != "" {
+		opts = append(opts, WithBootstrapProfile(BootstrapProfile(c.Profile)))
+	}
+	addString := func(value string, option func(string) Option) {
+		if value != "" {
+			opts = append(opts, option(value))
+		}
+	}
+
+	addString(c.Description, WithDescription)
+	addString(c.DefaultBranch, WithDefaultBranch)
+	addString(c.Visibility, WithVisibility)
+	addString(c.Owner, WithOwner)
+	addString(c.Token, WithToken)
+	addString(c.RepositoryName, WithRepositoryName)
+	addString(c.TargetPath, WithTarget)
+	addString(c.CommitMessageAppendix, WithCommitMessageAppendix)
+	addString(c.FromFile, WithFromFile)
+	addString(c.Registry, WithRegistry)
+	addString(c.DockerConfigPath, WithDockerConfigPath)
+	addString(c.TransportType, WithTransportType)
+	addString(c.TestURL, WithTestURL)
+	addString(c.CAFile, WithRootFile)
+	addString(c.PublicKeyPath, WithVerifySignatures)
+	addString(c.NodeArchitecture, WithNodeArchitecture)
+	addString(c.AirGapBundle, WithAirGapBundle)
+
+	if c.Personal {
+		opts = append(opts, WithPersonal(c.Personal))
+	}
+	if len(c.FallbackRegistries) > 0 {
+		opts = append(opts, WithFallbackRegistries(c.FallbackRegistries))
+	}
+	if len(c.Components) > 0 {
+		opts = append(opts, WithComponents(c.Components))
+	}
+	if c.Interval.Duration != 0 {
+		opts = append(opts, WithInterval(c.Interval.Duration))
+	}
+	for component, interval := range c.ComponentIntervals {
+		opts = append(opts, WithComponentInterval(component, interval.Duration))
+	}
+	if c.Timeout.Duration != 0 {
+		opts = append(opts, WithTimeout(c.Timeout.Duration))
+	}
+	if c.DryRun {
+		opts = append(opts, WithDryRun(c.DryRun))
+	}
+	if c.DestructiveUninstall {
+		opts = append(opts, WithDestructiveUninstall(c.DestructiveUninstall))
+	}
+	if c.SkipCompletedPhases {
+		opts = append(opts, WithSkipCompletedPhases(c.SkipCompletedPhases))
+	}
+	if c.ClusterOnly {
+		opts = append(opts, WithClusterOnlyMode(c.ClusterOnly))
+	}
+	if c.SkipPreflightChecks {
+		opts = append(opts, WithSkipPreflightChecks(c.SkipPreflightChecks))
+	}
+	if c.GitLabCI != nil {
+		opts = append(opts, WithGitLabCI(*c.GitLabCI))
+	}
+	if c.GitHubActions != nil {
+		opts = append(opts, WithGitHubActions(*c.GitHubActions))
+	}
+	if len(c.ComponentNamespaces) > 0 {
+		opts = append(opts, WithComponentNamespaces(c.ComponentNamespaces))
+	}
+	if c.FluxOCISource {
+		opts = append(opts, WithFluxOCISource(c.FluxOCISource))
+	}
+	for original, mirror := range c.ImageMirrors {
+		opts = append(opts, WithImageMirror(original, mirror))
+	}
+	if c.MaxResourceSize != 0 {
+		opts = append(opts, WithMaxResourceSize(c.MaxResourceSize))
+	}
+	if len(c.ImagePullSecrets) > 0 {
+		opts = append(opts, WithImagePullSecrets(c.ImagePullSecrets))
+	}
+	if c.LockConfigMap != nil {
+		opts = append(opts, WithLockConfigMap(c.LockConfigMap.Name, c.LockConfigMap.Namespace))
+	}
+	if c.EnforceLock {
+		opts = append(opts, WithEnforceLock(c.EnforceLock))
+	}
+	if c.FluxPodDisruptionBudgets != 0 {
+		opts = append(opts, WithFluxPodDisruptionBudgets(c.FluxPodDisruptionBudgets))
+	}
+	if c.SigningKeyPath != "" {
+		opts = append(opts, WithSigningKey(c.SigningKeyPath, c.SigningKeyPassphrase))
+	}
+	if len(c.AdditionalFluxCRDs) > 0 {
+		opts = append(opts, WithAdditionalFluxCRDs(c.AdditionalFluxCRDs...))
+	}
+	if len(c.RequiredSecrets) > 0 {
+		opts = append(opts, WithRequiredSecrets(c.RequiredSecrets))
+	}
+	if len(c.FluxResourceFilter) > 0 {
+		opts = append(opts, WithFluxResourceFilter(c.FluxResourceFilter))
+	}
+	if c.FluxSourcePullPolicy != "" {
+		opts = append(opts, WithFluxSourceControllerImagePullPolicy(c.FluxSourcePullPolicy))
+	}
+	if c.CloneDepth != 0 {
+		opts = append(opts, WithCloneDepth(c.CloneDepth))
+	}
+	if c.PreApplyValidation {
+		opts = append(opts, WithPreApplyValidation(c.PreApplyValidation))
+	}
+	if c.FluxOIDCProvider != "" {
+		opts = append(opts, WithFluxOIDCProvider(c.FluxOIDCProvider, c.FluxOIDCIdentity))
+	}
+	if len(c.FluxReceivers) > 0 {
+		opts = append(opts, WithFluxReceivers(c.FluxReceivers...))
+	}
+	if c.AuditLogPath != "" {
+		opts = append(opts, WithAuditLogPath(c.AuditLogPath))
+	}
+	if c.SandboxedBuild {
+		opts = append(opts, WithSandboxedBuild(c.SandboxedBuild))
+	}
+	if c.FluxVPAUpdateMode != "" {
+		opts = append(opts, WithFluxVPA(c.FluxVPAUpdateMode))
+	}
+	for controller, annotations := range c.FluxServiceAccountAnnotations {
+		opts = append(opts, WithFluxServiceAccountAnnotations(controller, annotations))
+	}
+	if c.CommitAuthorName != "" || c.CommitAuthorEmail != "" {
+		opts = append(opts, WithCommitAuthor(c.CommitAuthorName, c.CommitAuthorEmail))
+	}
+	if len(c.FluxResourceLimits) > 0 {
+		opts = append(opts, WithFluxResourceLimits(c.FluxResourceLimits))
+	}
+	if len(c.FluxResourceRequests) > 0 {
+		opts = append(opts, WithFluxResourceRequests(c.FluxResourceRequests))
+	}
+	if len(c.CodeOwners) > 0 {
+		opts = append(opts, WithCodeOwners(c.CodeOwners))
+	}
+	addString(c.MinKubernetesVersion, WithMinKubernetesVersion)
+	if c.AnnotationPropagation {
+		opts = append(opts, WithAnnotationPropagation(c.AnnotationPropagation))
+	}
+	if c.SSHKeyPath != "" {
+		opts = append(opts, WithSSHKey(c.SSHKeyPath, c.SSHPassphrase))
+	}
+	addString(c.SSHKnownHostsPath, WithSSHKnownHosts)
+	if len(c.FluxAlerts) > 0 {
+		opts = append(opts, WithFluxAlerts(c.FluxAlerts))
+	}
+	if c.ImageAutomation {
+		opts = append(opts, WithImageAutomation(c.ImageAutomation))
+	}
+	if c.BootstrapLock {
+		opts = append(opts, WithBootstrapLock(c.BootstrapLock))
+	}
+	if c.BootstrapLockTTL.Duration != 0 {
+		opts = append(opts, WithBootstrapLockTTL(c.BootstrapLockTTL.Duration))
+	}
+	if c.BranchProtection != nil {
+		opts = append(opts, WithBranchProtection(c.BranchProtection.RequirePullRequest, c.BranchProtection.RequiredApprovals))
+	}
+
+	return opts
+}
+
+// expandEnv substitutes the ${VAR} references in all string values of the config.
+func (c *BootstrapConfig) expandEnv() {
+	for _, s := range []*string{
+		&c.Description, &c.DefaultBranch, &c.Visibility, &c.Owner, &c.Token, &c.RepositoryName,
+		&c.TargetPath, &c.CommitMessageAppendix, &c.FromFile, &c.Registry, &c.DockerConfigPath,
+		&c.TransportType, &c.TestURL, &c.CAFile, &c.PublicKeyPath, &c.NodeArchitecture,
+		&c.SigningKeyPath, &c.SigningKeyPassphrase, &c.FluxOIDCProvider, &c.FluxOIDCIdentity,
+		&c.AuditLogPath, &c.FluxVPAUpdateMode, &c.CommitAuthorName, &c.CommitAuthorEmail,
+		&c.Profile, &c.AirGapBundle, &c.MinKubernetesVersion, &c.SSHKeyPath, &c.SSHPassphrase,
+		&c.SSHKnownHostsPath,
+	} {
+		*s = expandEnv(*s)
+	}
+
+	for _, list := range [][]string{c.FallbackRegistries, c.Components, c.ImagePullSecrets, c.AdditionalFluxCRDs, c.CodeOwners} {
+		for i := range list {
+			list[i] = expandEnv(list[i])
+		}
+	}
+
+	for _, m := range []map[string]string{c.ComponentNamespaces, c.ImageMirrors, c.FluxResourceFilter} {
+		for k, v := range m {
+			m[k] = expandEnv(v)
+		}
+	}
+
+	if c.GitLabCI != nil {
+		c.GitLabCI.Image = expandEnv(c.GitLabCI.Image)
+		for _, list := range [][]string{c.GitLabCI.Branches, c.GitLabCI.Tags} {
+			for i := range list {
+				list[i] = expandEnv(list[i])
+			}
+		}
+	}
+
+	if c.GitHubActions != nil {
+		c.GitHubActions.Image = expandEnv(c.GitHubActions.Image)
+		c.GitHubActions.Schedule = expandEnv(c.GitHubActions.Schedule)
+		c.GitHubActions.RunsOn = expandEnv(c.GitHubActions.RunsOn)
+		for i := range c.GitHubActions.Branches {
+			c.GitHubActions.Branches[i] = expandEnv(c.GitHubActions.Branches[i])
+		}
+	}
+
+	if c.LockConfigMap != nil {
+		c.LockConfigMap.Name = expandEnv(c.LockConfigMap.Name)
+		c.LockConfigMap.Namespace = expandEnv(c.LockConfigMap.Namespace)
+	}
+}
+
+func expandEnv(s string) string {
+	return envVarPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		return os.Getenv(envVarPattern.FindStringSubmatch(ref)[1])
+	})
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `description: management repository
+defaultBranch: main
+visibility: private
+personal: true
+owner: mpas-admin
+token: ${MPAS_TEST_TOKEN}
+repositoryName: mpas-management
+targetPath: clusters/${MPAS_TEST_CLUSTER}
+commitMessageAppendix: "[skip ci]"
+registry: ghcr.io/open-component-model/mpas-bootstrap-component
+fallbackRegistries:
+- registry.corp/mpas
+dockerConfigPath: /home/mpas/.docker/config.json
+transportType: https
+components:
+- ocm-controller
+- git-controller
+interval: 2m
+componentIntervals:
+  flux: 30s
+timeout: 10m
+caFile: /etc/ssl/ca.pem
+skipCompletedPhases: true
+skipPreflightChecks: true
+componentNamespaces:
+  ocm-controller: ocm
+fluxOCISource: true
+publicKeyPath: /keys/mpas.pub
+nodeArchitecture: arm64
+imageMirrors:
+  ghcr.io: mirror.corp/${MPAS_TEST_CLUSTER}
+maxResourceSize: 1024
+imagePullSecrets:
+- regcred
+lockConfigMap:
+  name: mpas-lock
+  namespace: mpas-system
+enforceLock: true
+fluxPodDisruptionBudgets: 1
+`
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("MPAS_TEST_TOKEN", "secret-token")
+	t.Setenv("MPAS_TEST_CLUSTER", "production")
+
+	path := filepath.Join(t.TempDir(), ".mpas.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testConfig), 0o600))
+
+	opts, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	got := options{}
+	for _, opt := range opts {
+		opt(&got)
+	}
+
+	assert.Equal(t, options{
+		description:            "management repository",
+		defaultBranch:          "main",
+		visibility:             "private",
+		personal:               true,
+		owner:                  "mpas-admin",
+		token:                  "secret-token",
+		repositoryName:         "mpas-management",
+		targetPath:             "clusters/production",
+		commitMessageAppendix:  "[skip ci]",
+		registry:               "ghcr.io/open-component-model/mpas-bootstrap-component",
+		fallbackRegistries:     []string{"registry.corp/mpas"},
+		dockerConfigPath:       "/home/mpas/.docker/config.json",
+		transportType:          "https",
+		components:             []string{"ocm-controller", "git-controller"},
+		interval:               2 * time.Minute,
+		componentIntervals:     map[string]time.Duration{"flux": 30 * time.Second},
+		timeout:                10 * time.Minute,
+		caFile:                 "/etc/ssl/ca.pem",
+		skipCompletedPhases:    true,
+		skipPreflightChecks:    true,
+		componentNamespaces:    map[string]string{"ocm-controller": "ocm"},
+		fluxOCISource:          true,
+		publicKeyPath:          "/keys/mpas.pub",
+		nodeArchitecture:       "arm64",
+		imageMirrors:           map[string]string{"ghcr.io": "mirror.corp/production"},
+		maxResourceSize:        1024,
+		imagePullSecrets:       []string{"regcred"},
+		lockConfigMapName:      "mpas-lock",
+		lockConfigMapNamespace: "mpas-system",
+		enforceLock:            true,
+		fluxPDBMinAvailable:    1,
+	}, got)
+}
+
+func TestLoadConfigUnknownKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".mpas.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("owner: mpas-admin\nrepository: mpas-management\n"), 0o600))
+
+	_, err := LoadConfig(path)
+	require.ErrorContains(t, err, "invalid config file "+path)
+	require.ErrorContains(t, err, `unknown field "repository"`)
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	_, err := LoadConfig(filepath.Join(t.TempDir(), ".mpas.yaml"))
+	require.ErrorContains(t, err, "failed to read config file")
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+)
+
+const (
+	// additionalCRDsFileName is the name of the file holding the additional CRDs of the Flux components.
+	additionalCRDsFileName = "additional-crds.yaml"
+	// crdsFileName is the name of the file the CRDs are applied from before the remaining manifests.
+	crdsFileName = "crds.yaml"
+)
+
+// WithAdditionalFluxCRDs adds the CustomResourceDefinitions in the manifests at paths to the Flux components.
+// They are applied together with the Flux CRDs, before the Flux controllers are started.
+func WithAdditionalFluxCRDs(paths ...string) Option {
+	return func(o *options) {
+		o.additionalFluxCRDPaths = append(o.additionalFluxCRDPaths, paths...)
+	}
+}
+
+// withAdditionalCRDPaths adds the CRD manifests at paths to the kustomization of the flux components.
+func withAdditionalCRDPaths(paths ...string) fluxOption {
+	return func(o *fluxOptions) {
+		o.additionalCRDPaths = append(o.additionalCRDPaths, paths...)
+	}
+}
+
+// addAdditionalCRDs writes the additional CRDs to dir and adds them to the resources of the kustomization.
+// The manifests must only contain CustomResourceDefinitions.
+func (f *fluxInstall) addAdditionalCRDs(kus *kustypes.Kustomization) error {
+	if len(f.additionalCRDPaths) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, path := range f.additionalCRDPaths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read CRD manifest %s: %w", path, err)
+		}
+
+		objects, err := kubeutils.YamlToUnstructructured(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse CRD manifest %s: %w", path, err)
+		}
+		for _, obj := range objects {
+			if obj.GetKind() != "CustomResourceDefinition" {
+				return fmt.Errorf("CRD manifest %s contains %s %s, only CustomResourceDefinitions are allowed", path, obj.GetKind(), obj.GetName())
+			}
+		}
+
+		buf.WriteString("---\n")
+		buf.Write(data)
+		if !bytes.HasSuffix(data, []byte("\n")) {
+			buf.WriteString("\n")
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(f.dir, additionalCRDsFileName), buf.Bytes(), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to write additional CRDs: %w", err)
+	}
+
+	kus.Resources = append(kus.Resources, "./"+additionalCRDsFileName)
+	return nil
+}
+
+// applyCRDs applies the CustomResourceDefinitions of the manifests and waits until they are established,
+// so that the controllers watching them start once their API is served. It returns the remaining manifests.
+func (f *fluxInstall) applyCRDs(ctx context.Context, manifests []byte) ([]byte, error) {
+	crds, rest, err := kubeutils.SeparateCRDs(manifests)
+	if err != nil {
+		return nil, err
+	}
+	if len(crds) == 0 {
+		return manifests, nil
+	}
+
+	if err := applyManifest(ctx, f.applier, f.restClientGetter, filepath.Join(f.dir, "crds"), crdsFileName, crds); err != nil {
+		return nil, err
+	}
+
+	objs, err := kubeutils.YamlToUnstructructured(crds)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		names = append(names, obj.GetName())
+	}
+
+	if err := kubeutils.WaitForCRDEstablished(ctx, f.kubeClient, names); err != nil {
+		return nil, err
+	}
+
+	return rest, nil
+}
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	cfd "github.com/open-component-model/ocm-controller/pkg/configdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var alertsCRD = []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: alerts.example.com
+spec:
+  group: example.com
+  names:
+    kind: Alert
+    plural: alerts
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+`)
+
+func TestAdditionalFluxCRDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crds.yaml")
+	require.NoError(t, os.WriteFile(path, alertsCRD, 0o600))
+
+	b := &Bootstrap{}
+	WithAdditionalFluxCRDs(path)(&b.options)
+	opts, fopts := b.newFluxOptions(t.TempDir(), nil)
+	for _, o := range fopts {
+		o(opts)
+	}
+
+	f := &fluxInstall{fluxOptions: opts}
+	kfile, kus, err := f.generateKustomization(bytes.NewReader(kustomizedDeployment))
+	require.NoError(t, err)
+	res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+	require.NoError(t, err)
+
+	objects, err := kubeutils.YamlToUnstructructured(res)
+	require.NoError(t, err)
+
+	var crds []string
+	for _, obj := range objects {
+		if obj.GetKind() == "CustomResourceDefinition" {
+			crds = append(crds, obj.GetName())
+			assert.Empty(t, obj.GetNamespace())
+		}
+	}
+	assert.Equal(t, []string{"alerts.example.com"}, crds)
+}
+
+func TestAdditionalFluxCRDsRejectsOtherKinds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crds.yaml")
+	require.NoError(t, os.WriteFile(path, kustomizedDeployment, 0o600))
+
+	f := &fluxInstall{fluxOptions: &fluxOptions{dir: t.TempDir(), namespace: "flux-system"}}
+	withAdditionalCRDPaths(path)(f.fluxOptions)
+	kfile, kus, err := f.generateKustomization(bytes.NewReader(kustomizedDeployment))
+	require.NoError(t, err)
+	_, err = f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+	assert.ErrorContains(t, err, "only CustomResourceDefinitions are allowed")
+}
+
+func TestFluxInstallCRDPreInstallation(t *testing.T) {
+	manifests := append(append(append([]byte{}, alertsCRD...), []byte("---\n")...), fluxControllers...)
+
+	testCases := []struct {
+		name        string
+		established bool
+		expectedErr string
+	}{
+		{
+			name:        "CRDs are established",
+			established: true,
+		},
+		{
+			name:        "CRDs are not established",
+			expectedErr: "failed to wait for CustomResourceDefinition alerts.example.com to be established",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			type appliedManifest struct {
+				name  string
+				kinds []string
+			}
+			var applied []appliedManifest
+			applier := func(_ context.Context, _ genericclioptions.RESTClientGetter, _, manifestPath string) (string, error) {
+				data, err := os.ReadFile(manifestPath)
+				if err != nil {
+					return "", err
+				}
+				objs, err := kubeutils.YamlToUnstructructured(data)
+				if err != nil {
+					return "", err
+				}
+				m := appliedManifest{name: filepath.Base(manifestPath)}
+				for _, obj := range objs {
+					m.kinds = append(m.kinds, obj.GetKind())
+				}
+				applied = append(applied, m)
+				return "", nil
+			}
+
+			crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "alerts.example.com"}}
+			if tc.established {
+				crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{
+					{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+				}
+			}
+			scheme, err := kubeutils.NewScheme()
+			require.NoError(t, err)
+			kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(crd).Build()
+
+			gitClient := &recordingGitClient{}
+			f := &fluxInstall{
+				componentName: "ocm.software/mpas/flux",
+				version:       "v2.0.0",
+				fluxOptions: &fluxOptions{
+					gitClient:   gitClient,
+					applier:     applier,
+					dir:         t.TempDir(),
+					namespace:   "flux-system",
+					clusterOnly: true,
+					kubeClient:  kubeClient,
+				},
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err = f.reconcileComponents(ctx, "target/flux-system/gotk-components.yaml", string(manifests))
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				// the controllers are not applied before the CRDs are established
+				assert.Equal(t, []appliedManifest{{name: "crds.yaml", kinds: []string{"CustomResourceDefinition"}}}, applied)
+				assert.Empty(t, gitClient.calls)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []appliedManifest{
+				{name: "crds.yaml", kinds: []string{"CustomResourceDefinition"}},
+				{name: "gotk-components.yaml", kinds: []string{"Deployment", "Deployment"}},
+			}, applied)
+			assert.Empty(t, gitClient.calls)
+		})
+	}
+}
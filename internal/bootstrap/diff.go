@@ -0,0 +1,303 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/utils"
+	"github.com/pmezard/go-difflib/difflib"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// DiffAction is the change applying the desired manifests would make to a resource.
+type DiffAction string
+
+const (
+	// DiffActionAdded marks a resource that would be created.
+	DiffActionAdded DiffAction = "added"
+	// DiffActionModified marks a resource that would be changed.
+	DiffActionModified DiffAction = "modified"
+	// DiffActionRemoved marks a resource that would be deleted.
+	DiffActionRemoved DiffAction = "removed"
+)
+
+// ResourceDiff is the change of a single resource.
+type ResourceDiff struct {
+	// Resource identifies the resource as kind/namespace/name.
+	Resource string
+	// Action is the change made to the resource.
+	Action DiffAction
+	// Diff is the unified diff of the live and the desired resource.
+	Diff string
+}
+
+// Diff writes a unified diff of the live cluster resources and the component manifests the
+// bootstrap would generate to w. Resources of the component manifests committed to the management
+// repository that would no longer be generated are reported as removed.
+func (b *Bootstrap) Diff(ctx context.Context, w io.Writer) error {
+	if b.repository == nil {
+		return fmt.Errorf("management repository is not set")
+	}
+
+	octx := om.DefaultContext()
+	if _, err := utils.Configure(octx, ""); err != nil {
+		return fmt.Errorf("failed to configure ocm context: %w", err)
+	}
+	octx.LoggingContext().SetDefaultLevel(1)
+
+	ociRepo, err := b.makeOCIRepositoryWithFallback(ctx, octx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch bootstrap component: %w", err)
+	}
+	defer ociRepo.Close()
+
+	var refs map[string]compdesc.ComponentReference
+	if b.enforceLock {
+		refs, err = b.readLockedReferences(ctx, ociRepo)
+	} else {
+		refs, err = b.fetchBootstrapComponentReferences(ociRepo)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch bootstrap component references: %w", err)
+	}
+
+	var (
+		desired   []unstructured.Unstructured
+		manifests = make(map[string]bool, len(refs))
+	)
+	for _, comp := range getOrderedKeys(refs) {
+		ref := refs[comp]
+		manifests[componentManifestFileName(comp, ref)] = true
+
+		data, err := b.generateComponentManifest(ctx, ociRepo, comp, ref)
+		if err != nil {
+			return fmt.Errorf("failed to generate %s manifests: %w", comp, err)
+		}
+
+		objects, err := kubeutils.YamlToUnstructructured(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s manifests: %w", comp, err)
+		}
+		for _, obj := range objects {
+			desired = append(desired, *obj)
+		}
+	}
+
+	committed, err := b.committedObjects(ctx, func(path string) bool {
+		return manifests[filepath.Base(path)]
+	})
+	if err != nil {
+		return err
+	}
+
+	existing, err := b.liveObjects(ctx, append(desired, derefObjects(committed)...))
+	if err != nil {
+		return err
+	}
+
+	for _, d := range diffResources(existing, desired) {
+		if _, err := fmt.Fprint(w, d.Diff); err != nil {
+			return fmt.Errorf("failed to write diff: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// liveObjects returns the live cluster state of the given objects. Objects that do not exist are omitted.
+func (b *Bootstrap) liveObjects(ctx context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	var (
+		live []unstructured.Unstructured
+		seen = make(map[string]bool, len(objects))
+	)
+	for _, obj := range objects {
+		key := resourceKey(obj)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		l := unstructured.Unstructured{}
+		l.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := b.kubeclient.Get(ctx, client.ObjectKeyFromObject(&obj), &l); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get %s: %w", resourceName(obj), err)
+		}
+		live = append(live, l)
+	}
+
+	return live, nil
+}
+
+// diffResources categorizes the changes applying desired to existing would make.
+// Desired resources are merged onto the existing ones, so fields defaulted by the cluster
+// are not reported as modified.
+func diffResources(existing, desired []unstructured.Unstructured) []ResourceDiff {
+	existingByKey := make(map[string]unstructured.Unstructured, len(existing))
+	for _, obj := range existing {
+		existingByKey[resourceKey(obj)] = obj
+	}
+
+	var (
+		diffs      []ResourceDiff
+		desiredKey = make(map[string]bool, len(desired))
+	)
+	for _, obj := range desired {
+		key := resourceKey(obj)
+		desiredKey[key] = true
+
+		want := cleanObject(obj)
+		live, ok := existingByKey[key]
+		if !ok {
+			diffs = append(diffs, newResourceDiff(obj, DiffActionAdded, nil, want))
+			continue
+		}
+
+		current := cleanObject(live)
+		merged, err := mergeObject(obj.GroupVersionKind(), current, want)
+		if err != nil {
+			// the desired manifest replaces the live object if it cannot be merged
+			merged = want
+		}
+		if !equalObjects(current, merged) {
+			diffs = append(diffs, newResourceDiff(obj, DiffActionModified, current, merged))
+		}
+	}
+
+	for _, obj := range existing {
+		if desiredKey[resourceKey(obj)] {
+			continue
+		}
+		diffs = append(diffs, newResourceDiff(obj, DiffActionRemoved, cleanObject(obj), nil))
+	}
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Resource < diffs[j].Resource
+	})
+
+	return diffs
+}
+
+func newResourceDiff(obj unstructured.Unstructured, action DiffAction, from, to map[string]any) ResourceDiff {
+	name := resourceName(obj)
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(toYAML(from)),
+		B:        difflib.SplitLines(toYAML(to)),
+		FromFile: "live/" + name,
+		ToFile:   "desired/" + name,
+		Context:  3,
+	})
+
+	return ResourceDiff{
+		Resource: name,
+		Action:   action,
+		Diff:     diff,
+	}
+}
+
+// cleanObject returns the fields of obj that are set by the user, omitting the status and
+// metadata maintained by the cluster.
+func cleanObject(obj unstructured.Unstructured) map[string]any {
+	o := obj.DeepCopy()
+	delete(o.Object, "status")
+	for _, field := range []string{"managedFields", "resourceVersion", "uid", "creationTimestamp", "generation", "selfLink"} {
+		unstructured.RemoveNestedField(o.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(o.Object, "metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration")
+	if annotations, found, _ := unstructured.NestedMap(o.Object, "metadata", "annotations"); found && len(annotations) == 0 {
+		unstructured.RemoveNestedField(o.Object, "metadata", "annotations")
+	}
+	return o.Object
+}
+
+// mergeObject merges desired onto current. Built-in types are merged with a strategic merge patch,
+// so list items like containers are merged by their key; other types with a JSON merge patch.
+func mergeObject(gvk schema.GroupVersionKind, current, desired map[string]any) (map[string]any, error) {
+	if typed, err := scheme.Scheme.New(gvk); err == nil {
+		return strategicpatch.StrategicMergeMapPatch(current, desired, typed)
+	}
+
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	desiredJSON, err := json.Marshal(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	mergedJSON, err := jsonpatch.MergePatch(currentJSON, desiredJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := map[string]any{}
+	if err := json.Unmarshal(mergedJSON, &merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+func equalObjects(a, b map[string]any) bool {
+	return toYAML(a) == toYAML(b)
+}
+
+func toYAML(obj map[string]any) string {
+	if obj == nil {
+		return ""
+	}
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Sprint(obj)
+	}
+	return string(data)
+}
+
+// resourceKey identifies a resource independent of its API version.
+func resourceKey(obj unstructured.Unstructured) string {
+	gk := obj.GroupVersionKind().GroupKind()
+	return strings.Join([]string{gk.Group, gk.Kind, obj.GetNamespace(), obj.GetName()}, "/")
+}
+
+// resourceName identifies a resource as kind/namespace/name.
+func resourceName(obj unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
+
+// componentManifestFileName returns the name of the file the manifests of the component are committed to.
+func componentManifestFileName(comp string, ref compdesc.ComponentReference) string {
+	if comp == env.FluxName {
+		return "gotk-components.yaml"
+	}
+	return fmt.Sprintf("%s.yaml", filepath.Base(ref.GetComponentName()))
+}
+
+func derefObjects(objects []*unstructured.Unstructured) []unstructured.Unstructured {
+	result := make([]unstructured.Unstructured, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, *obj)
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDiffResources(t *testing.T) {
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "git-controller", Namespace: "ocm-system"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "git-controller"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "git-controller"}},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:            "manager",
+						Image:           "ghcr.io/user/git-controller:v0.9.0",
+						ImagePullPolicy: corev1.PullIfNotPresent,
+					}},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+	}
+	unchanged := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "git-controller", Namespace: "ocm-system"},
+	}
+	removed := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "git-controller-metrics", Namespace: "ocm-system"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "metrics", Port: 8080}}},
+	}
+
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+	b := &Bootstrap{options: options{
+		kubeclient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment, unchanged, removed).Build(),
+	}}
+
+	desired, err := kubeutils.YamlToUnstructructured(append(append([]byte{}, kustomizedDeployment...), []byte(`---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: git-controller
+  namespace: ocm-system
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: git-controller-config
+  namespace: ocm-system
+data:
+  interval: 10m
+`)...))
+	require.NoError(t, err)
+
+	// the removed service is part of the previously committed manifests
+	committed := &unstructured.Unstructured{}
+	committed.SetAPIVersion("v1")
+	committed.SetKind("Service")
+	committed.SetName("git-controller-metrics")
+	committed.SetNamespace("ocm-system")
+
+	existing, err := b.liveObjects(context.Background(), append(derefObjects(desired), *committed))
+	require.NoError(t, err)
+	require.Len(t, existing, 3, "the config map does not exist yet")
+
+	diffs := diffResources(existing, derefObjects(desired))
+	require.Len(t, diffs, 3)
+
+	assert.Equal(t, "ConfigMap/ocm-system/git-controller-config", diffs[0].Resource)
+	assert.Equal(t, DiffActionAdded, diffs[0].Action)
+	assert.Contains(t, diffs[0].Diff, "+++ desired/ConfigMap/ocm-system/git-controller-config")
+	assert.Contains(t, diffs[0].Diff, "+  interval: 10m")
+
+	assert.Equal(t, "Deployment/ocm-system/git-controller", diffs[1].Resource)
+	assert.Equal(t, DiffActionModified, diffs[1].Action)
+	assert.Contains(t, diffs[1].Diff, "--- live/Deployment/ocm-system/git-controller")
+	assert.Contains(t, diffs[1].Diff, "-      - image: ghcr.io/user/git-controller:v0.9.0")
+	assert.Contains(t, diffs[1].Diff, "+      - image: ghcr.io/user/git-controller:v1.0.0")
+	assert.NotContains(t, diffs[1].Diff, "readyReplicas", "the status must not be compared")
+	assert.NotContains(t, diffs[1].Diff, "imagePullPolicy", "fields defaulted by the cluster must not be reported")
+
+	assert.Equal(t, "Service/ocm-system/git-controller-metrics", diffs[2].Resource)
+	assert.Equal(t, DiffActionRemoved, diffs[2].Action)
+	assert.Contains(t, diffs[2].Diff, "-  - name: metrics")
+}
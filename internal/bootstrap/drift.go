@@ -0,0 +1,232 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// driftMissing is the live value of a resource that does not exist in the cluster.
+	driftMissing = "<missing>"
+	// kustomizeGroup is the API group of the kustomize config files, which are not cluster resources.
+	kustomizeGroup = "kustomize.config.k8s.io"
+)
+
+// DriftItem is a difference between a committed manifest and the live cluster resource.
+type DriftItem struct {
+	// Resource identifies the resource as kind/namespace/name.
+	Resource string
+	// Field is the path of the differing field, e.g. spec.replicas.
+	// It is empty if the resource does not exist in the cluster.
+	Field string
+	// Committed is the value in the management repository.
+	Committed string
+	// Live is the value in the cluster.
+	Live string
+}
+
+// CompareWithCluster compares the manifests committed to the management repository with the
+// live cluster resources. Only fields set in the committed manifests are compared, so fields
+// defaulted by the cluster are not reported. Of the metadata, only the labels and annotations
+// are compared. Resources whose kind is not served by the cluster are skipped.
+func (b *Bootstrap) CompareWithCluster(ctx context.Context) ([]DriftItem, error) {
+	if b.repository == nil {
+		return nil, fmt.Errorf("management repository is not set")
+	}
+
+	objects, err := b.committedObjects(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []DriftItem
+	for _, committed := range objects {
+		resource := fmt.Sprintf("%s/%s/%s", committed.GetKind(), committed.GetNamespace(), committed.GetName())
+
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(committed.GroupVersionKind())
+		if err := b.kubeclient.Get(ctx, client.ObjectKeyFromObject(committed), live); err != nil {
+			if apierrors.IsNotFound(err) {
+				drift = append(drift, DriftItem{Resource: resource, Committed: "<present>", Live: driftMissing})
+				continue
+			}
+			// the CRD of the resource is not installed, e.g. because its component is not installed yet
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get %s: %w", resource, err)
+		}
+
+		metadata := map[string]any{}
+		for _, field := range []string{"labels", "annotations"} {
+			if v, ok, _ := unstructured.NestedFieldNoCopy(committed.Object, "metadata", field); ok {
+				metadata[field] = v
+			}
+		}
+		delete(committed.Object, "status")
+		delete(committed.Object, "metadata")
+		if len(metadata) > 0 {
+			committed.Object["metadata"] = metadata
+		}
+		drift = append(drift, compareFields(resource, "", committed.Object, live.Object)...)
+	}
+
+	return drift, nil
+}
+
+// committedObjects returns the objects of the component manifests in the management repository.
+// Only the files include returns true for are read, all files are read if include is nil.
+// The kustomize config files are skipped, as they are not applied to the cluster.
+func (b *Bootstrap) committedObjects(ctx context.Context, include func(path string) bool) ([]*unstructured.Unstructured, error) {
+	namespaces := map[string]bool{
+		env.DefaultFluxNamespace:            true,
+		env.DefaultCertManagerNamespace:     true,
+		env.DefaultOCMNamespace:             true,
+		env.DefaultMPASNamespace:            true,
+		env.DefaultExternalSecretsNamespace: true,
+	}
+	for _, ns := range b.componentNamespaces {
+		namespaces[ns] = true
+	}
+
+	dirs := make([]string, 0, len(namespaces))
+	for ns := range namespaces {
+		dirs = append(dirs, filepath.Join(b.targetPath, ns))
+	}
+	sort.Strings(dirs)
+
+	var (
+		objects []*unstructured.Unstructured
+		seen    = make(map[string]bool)
+	)
+	for _, dir := range dirs {
+		files, err := b.repository.Files().Get(ctx, dir, b.defaultBranch)
+		if err != nil {
+			if errors.Is(err, gitprovider.ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+		}
+
+		for _, file := range files {
+			if file.Path == nil || file.Content == nil || seen[*file.Path] {
+				continue
+			}
+			seen[*file.Path] = true
+
+			path := *file.Path
+			if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+				continue
+			}
+			if include != nil && !include(path) {
+				continue
+			}
+
+			objs, err := kubeutils.YamlToUnstructructured([]byte(*file.Content))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", *file.Path, err)
+			}
+			for _, obj := range objs {
+				if obj.GroupVersionKind().Group == kustomizeGroup {
+					continue
+				}
+				objects = append(objects, obj)
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+// compareFields returns the fields of committed that differ from live.
+func compareFields(resource, path string, committed, live any) []DriftItem {
+	if c, ok := committed.(map[string]any); ok {
+		l, ok := live.(map[string]any)
+		if !ok {
+			return []DriftItem{newDriftItem(resource, path, committed, live)}
+		}
+
+		keys := make([]string, 0, len(c))
+		for k := range c {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var drift []DriftItem
+		for _, k := range keys {
+			drift = append(drift, compareFields(resource, joinFieldPath(path, k), c[k], l[k])...)
+		}
+		return drift
+	}
+
+	if c, ok := committed.([]any); ok {
+		l, ok := live.([]any)
+		if !ok || len(c) != len(l) {
+			return []DriftItem{newDriftItem(resource, path, committed, live)}
+		}
+
+		var drift []DriftItem
+		for i := range c {
+			drift = append(drift, compareFields(resource, fmt.Sprintf("%s[%d]", path, i), c[i], l[i])...)
+		}
+		return drift
+	}
+
+	if fieldValue(committed) != fieldValue(live) {
+		return []DriftItem{newDriftItem(resource, path, committed, live)}
+	}
+
+	return nil
+}
+
+func newDriftItem(resource, path string, committed, live any) DriftItem {
+	return DriftItem{
+		Resource:  resource,
+		Field:     path,
+		Committed: fieldValue(committed),
+		Live:      fieldValue(live),
+	}
+}
+
+func joinFieldPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	if strings.ContainsAny(field, "./") {
+		return fmt.Sprintf("%s[%q]", path, field)
+	}
+	return path + "." + field
+}
+
+// fieldValue renders a field value. Numbers are rendered the same regardless of their type.
+func fieldValue(v any) string {
+	if v == nil {
+		return driftMissing
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
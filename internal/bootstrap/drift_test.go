@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// noMatchClient fails with a no kind match error for the kinds whose CRD is not installed.
+type noMatchClient struct {
+	client.Client
+
+	notInstalled string
+}
+
+func (c *noMatchClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if gvk := obj.GetObjectKind().GroupVersionKind(); gvk.Kind == c.notInstalled {
+		return &meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}}
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func TestCompareWithCluster(t *testing.T) {
+	manifests := string(kustomizedDeployment) + `---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: git-controller
+  namespace: ocm-system
+`
+	replicas := int32(3)
+	live := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "git-controller", Namespace: "ocm-system"},
+		Spec: appsv1.DeploymentSpec{
+			// manually scaled up
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "git-controller"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "git-controller"}},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:            "manager",
+						Image:           "ghcr.io/user/git-controller:v1.0.0",
+						ImagePullPolicy: corev1.PullIfNotPresent,
+					}},
+				},
+			},
+		},
+	}
+
+	b := &Bootstrap{
+		repository: &mockGitRepository{
+			fileClient: &mockFileClient{files: []*gitprovider.CommitFile{{
+				Path:    gitprovider.StringVar("clusters/ocm-system/git-controller.yaml"),
+				Content: gitprovider.StringVar(manifests),
+			}}},
+		},
+		options: options{
+			kubeclient: fake.NewClientBuilder().WithObjects(live).Build(),
+			targetPath: "clusters",
+		},
+	}
+
+	drift, err := b.CompareWithCluster(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []DriftItem{
+		{Resource: "Deployment/ocm-system/git-controller", Field: "spec.replicas", Committed: "1", Live: "3"},
+		{Resource: "ServiceAccount/ocm-system/git-controller", Committed: "<present>", Live: "<missing>"},
+	}, drift)
+}
+
+func TestCompareWithClusterFluxLayout(t *testing.T) {
+	components := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: source-controller
+  namespace: flux-system
+  labels:
+    app.kubernetes.io/part-of: flux
+spec:
+  replicas: 1
+`
+
+	sync := `apiVersion: source.toolkit.fluxcd.io/v1
+kind: GitRepository
+metadata:
+  name: flux-system
+  namespace: flux-system
+spec:
+  url: https://github.com/open-component-model/mpas-management
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: flux-system
+  namespace: flux-system
+spec:
+  path: ./clusters
+  prune: true
+`
+	kustomization := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- gotk-components.yaml
+- gotk-sync.yaml
+`
+	alerts := `apiVersion: notification.toolkit.fluxcd.io/v1beta2
+kind: Alert
+metadata:
+  name: slack-0
+  namespace: flux-system
+spec:
+  eventSeverity: error
+`
+
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+	replicas := int32(1)
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "source-controller",
+				Namespace: "flux-system",
+				// the label was changed in the cluster
+				Labels: map[string]string{"app.kubernetes.io/part-of": "other"},
+			},
+			Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+		},
+		&sourcev1.GitRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "flux-system", Namespace: "flux-system"},
+			Spec:       sourcev1.GitRepositorySpec{URL: "https://github.com/open-component-model/mpas-management"},
+		},
+		&kustomizev1.Kustomization{
+			ObjectMeta: metav1.ObjectMeta{Name: "flux-system", Namespace: "flux-system"},
+			Spec:       kustomizev1.KustomizationSpec{Path: "./clusters", Prune: true},
+		},
+	).Build()
+
+	b := &Bootstrap{
+		repository: &mockGitRepository{
+			fileClient: &mockFileClient{files: []*gitprovider.CommitFile{
+				{Path: gitprovider.StringVar("clusters/flux-system/gotk-components.yaml"), Content: gitprovider.StringVar(components)},
+				{Path: gitprovider.StringVar("clusters/flux-system/gotk-sync.yaml"), Content: gitprovider.StringVar(sync)},
+				{Path: gitprovider.StringVar("clusters/flux-system/kustomization.yaml"), Content: gitprovider.StringVar(kustomization)},
+				{Path: gitprovider.StringVar("clusters/flux-system/flux-alerts.yaml"), Content: gitprovider.StringVar(alerts)},
+			}},
+		},
+		options: options{
+			kubeclient: &noMatchClient{Client: kubeClient, notInstalled: "Alert"},
+			targetPath: "clusters",
+		},
+	}
+
+	drift, err := b.CompareWithCluster(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []DriftItem{
+		{
+			Resource:  "Deployment/flux-system/source-controller",
+			Field:     `metadata.labels["app.kubernetes.io/part-of"]`,
+			Committed: "flux",
+			Live:      "other",
+		},
+	}, drift)
+}
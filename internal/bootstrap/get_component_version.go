@@ -4,15 +4,19 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/containers/image/v5/pkg/compression"
+	ociname "github.com/google/go-containerregistry/pkg/name"
 	"github.com/open-component-model/ocm/pkg/contexts/ocm"
 	"github.com/open-component-model/ocm/pkg/contexts/ocm/accessmethods/ociartifact"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
 )
 
-// getComponentVersion returns the component version matching the given version constraint.
+// getComponentVersion returns the highest component version matching the given version constraint.
+// Stable releases are preferred over pre-releases if both match.
 func getComponentVersion(repository ocm.Repository, componentName, version string) (ocm.ComponentVersionAccess, error) {
 	c, err := repository.LookupComponent(componentName)
 	if err != nil {
@@ -26,77 +30,135 @@ func getComponentVersion(repository ocm.Repository, componentName, version strin
 	if err != nil {
 		return nil, err
 	}
-	var ver *semver.Version
+
+	ver, err := highestMatchingVersion(vnames, constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	cv, err := c.LookupVersion(ver.Original())
+	if err != nil {
+		return nil, err
+	}
+	return cv, nil
+}
+
+// highestMatchingVersion returns the highest version satisfying the constraint.
+// The highest stable version is returned if any stable version matches.
+func highestMatchingVersion(vnames []string, constraint *semver.Constraints) (*semver.Version, error) {
+	var matching semver.Collection
 	for _, vname := range vnames {
 		v, err := semver.NewVersion(vname)
 		if err != nil {
 			return nil, err
 		}
 		if constraint.Check(v) {
-			ver = v
-			break
+			matching = append(matching, v)
 		}
 	}
 
-	if ver == nil {
+	if len(matching) == 0 {
 		return nil, errors.New("no matching version found")
 	}
 
-	cv, err := c.LookupVersion(ver.Original())
-	if err != nil {
-		return nil, err
-	}
-	return cv, nil
+	sort.SliceStable(matching, func(i, j int) bool {
+		iStable, jStable := matching[i].Prerelease() == "", matching[j].Prerelease() == ""
+		if iStable != jStable {
+			return !iStable
+		}
+		return matching[i].LessThan(matching[j])
+	})
+
+	return matching[len(matching)-1], nil
 }
 
-// resources contains the resources extracted from the component version
+// resources contains the resources extracted from the component version.
+// The readers must be closed by calling Close.
 type resources struct {
-	componentResource []byte
-	ocmConfig         []byte
+	componentResource io.ReadCloser
+	ocmConfig         io.ReadCloser
 	imagesResources   map[string]nameTag
 	componentList     []string
+	// helmCharts holds the packaged Helm charts of the component by resource name
+	helmCharts map[string][]byte
+	// extraIdentity is the extra identity of the component resource
+	extraIdentity map[string]string
+}
+
+// Close closes the readers of the resources.
+func (r resources) Close() error {
+	var errs []error
+	for _, rc := range []io.ReadCloser{r.componentResource, r.ocmConfig} {
+		if rc != nil {
+			errs = append(errs, rc.Close())
+		}
+	}
+	return errors.Join(errs...)
 }
 
 type nameTag struct {
-	Name string
-	Tag  string
+	Name   string
+	Tag    string
+	Digest string
 }
 
-func getResources(cv ocm.ComponentVersionAccess, componentName string) (resources, error) {
+// getResources returns the resources of the given component version.
+// The content of the component and ocm-config resources is limited to maxSize bytes if maxSize is positive.
+// Resources whose extra identity or labels do not match all entries of filter are skipped.
+func getResources(cv ocm.ComponentVersionAccess, componentName string, maxSize int64, filter map[string]string) (_ resources, err error) {
 	res := cv.GetResources()
 	var (
-		componentResource []byte
-		ocmConfig         []byte
+		componentResource io.ReadCloser
+		ocmConfig         io.ReadCloser
 		imagesResources   = make(map[string]nameTag, 0)
 		comps             = make([]string, 0)
-		err               error
+		helmCharts        = make(map[string][]byte)
+		extraIdentity     map[string]string
 	)
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, resources{componentResource: componentResource, ocmConfig: ocmConfig}.Close())
+		}
+	}()
+
 	for _, resource := range res {
+		if !matchesResourceFilter(resource.Meta(), filter) {
+			continue
+		}
+
 		switch resource.Meta().GetName() {
 		case componentName:
-			componentResource, err = getResourceContent(resource)
+			componentResource, err = getResourceContent(resource, maxSize)
 			if err != nil {
 				return resources{}, err
 			}
+			extraIdentity = resource.Meta().ExtraIdentity
 		case "ocm-config":
-			ocmConfig, err = getResourceContent(resource)
+			ocmConfig, err = getResourceContent(resource, maxSize)
 			if err != nil {
 				return resources{}, err
 			}
 		default:
-			if resource.Meta().GetType() == "ociImage" {
-				name, version, err := getResourceRef(resource)
+			switch resource.Meta().GetType() {
+			case "ociImage":
+				var name, version, digest string
+				name, version, digest, err = getResourceRef(resource)
 				if err != nil {
 					return resources{}, fmt.Errorf("failed to get resource reference: %w", err)
 				}
-				imagesResources[resource.Meta().GetName()] = struct {
-					Name string
-					Tag  string
-				}{
-					Name: name,
-					Tag:  version,
+				imagesResources[resource.Meta().GetName()] = nameTag{
+					Name:   name,
+					Tag:    version,
+					Digest: digest,
 				}
 				comps = append(comps, resource.Meta().GetName())
+			case helmChartType:
+				var chart []byte
+				chart, err = readResourceBlob(resource, maxSize)
+				if err != nil {
+					return resources{}, fmt.Errorf("failed to read helm chart %s: %w", resource.Meta().GetName(), err)
+				}
+				helmCharts[resource.Meta().GetName()] = chart
 			}
 		}
 	}
@@ -105,10 +167,14 @@ func getResources(cv ocm.ComponentVersionAccess, componentName string) (resource
 		ocmConfig:         ocmConfig,
 		imagesResources:   imagesResources,
 		componentList:     comps,
+		helmCharts:        helmCharts,
+		extraIdentity:     extraIdentity,
 	}, nil
 }
 
-func getResourceContent(resource ocm.ResourceAccess) ([]byte, error) {
+// readResourceBlob reads the content of the given resource as is, without decompressing it.
+// Reading fails once more than maxSize bytes are read if maxSize is positive.
+func readResourceBlob(resource ocm.ResourceAccess, maxSize int64) (_ []byte, err error) {
 	access, err := resource.AccessMethod()
 	if err != nil {
 		return nil, err
@@ -118,33 +184,146 @@ func getResourceContent(resource ocm.ResourceAccess) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	defer reader.Close()
+	defer func() {
+		err = errors.Join(err, reader.Close())
+	}()
 
-	decompressedReader, _, err := compression.AutoDecompress(reader)
+	var r io.Reader = reader
+	if maxSize > 0 {
+		r = newMaxSizeReader(reader, resource.Meta().GetName(), maxSize)
+	}
+
+	return io.ReadAll(r)
+}
+
+// matchesResourceFilter returns true if each entry of filter matches either an extra identity
+// attribute or a string label of the resource.
+func matchesResourceFilter(meta *compdesc.ResourceMeta, filter map[string]string) bool {
+	for key, value := range filter {
+		if id, ok := meta.ExtraIdentity[key]; ok && id == value {
+			continue
+		}
+
+		var label string
+		if ok, err := meta.Labels.GetValue(key, &label); err == nil && ok && label == value {
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// getResourceContent returns a reader for the decompressed content of the given resource.
+// Reading fails once more than maxSize bytes are read if maxSize is positive.
+func getResourceContent(resource ocm.ResourceAccess, maxSize int64) (io.ReadCloser, error) {
+	access, err := resource.AccessMethod()
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := access.Reader()
 	if err != nil {
 		return nil, err
 	}
-	defer decompressedReader.Close()
 
-	return io.ReadAll(decompressedReader)
+	decompressedReader, _, err := compression.AutoDecompress(reader)
+	if err != nil {
+		return nil, errors.Join(err, reader.Close())
+	}
+
+	rc := &resourceReader{
+		Reader:  decompressedReader,
+		closers: []io.Closer{decompressedReader, reader},
+	}
+	if maxSize > 0 {
+		rc.Reader = newMaxSizeReader(decompressedReader, resource.Meta().GetName(), maxSize)
+	}
+
+	return rc, nil
+}
+
+// resourceReader reads the content of a resource and closes all underlying readers on Close.
+type resourceReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *resourceReader) Close() error {
+	var errs []error
+	for _, c := range r.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// maxSizeReader returns an error once more than max bytes are read.
+type maxSizeReader struct {
+	r    io.Reader
+	name string
+	max  int64
+	n    int64
+}
+
+func newMaxSizeReader(r io.Reader, name string, max int64) *maxSizeReader {
+	return &maxSizeReader{
+		// read one byte more than allowed to detect that the limit is exceeded
+		r:    io.LimitReader(r, max+1),
+		name: name,
+		max:  max,
+	}
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	n, err := m.r.Read(p)
+	m.n += int64(n)
+	if m.n > m.max {
+		return n, fmt.Errorf("resource %s exceeds the maximum size of %d bytes", m.name, m.max)
+	}
+	return n, err
 }
 
-func getResourceRef(resource ocm.ResourceAccess) (string, string, error) {
+// getResourceRef returns the name, tag and digest of the image referenced by the given resource.
+func getResourceRef(resource ocm.ResourceAccess) (string, string, string, error) {
 	a, err := resource.Access()
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
 	spec, ok := a.(*ociartifact.AccessSpec)
 	if !ok {
-		return "", "", fmt.Errorf("access spec was of type %+v; expected ociartifact", a)
+		return "", "", "", fmt.Errorf("access spec was of type %+v; expected ociartifact", a)
+	}
+
+	return parseImageReference(spec.ImageReference)
+}
+
+// parseImageReference splits the given image reference into name, tag and digest.
+// The tag is empty for digest references without a tag.
+func parseImageReference(image string) (string, string, string, error) {
+	base, _, hasDigest := strings.Cut(image, "@")
+
+	var tag string
+	if i := strings.LastIndex(base, ":"); i > strings.LastIndex(base, "/") {
+		tag = base[i+1:]
+	}
+
+	if hasDigest {
+		d, err := ociname.NewDigest(image)
+		if err != nil {
+			return "", "", "", fmt.Errorf("failed to parse image reference %s: %w", image, err)
+		}
+		return d.Context().Name(), tag, d.DigestStr(), nil
 	}
 
-	im := spec.ImageReference
-	split := strings.Split(im, ":")
-	if len(split) != 2 {
-		return "", "", fmt.Errorf("expected image format of domain:verion but was: %s", im)
+	if tag == "" {
+		return "", "", "", fmt.Errorf("expected image reference with tag or digest but was: %s", image)
+	}
+
+	t, err := ociname.NewTag(image)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to parse image reference %s: %w", image, err)
 	}
 
-	name, version := split[0], split[1]
-	return name, version, nil
+	return t.Context().Name(), t.TagStr(), "", nil
 }
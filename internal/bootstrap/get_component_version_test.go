@@ -0,0 +1,284 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/open-component-model/ocm-controller/pkg/fakes"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	ocmmetav1 "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/meta/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HighestMatchingVersion(t *testing.T) {
+	testCases := []struct {
+		name        string
+		versions    []string
+		constraint  string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:       "unordered versions",
+			versions:   []string{"v1.0.0", "v1.2.0", "v1.1.0"},
+			constraint: ">=v1.0.0",
+			expected:   "v1.2.0",
+		},
+		{
+			name:       "constraint limits the highest version",
+			versions:   []string{"v2.0.0", "v1.0.0", "v1.1.0"},
+			constraint: "<v2.0.0",
+			expected:   "v1.1.0",
+		},
+		{
+			name:       "pre-releases are filtered without pre-release constraint",
+			versions:   []string{"v1.0.0", "v1.1.0-rc.1"},
+			constraint: ">=v1.0.0",
+			expected:   "v1.0.0",
+		},
+		{
+			name:       "stable releases are preferred over higher pre-releases",
+			versions:   []string{"v1.1.0-rc.1", "v1.0.0", "v1.1.0-rc.2"},
+			constraint: ">=v1.0.0-0",
+			expected:   "v1.0.0",
+		},
+		{
+			name:       "highest pre-release if no stable release matches",
+			versions:   []string{"v1.1.0-rc.2", "v1.1.0-rc.10", "v1.1.0-rc.1"},
+			constraint: ">=v1.1.0-0",
+			expected:   "v1.1.0-rc.10",
+		},
+		{
+			name:        "no matching version",
+			versions:    []string{"v1.0.0"},
+			constraint:  ">=v2.0.0",
+			expectedErr: "no matching version found",
+		},
+		{
+			name:        "no versions",
+			constraint:  ">=v1.0.0",
+			expectedErr: "no matching version found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			constraint, err := semver.NewConstraint(tc.constraint)
+			require.NoError(t, err)
+
+			v, err := highestMatchingVersion(tc.versions, constraint)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, v.Original())
+		})
+	}
+}
+
+func Test_GetComponentVersion(t *testing.T) {
+	repo := &mockRepository{
+		cv: []*mockComponentAccess{
+			{
+				name:     "ocm.software/ocm-controller",
+				versions: []string{"v1.0.0", "v1.2.0", "v1.1.0"},
+				cva: map[string]*fakes.Component{
+					"v1.0.0": {Name: "ocm.software/ocm-controller", Version: "v1.0.0"},
+					"v1.1.0": {Name: "ocm.software/ocm-controller", Version: "v1.1.0"},
+					"v1.2.0": {Name: "ocm.software/ocm-controller", Version: "v1.2.0"},
+				},
+			},
+		},
+	}
+
+	cv, err := getComponentVersion(repo, "ocm.software/ocm-controller", ">=v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0", cv.GetVersion())
+}
+
+func Test_ParseImageReference(t *testing.T) {
+	testCases := []struct {
+		name           string
+		image          string
+		expectedName   string
+		expectedTag    string
+		expectedDigest string
+		expectedErr    string
+	}{
+		{
+			name:         "tag only",
+			image:        "ghcr.io/open-component-model/ocm-controller:v0.16.1",
+			expectedName: "ghcr.io/open-component-model/ocm-controller",
+			expectedTag:  "v0.16.1",
+		},
+		{
+			name:         "port in registry",
+			image:        "localhost:5000/img:tag",
+			expectedName: "localhost:5000/img",
+			expectedTag:  "tag",
+		},
+		{
+			name:           "digest only",
+			image:          "ghcr.io/open-component-model/ocm-controller@sha256:7f0a3f8c5e9e4a3ad0f4d4d7e3c1e08a5ab2f2b1e7a4d9b1a2ccd0d6a8b0c2e1",
+			expectedName:   "ghcr.io/open-component-model/ocm-controller",
+			expectedDigest: "sha256:7f0a3f8c5e9e4a3ad0f4d4d7e3c1e08a5ab2f2b1e7a4d9b1a2ccd0d6a8b0c2e1",
+		},
+		{
+			name:           "tag and digest with port in registry",
+			image:          "localhost:5000/img:v1.0.0@sha256:7f0a3f8c5e9e4a3ad0f4d4d7e3c1e08a5ab2f2b1e7a4d9b1a2ccd0d6a8b0c2e1",
+			expectedName:   "localhost:5000/img",
+			expectedTag:    "v1.0.0",
+			expectedDigest: "sha256:7f0a3f8c5e9e4a3ad0f4d4d7e3c1e08a5ab2f2b1e7a4d9b1a2ccd0d6a8b0c2e1",
+		},
+		{
+			name:        "missing tag and digest",
+			image:       "localhost:5000/img",
+			expectedErr: "expected image reference with tag or digest",
+		},
+		{
+			name:        "invalid digest",
+			image:       "ghcr.io/open-component-model/ocm-controller@sha256:invalid",
+			expectedErr: "failed to parse image reference",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			name, tag, digest, err := parseImageReference(tc.image)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedTag, tag)
+			assert.Equal(t, tc.expectedDigest, digest)
+		})
+	}
+}
+
+func TestGetResourcesMaxSize(t *testing.T) {
+	componentName := "ocm.software/mpas/git-controller"
+	cv, err := getComponentVersion(newKustomizeTestRepository(componentName), componentName, "v1.0.0")
+	require.NoError(t, err)
+
+	res, err := getResources(cv, componentName, int64(len(testComponentData)), nil)
+	require.NoError(t, err)
+	data, err := io.ReadAll(res.componentResource)
+	require.NoError(t, err)
+	assert.Equal(t, testComponentData, data)
+	require.NoError(t, res.Close())
+
+	res, err = getResources(cv, componentName, 10, nil)
+	require.NoError(t, err)
+	_, err = io.ReadAll(res.componentResource)
+	require.ErrorContains(t, err, "resource ocm.software/mpas/git-controller exceeds the maximum size of 10 bytes")
+	require.NoError(t, res.Close())
+}
+
+func TestMaxSizeReader(t *testing.T) {
+	data, err := io.ReadAll(newMaxSizeReader(strings.NewReader("hello"), "test", 5))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	_, err = io.ReadAll(newMaxSizeReader(strings.NewReader("hello world"), "test", 5))
+	require.ErrorContains(t, err, "resource test exceeds the maximum size of 5 bytes")
+}
+
+// zeroReader returns an endless stream of zero bytes.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+const benchmarkResourceSize = 200 * 1024 * 1024
+
+// BenchmarkResourceStreaming copies a simulated 200 MB resource. Compared to
+// BenchmarkResourceReadAll the allocated bytes per operation stay constant.
+func BenchmarkResourceStreaming(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r := newMaxSizeReader(io.LimitReader(zeroReader{}, benchmarkResourceSize), "flux", benchmarkResourceSize)
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkResourceReadAll(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := io.ReadAll(io.LimitReader(zeroReader{}, benchmarkResourceSize)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMatchesResourceFilter(t *testing.T) {
+	newMeta := func(identity ocmmetav1.Identity, labels ...ocmmetav1.Label) *compdesc.ResourceMeta {
+		return &compdesc.ResourceMeta{
+			ElementMeta: compdesc.ElementMeta{
+				Name:          "flux",
+				Version:       "v2.0.0",
+				ExtraIdentity: identity,
+				Labels:        labels,
+			},
+		}
+	}
+
+	// resources with the same name are disambiguated by extra identity or label
+	staging := newMeta(ocmmetav1.Identity{"environment": "staging"})
+	production := newMeta(ocmmetav1.Identity{"environment": "production"})
+	labeled := newMeta(nil,
+		ocmmetav1.Label{Name: "environment", Value: json.RawMessage(`"production"`)},
+		ocmmetav1.Label{Name: "region", Value: json.RawMessage(`"eu"`)},
+	)
+	plain := newMeta(nil)
+
+	testCases := []struct {
+		name     string
+		filter   map[string]string
+		expected []bool
+	}{
+		{
+			name:     "no filter matches all resources",
+			expected: []bool{true, true, true, true},
+		},
+		{
+			name:     "extra identity or label",
+			filter:   map[string]string{"environment": "production"},
+			expected: []bool{false, true, true, false},
+		},
+		{
+			name:     "all entries must match",
+			filter:   map[string]string{"environment": "production", "region": "eu"},
+			expected: []bool{false, false, true, false},
+		},
+		{
+			name:     "unknown value",
+			filter:   map[string]string{"environment": "development"},
+			expected: []bool{false, false, false, false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var matches []bool
+			for _, meta := range []*compdesc.ResourceMeta{staging, production, labeled, plain} {
+				matches = append(matches, matchesResourceFilter(meta, tc.filter))
+			}
+			assert.Equal(t, tc.expected, matches)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/mpas/internal/env"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// githubActionsFileName is the path of the GitHub Actions workflow file.
+	githubActionsFileName = ".github/workflows/mpas-reconcile.yaml"
+	// defaultGitHubActionsSchedule runs the workflow every hour.
+	defaultGitHubActionsSchedule = "0 * * * *"
+	// defaultGitHubActionsRunner is the runner the workflow runs on by default.
+	defaultGitHubActionsRunner = "ubuntu-latest"
+)
+
+// GHActionsSpec configures the GitHub Actions workflow committed to the management repository.
+type GHActionsSpec struct {
+	// Image is the container image providing the mpas binary.
+	Image string `json:"image"`
+	// Branches are the branches that trigger the workflow on push. Defaults to the default branch.
+	Branches []string `json:"branches,omitempty"`
+	// Schedule is the cron schedule the workflow runs on. Defaults to every hour.
+	Schedule string `json:"schedule,omitempty"`
+	// RunsOn is the runner of the job. Defaults to ubuntu-latest.
+	RunsOn string `json:"runsOn,omitempty"`
+}
+
+// WithGitHubActions commits a GitHub Actions workflow running mpas reconcile on push and on a schedule
+// to the management repository. It only has an effect for GitHub repositories.
+func WithGitHubActions(workflowSpec GHActionsSpec) Option {
+	return func(o *options) {
+		o.githubActions = &workflowSpec
+	}
+}
+
+type githubWorkflow struct {
+	Name string               `json:"name"`
+	On   githubTriggers       `json:"on"`
+	Jobs map[string]githubJob `json:"jobs"`
+}
+
+type githubTriggers struct {
+	Push     githubPush       `json:"push"`
+	Schedule []githubSchedule `json:"schedule"`
+}
+
+type githubPush struct {
+	Branches []string `json:"branches"`
+}
+
+type githubSchedule struct {
+	Cron string `json:"cron"`
+}
+
+type githubJob struct {
+	RunsOn    string          `json:"runs-on"`
+	Container githubContainer `json:"container"`
+	Steps     []githubStep    `json:"steps"`
+}
+
+type githubContainer struct {
+	Image string `json:"image"`
+}
+
+type githubStep struct {
+	Uses string `json:"uses,omitempty"`
+	Run  string `json:"run,omitempty"`
+}
+
+// reconcileGitHubActions commits the GitHub Actions workflow to the management repository
+// if the provider is GitHub and the workflow is set.
+func (b *Bootstrap) reconcileGitHubActions(ctx context.Context) error {
+	if b.githubActions == nil || string(b.providerClient.ProviderID()) != env.ProviderGithub {
+		return nil
+	}
+
+	data, err := generateGitHubActions(*b.githubActions, b.defaultBranch)
+	if err != nil {
+		return err
+	}
+
+	if b.dryRun {
+		printDryRunPreview(b.printer, githubActionsFileName, data)
+		return nil
+	}
+
+	files := []gitprovider.CommitFile{
+		{
+			Path:    ptr.To(githubActionsFileName),
+			Content: ptr.To(string(data)),
+		},
+	}
+	if _, err := b.repository.Commits().Create(ctx, b.defaultBranch, "Add GitHub Actions workflow", files); err != nil {
+		return fmt.Errorf("failed to commit %s: %w", githubActionsFileName, err)
+	}
+
+	return nil
+}
+
+func generateGitHubActions(spec GHActionsSpec, defaultBranch string) ([]byte, error) {
+	branches := spec.Branches
+	if len(branches) == 0 {
+		branches = []string{defaultBranch}
+	}
+
+	schedule := spec.Schedule
+	if schedule == "" {
+		schedule = defaultGitHubActionsSchedule
+	}
+
+	runsOn := spec.RunsOn
+	if runsOn == "" {
+		runsOn = defaultGitHubActionsRunner
+	}
+
+	data, err := yaml.Marshal(githubWorkflow{
+		Name: "mpas-reconcile",
+		On: githubTriggers{
+			Push:     githubPush{Branches: branches},
+			Schedule: []githubSchedule{{Cron: schedule}},
+		},
+		Jobs: map[string]githubJob{
+			"reconcile": {
+				RunsOn:    runsOn,
+				Container: githubContainer{Image: spec.Image},
+				Steps: []githubStep{
+					{Uses: "actions/checkout@v4"},
+					{Run: "mpas reconcile"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal github actions workflow: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestReconcileGitHubActions(t *testing.T) {
+	testCases := []struct {
+		name       string
+		providerID gitprovider.ProviderID
+		spec       *GHActionsSpec
+		expected   string
+	}{
+		{
+			name:       "github with defaults",
+			providerID: "github",
+			spec:       &GHActionsSpec{Image: "ghcr.io/open-component-model/mpas:v0.1.0"},
+			expected: `jobs:
+  reconcile:
+    container:
+      image: ghcr.io/open-component-model/mpas:v0.1.0
+    runs-on: ubuntu-latest
+    steps:
+    - uses: actions/checkout@v4
+    - run: mpas reconcile
+name: mpas-reconcile
+"on":
+  push:
+    branches:
+    - main
+  schedule:
+  - cron: 0 * * * *
+`,
+		},
+		{
+			name:       "github with branches, schedule and runner",
+			providerID: "github",
+			spec: &GHActionsSpec{
+				Image:    "ghcr.io/open-component-model/mpas:v0.1.0",
+				Branches: []string{"main", "release"},
+				Schedule: "30 2 * * *",
+				RunsOn:   "self-hosted",
+			},
+			expected: `jobs:
+  reconcile:
+    container:
+      image: ghcr.io/open-component-model/mpas:v0.1.0
+    runs-on: self-hosted
+    steps:
+    - uses: actions/checkout@v4
+    - run: mpas reconcile
+name: mpas-reconcile
+"on":
+  push:
+    branches:
+    - main
+    - release
+  schedule:
+  - cron: 30 2 * * *
+`,
+		},
+		{
+			name:       "other provider",
+			providerID: "gitlab",
+			spec:       &GHActionsSpec{Image: "ghcr.io/open-component-model/mpas:v0.1.0"},
+		},
+		{
+			name:       "option not set",
+			providerID: "github",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := &mockCommitClient{commit: &mockCommit{sha: "sha"}}
+			b := &Bootstrap{
+				providerClient: &mockProviderClient{providerID: tc.providerID},
+				repository:     &mockGitRepository{commitClient: mc},
+				options: options{
+					defaultBranch: "main",
+					githubActions: tc.spec,
+				},
+			}
+
+			require.NoError(t, b.reconcileGitHubActions(context.Background()))
+			if tc.expected == "" {
+				assert.Empty(t, mc.calledWidth)
+				return
+			}
+
+			require.Len(t, mc.calledWidth, 1)
+			args := mc.calledWidth[0]
+			assert.Equal(t, "main", args[0])
+			assert.Equal(t, []gitprovider.CommitFile{
+				{
+					Path:    ptr.To(".github/workflows/mpas-reconcile.yaml"),
+					Content: ptr.To(tc.expected),
+				},
+			}, args[2])
+		})
+	}
+}
+
+func TestReconcileGitHubActionsDryRun(t *testing.T) {
+	out := &bytes.Buffer{}
+	p, err := printer.Newprinter(out)
+	require.NoError(t, err)
+
+	mc := &mockCommitClient{commit: &mockCommit{sha: "sha"}}
+	b := &Bootstrap{
+		providerClient: &mockProviderClient{providerID: "github"},
+		repository:     &mockGitRepository{commitClient: mc},
+		options: options{
+			defaultBranch: "main",
+			githubActions: &GHActionsSpec{Image: "ghcr.io/open-component-model/mpas:v0.1.0"},
+			dryRun:        true,
+			printer:       p,
+		},
+	}
+
+	require.NoError(t, b.reconcileGitHubActions(context.Background()))
+	assert.Empty(t, mc.calledWidth)
+	assert.Contains(t, out.String(), "--- [dry-run] .github/workflows/mpas-reconcile.yaml ---")
+	assert.Contains(t, out.String(), "mpas reconcile")
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/mpas/internal/env"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+// gitlabCIFileName is the name of the GitLab CI/CD config file.
+const gitlabCIFileName = ".gitlab-ci.yml"
+
+// GitLabCISpec configures the GitLab CI/CD config committed to the management repository.
+type GitLabCISpec struct {
+	// Image is the container image providing the mpas binary.
+	Image string `json:"image"`
+	// Branches are the branches that trigger the pipeline on push. Defaults to the default branch.
+	Branches []string `json:"branches,omitempty"`
+	// Tags select the runners of the job.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// WithGitLabCI commits a GitLab CI/CD config running mpas reconcile on push
+// to the management repository. It only has an effect for GitLab repositories.
+func WithGitLabCI(ciSpec GitLabCISpec) Option {
+	return func(o *options) {
+		o.gitlabCI = &ciSpec
+	}
+}
+
+type gitlabCI struct {
+	Stages    []string  `json:"stages"`
+	Reconcile gitlabJob `json:"mpas-reconcile"`
+}
+
+type gitlabJob struct {
+	Stage  string       `json:"stage"`
+	Image  string       `json:"image"`
+	Tags   []string     `json:"tags,omitempty"`
+	Script []string     `json:"script"`
+	Rules  []gitlabRule `json:"rules"`
+}
+
+type gitlabRule struct {
+	If string `json:"if"`
+}
+
+// reconcileGitLabCI commits the GitLab CI/CD config to the management repository
+// if the provider is GitLab and the config is set.
+func (b *Bootstrap) reconcileGitLabCI(ctx context.Context) error {
+	if b.gitlabCI == nil || string(b.providerClient.ProviderID()) != env.ProviderGitlab {
+		return nil
+	}
+
+	data, err := generateGitLabCI(*b.gitlabCI, b.defaultBranch)
+	if err != nil {
+		return err
+	}
+
+	if b.dryRun {
+		printDryRunPreview(b.printer, gitlabCIFileName, data)
+		return nil
+	}
+
+	files := []gitprovider.CommitFile{
+		{
+			Path:    ptr.To(gitlabCIFileName),
+			Content: ptr.To(string(data)),
+		},
+	}
+	if _, err := b.repository.Commits().Create(ctx, b.defaultBranch, "Add GitLab CI/CD configuration", files); err != nil {
+		return fmt.Errorf("failed to commit %s: %w", gitlabCIFileName, err)
+	}
+
+	return nil
+}
+
+func generateGitLabCI(spec GitLabCISpec, defaultBranch string) ([]byte, error) {
+	branches := spec.Branches
+	if len(branches) == 0 {
+		branches = []string{defaultBranch}
+	}
+
+	job := gitlabJob{
+		Stage:  "reconcile",
+		Image:  spec.Image,
+		Tags:   spec.Tags,
+		Script: []string{"mpas reconcile"},
+	}
+	for _, branch := range branches {
+		job.Rules = append(job.Rules, gitlabRule{
+			If: fmt.Sprintf(`$CI_PIPELINE_SOURCE == "push" && $CI_COMMIT_BRANCH == %q`, branch),
+		})
+	}
+
+	data, err := yaml.Marshal(gitlabCI{
+		Stages:    []string{"reconcile"},
+		Reconcile: job,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal gitlab ci config: %w", err)
+	}
+
+	return data, nil
+}
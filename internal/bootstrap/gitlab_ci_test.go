@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestReconcileGitLabCI(t *testing.T) {
+	testCases := []struct {
+		name       string
+		providerID gitprovider.ProviderID
+		spec       *GitLabCISpec
+		expected   string
+	}{
+		{
+			name:       "gitlab with default branch",
+			providerID: "gitlab",
+			spec:       &GitLabCISpec{Image: "ghcr.io/open-component-model/mpas:v0.1.0"},
+			expected: `mpas-reconcile:
+  image: ghcr.io/open-component-model/mpas:v0.1.0
+  rules:
+  - if: $CI_PIPELINE_SOURCE == "push" && $CI_COMMIT_BRANCH == "main"
+  script:
+  - mpas reconcile
+  stage: reconcile
+stages:
+- reconcile
+`,
+		},
+		{
+			name:       "gitlab with branches and tags",
+			providerID: "gitlab",
+			spec: &GitLabCISpec{
+				Image:    "ghcr.io/open-component-model/mpas:v0.1.0",
+				Branches: []string{"main", "release"},
+				Tags:     []string{"docker"},
+			},
+			expected: `mpas-reconcile:
+  image: ghcr.io/open-component-model/mpas:v0.1.0
+  rules:
+  - if: $CI_PIPELINE_SOURCE == "push" && $CI_COMMIT_BRANCH == "main"
+  - if: $CI_PIPELINE_SOURCE == "push" && $CI_COMMIT_BRANCH == "release"
+  script:
+  - mpas reconcile
+  stage: reconcile
+  tags:
+  - docker
+stages:
+- reconcile
+`,
+		},
+		{
+			name:       "other provider",
+			providerID: "github",
+			spec:       &GitLabCISpec{Image: "ghcr.io/open-component-model/mpas:v0.1.0"},
+		},
+		{
+			name:       "option not set",
+			providerID: "gitlab",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mc := &mockCommitClient{commit: &mockCommit{sha: "sha"}}
+			b := &Bootstrap{
+				providerClient: &mockProviderClient{providerID: tc.providerID},
+				repository:     &mockGitRepository{commitClient: mc},
+				options: options{
+					defaultBranch: "main",
+					gitlabCI:      tc.spec,
+				},
+			}
+
+			require.NoError(t, b.reconcileGitLabCI(context.Background()))
+			if tc.expected == "" {
+				assert.Empty(t, mc.calledWidth)
+				return
+			}
+
+			require.Len(t, mc.calledWidth, 1)
+			args := mc.calledWidth[0]
+			assert.Equal(t, "main", args[0])
+			assert.Equal(t, []gitprovider.CommitFile{
+				{
+					Path:    ptr.To(".gitlab-ci.yml"),
+					Content: ptr.To(tc.expected),
+				},
+			}, args[2])
+		})
+	}
+}
+
+func TestReconcileGitLabCIDryRun(t *testing.T) {
+	out := &bytes.Buffer{}
+	p, err := printer.Newprinter(out)
+	require.NoError(t, err)
+
+	mc := &mockCommitClient{commit: &mockCommit{sha: "sha"}}
+	b := &Bootstrap{
+		providerClient: &mockProviderClient{providerID: "gitlab"},
+		repository:     &mockGitRepository{commitClient: mc},
+		options: options{
+			defaultBranch: "main",
+			gitlabCI:      &GitLabCISpec{Image: "ghcr.io/open-component-model/mpas:v0.1.0"},
+			dryRun:        true,
+			printer:       p,
+		},
+	}
+
+	require.NoError(t, b.reconcileGitLabCI(context.Background()))
+	assert.Empty(t, mc.calledWidth)
+	assert.Contains(t, out.String(), "--- [dry-run] .gitlab-ci.yml ---")
+	assert.Contains(t, out.String(), "mpas reconcile")
+}
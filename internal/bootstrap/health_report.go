@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/open-component-model/mpas/internal/env"
+	"golang.org/x/sync/errgroup"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ComponentHealthReport is the health of a single controller or Flux object.
+type ComponentHealthReport struct {
+	// Component is the controller deployment or Flux object, e.g. Kustomization/flux-system.
+	Component string
+	// Ready is true if the deployment is available or the object's Ready condition is True.
+	Ready bool
+	// Message describes the status.
+	Message string
+}
+
+// HealthReportError is returned if the health check of the flux installation fails.
+// Reports holds the health of each controller at the time of the failure.
+type HealthReportError struct {
+	Reports []ComponentHealthReport
+	Err     error
+}
+
+func (e *HealthReportError) Error() string {
+	var notReady []string
+	for _, r := range e.Reports {
+		if !r.Ready {
+			notReady = append(notReady, fmt.Sprintf("%s: %s", r.Component, r.Message))
+		}
+	}
+	if len(notReady) == 0 {
+		return e.Err.Error()
+	}
+
+	return fmt.Sprintf("%s\n%s", e.Err, strings.Join(notReady, "\n"))
+}
+
+func (e *HealthReportError) Unwrap() error {
+	return e.Err
+}
+
+// HealthCheck reports the health of the flux sync Kustomization and of each flux controller.
+func (f *fluxInstall) HealthCheck(ctx context.Context) ([]ComponentHealthReport, error) {
+	report, err := kustomizationHealth(ctx, f.kubeClient, client.ObjectKey{Name: f.namespace, Namespace: f.namespace})
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := deploymentHealth(ctx, f.kubeClient, f.namespace, f.components)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]ComponentHealthReport{report}, deployments...), nil
+}
+
+// HealthReport reports the health of the controllers of all installed components, similar to
+// flux check. The installed components are read from the lock file.
+func (b *Bootstrap) HealthReport(ctx context.Context) ([]ComponentHealthReport, error) {
+	store := b.lockStore()
+	if store == nil {
+		return nil, fmt.Errorf("no lock file store is configured")
+	}
+
+	lock, err := store.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []ComponentHealthReport
+	for _, entry := range lock.Components {
+		var (
+			namespace   string
+			deployments []string
+		)
+		switch entry.Name {
+		case env.FluxName:
+			namespace = b.componentNamespace(env.FluxName, env.DefaultFluxNamespace)
+			report, err := kustomizationHealth(ctx, b.kubeclient, client.ObjectKey{Name: namespace, Namespace: namespace})
+			if err != nil {
+				return nil, err
+			}
+			reports = append(reports, report)
+		case env.CertManagerName:
+			namespace = env.DefaultCertManagerNamespace
+			deployments = []string{certManager, certManagerCAInjector, certManagerWebhook}
+		default:
+			namespace, deployments, err = b.componentDeployments(entry.Name)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		componentReports, err := deploymentHealth(ctx, b.kubeclient, namespace, deployments)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, componentReports...)
+	}
+
+	return reports, nil
+}
+
+// kustomizationHealth reports the Ready condition of the Kustomization.
+func kustomizationHealth(ctx context.Context, c client.Client, key client.ObjectKey) (ComponentHealthReport, error) {
+	report := ComponentHealthReport{Component: fmt.Sprintf("%s/%s", kustomizev1.KustomizationKind, key.Name)}
+
+	kustomization := &kustomizev1.Kustomization{}
+	if err := c.Get(ctx, key, kustomization); err != nil {
+		if apierrors.IsNotFound(err) {
+			report.Message = "not found"
+			return report, nil
+		}
+		return ComponentHealthReport{}, fmt.Errorf("failed to get Kustomization %s: %w", key, err)
+	}
+
+	cond := apimeta.FindStatusCondition(kustomization.Status.Conditions, meta.ReadyCondition)
+	if cond == nil {
+		report.Message = "waiting to be reconciled"
+		return report, nil
+	}
+
+	report.Ready = cond.Status == "True"
+	report.Message = cond.Message
+	return report, nil
+}
+
+// deploymentHealth reports the availability of the given deployments in the namespace.
+// All deployments of the namespace are reported if names is empty.
+func deploymentHealth(ctx context.Context, c client.Client, namespace string, names []string) ([]ComponentHealthReport, error) {
+	var list appsv1.DeploymentList
+	if err := c.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list deployments in namespace %s: %w", namespace, err)
+	}
+
+	found := make(map[string]appsv1.Deployment, len(list.Items))
+	for _, d := range list.Items {
+		found[d.Name] = d
+	}
+
+	if len(names) == 0 {
+		for name := range found {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	reports := make([]ComponentHealthReport, 0, len(names))
+	for _, name := range names {
+		report := ComponentHealthReport{Component: fmt.Sprintf("Deployment/%s/%s", namespace, name)}
+
+		d, ok := found[name]
+		switch {
+		case !ok:
+			report.Message = "not found"
+		case d.Status.AvailableReplicas < ptr.Deref(d.Spec.Replicas, 1):
+			report.Message = fmt.Sprintf("%d/%d replicas available", d.Status.AvailableReplicas, ptr.Deref(d.Spec.Replicas, 1))
+		default:
+			report.Ready = true
+			report.Message = "available"
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// healthCheck waits until a part of the flux installation is healthy or ctx is done.
+type healthCheck func(ctx context.Context) error
+
+// runHealthChecks runs the checks in parallel, so that they are bounded by a single timeout
+// instead of one per check. The returned error joins the failures of all checks. Each check
+// gets its own context derived from the shared deadline, nothing else is shared between them.
+func runHealthChecks(ctx context.Context, timeout time.Duration, checks ...healthCheck) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	errs := make([]error, len(checks))
+	var g errgroup.Group
+	for i, check := range checks {
+		i, check := i, check
+		g.Go(func() error {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			errs[i] = check(ctx)
+			return nil
+		})
+	}
+	// the checks never return an error, so that all of them run to completion
+	_ = g.Wait()
+
+	return errors.Join(errs...)
+}
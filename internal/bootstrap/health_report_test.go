@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newHealthReportClient(t *testing.T) client.Client {
+	t.Helper()
+
+	deployment := func(name, namespace string, available int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](1)},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: available},
+		}
+	}
+	kustomization := &kustomizev1.Kustomization{
+		ObjectMeta: metav1.ObjectMeta{Name: env.DefaultFluxNamespace, Namespace: env.DefaultFluxNamespace},
+		Status: kustomizev1.KustomizationStatus{Conditions: []metav1.Condition{{
+			Type:    meta.ReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  "BuildFailed",
+			Message: "kustomization path not found",
+		}}},
+	}
+
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		kustomization,
+		deployment("source-controller", env.DefaultFluxNamespace, 1),
+		deployment("kustomize-controller", env.DefaultFluxNamespace, 0),
+		deployment("ocm-controller", env.DefaultOCMNamespace, 1),
+	).Build()
+}
+
+func TestFluxHealthCheck(t *testing.T) {
+	f := &fluxInstall{
+		fluxOptions: &fluxOptions{kubeClient: newHealthReportClient(t), namespace: env.DefaultFluxNamespace},
+		components:  []string{"source-controller", "kustomize-controller", "helm-controller"},
+	}
+
+	reports, err := f.HealthCheck(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []ComponentHealthReport{
+		{Component: "Kustomization/flux-system", Message: "kustomization path not found"},
+		{Component: "Deployment/flux-system/source-controller", Ready: true, Message: "available"},
+		{Component: "Deployment/flux-system/kustomize-controller", Message: "0/1 replicas available"},
+		{Component: "Deployment/flux-system/helm-controller", Message: "not found"},
+	}, reports)
+
+	err = &HealthReportError{Reports: reports, Err: errors.New("failed to report health")}
+	assert.EqualError(t, err, "failed to report health\n"+
+		"Kustomization/flux-system: kustomization path not found\n"+
+		"Deployment/flux-system/kustomize-controller: 0/1 replicas available\n"+
+		"Deployment/flux-system/helm-controller: not found")
+}
+
+func TestHealthReport(t *testing.T) {
+	lockFile := `components:
+- name: flux
+  componentName: ocm.software/mpas/flux
+  version: v2.1.0
+- name: ocm-controller
+  componentName: ocm.software/mpas/ocm-controller
+  version: v0.14.0
+`
+	b := &Bootstrap{
+		providerClient: &mockProviderClient{providerID: env.ProviderGitea},
+		repository: &mockGitRepository{fileClient: &mockFileClient{files: []*gitprovider.CommitFile{
+			{Path: ptr.To(lockFileName), Content: ptr.To(lockFile)},
+		}}},
+		options: options{defaultBranch: "main", kubeclient: newHealthReportClient(t)},
+	}
+
+	reports, err := b.HealthReport(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []ComponentHealthReport{
+		{Component: "Kustomization/flux-system", Message: "kustomization path not found"},
+		{Component: "Deployment/flux-system/kustomize-controller", Message: "0/1 replicas available"},
+		{Component: "Deployment/flux-system/source-controller", Ready: true, Message: "available"},
+		{Component: "Deployment/ocm-system/ocm-controller", Ready: true, Message: "available"},
+	}, reports)
+}
+
+func TestRunHealthChecks(t *testing.T) {
+	// slowCheck waits until its context is done, like a health check of a controller that never gets ready
+	var running atomic.Int32
+	slowCheck := func(name string) healthCheck {
+		return func(ctx context.Context) error {
+			running.Add(1)
+			<-ctx.Done()
+			return fmt.Errorf("%s: %w", name, ctx.Err())
+		}
+	}
+
+	timeout := 200 * time.Millisecond
+	start := time.Now()
+	err := runHealthChecks(context.Background(), timeout, slowCheck("kustomization"), slowCheck("components"))
+	elapsed := time.Since(start)
+
+	assert.EqualValues(t, 2, running.Load())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "kustomization")
+	assert.ErrorContains(t, err, "components")
+	// the checks share the deadline, so the total wait is bounded by a single timeout
+	assert.GreaterOrEqual(t, elapsed, timeout)
+	assert.Less(t, elapsed, 2*timeout)
+}
+
+func TestRunHealthChecksHealthy(t *testing.T) {
+	healthy := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("deployment not ready") }
+
+	assert.NoError(t, runHealthChecks(context.Background(), time.Second, healthy, healthy))
+	assert.EqualError(t, runHealthChecks(context.Background(), time.Second, healthy, failing), "deployment not ready")
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2beta1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// helmChartType is the OCM resource type of packaged Helm charts.
+	helmChartType = "helmChart"
+	// helmReleasesDir is the directory below the target path holding the Helm charts and their releases.
+	// It is separate from the flux namespace directory, whose kustomization only lists the flux manifests.
+	helmReleasesDir = "helm-releases"
+	// helmReleasesFileName is the name of the file holding the HelmReleases.
+	helmReleasesFileName = "helm-releases.yaml"
+)
+
+// reconcileHelmCharts commits the given Helm charts to the management repository together with
+// a HelmRelease for each chart, which installs the chart from the flux GitRepository.
+func (f *fluxInstall) reconcileHelmCharts(ctx context.Context, charts map[string][]byte) error {
+	if len(charts) == 0 {
+		return nil
+	}
+
+	releases, err := f.generateHelmReleases(charts)
+	if err != nil {
+		return err
+	}
+
+	releasesPath := path.Join(f.targetPath, helmReleasesDir, helmReleasesFileName)
+	if f.dryRun {
+		printDryRunPreview(f.printer, releasesPath, releases)
+		return nil
+	}
+
+	if f.clusterOnly {
+		f.fluxLogger().Warningf("skipping %d helm charts, they require the management repository", len(charts))
+		return nil
+	}
+
+	files := map[string]io.Reader{
+		releasesPath: bytes.NewReader(releases),
+	}
+	for name, chart := range charts {
+		files[f.helmChartPath(name)] = bytes.NewReader(chart)
+	}
+
+	return f.commitAndPush(ctx, fmt.Sprintf("Add Flux %s helm releases", f.version), files)
+}
+
+// generateHelmReleases returns a HelmRelease for each chart, sorted by chart name.
+func (f *fluxInstall) generateHelmReleases(charts map[string][]byte) ([]byte, error) {
+	names := make([]string, 0, len(charts))
+	for name := range charts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		release := helmv2.HelmRelease{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: helmv2.GroupVersion.String(),
+				Kind:       helmv2.HelmReleaseKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: f.namespace,
+			},
+			Spec: helmv2.HelmReleaseSpec{
+				Interval: metav1.Duration{Duration: f.componentInterval(name)},
+				Chart: helmv2.HelmChartTemplate{
+					Spec: helmv2.HelmChartTemplateSpec{
+						Chart: "./" + f.helmChartPath(name),
+						SourceRef: helmv2.CrossNamespaceObjectReference{
+							Kind:      sourcev1.GitRepositoryKind,
+							Name:      f.namespace,
+							Namespace: f.namespace,
+						},
+					},
+				},
+			},
+		}
+
+		data, err := yaml.Marshal(release)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal HelmRelease %s: %w", name, err)
+		}
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// helmChartPath returns the path of the packaged chart in the management repository.
+func (f *fluxInstall) helmChartPath(name string) string {
+	return path.Join(f.targetPath, helmReleasesDir, "charts", name+".tgz")
+}
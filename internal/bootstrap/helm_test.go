@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	helmv2 "github.com/fluxcd/helm-controller/api/v2beta1"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/open-component-model/ocm-controller/pkg/fakes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+// testHelmChart stands in for a packaged chart, it is committed as is.
+var testHelmChart = []byte("podinfo-6.5.0.tgz")
+
+func TestGetResourcesHelmChart(t *testing.T) {
+	componentName := "ocm.software/mpas/podinfo"
+	repo := newKustomizeTestRepository(componentName)
+	cva := repo.cv[0].cva["v1.0.0"]
+	cva.Resources = append(cva.Resources, &fakes.Resource{
+		Name:    "podinfo",
+		Version: "v6.5.0",
+		Data:    testHelmChart,
+		Kind:    "localBlob",
+		Type:    helmChartType,
+	})
+
+	cv, err := getComponentVersion(repo, componentName, "v1.0.0")
+	require.NoError(t, err)
+	res, err := getResources(cv, componentName, 0, nil)
+	require.NoError(t, err)
+	defer res.Close()
+
+	assert.Equal(t, map[string][]byte{"podinfo": testHelmChart}, res.helmCharts)
+	assert.Equal(t, []string{"git-controller"}, res.componentList)
+}
+
+func TestGenerateHelmReleases(t *testing.T) {
+	f := &fluxInstall{fluxOptions: &fluxOptions{
+		targetPath: "./clusters/management",
+		namespace:  "flux-system",
+	}}
+
+	data, err := f.generateHelmReleases(map[string][]byte{"podinfo": testHelmChart})
+	require.NoError(t, err)
+
+	release := &helmv2.HelmRelease{}
+	require.NoError(t, yaml.Unmarshal(data, release))
+	assert.Equal(t, "HelmRelease", release.Kind)
+	assert.Equal(t, "podinfo", release.Name)
+	assert.Equal(t, "flux-system", release.Namespace)
+	assert.Equal(t, "./clusters/management/helm-releases/charts/podinfo.tgz", release.Spec.Chart.Spec.Chart)
+	assert.Equal(t, helmv2.CrossNamespaceObjectReference{
+		Kind:      "GitRepository",
+		Name:      "flux-system",
+		Namespace: "flux-system",
+	}, release.Spec.Chart.Spec.SourceRef)
+}
+
+func TestReconcileHelmChartsDryRun(t *testing.T) {
+	out := &bytes.Buffer{}
+	p, err := printer.Newprinter(out)
+	require.NoError(t, err)
+
+	f := &fluxInstall{fluxOptions: &fluxOptions{
+		targetPath: ".",
+		namespace:  "flux-system",
+		dryRun:     true,
+		printer:    p,
+	}}
+
+	require.NoError(t, f.reconcileHelmCharts(context.Background(), nil))
+	assert.Empty(t, out.String())
+
+	require.NoError(t, f.reconcileHelmCharts(context.Background(), map[string][]byte{"podinfo": testHelmChart}))
+	assert.Contains(t, out.String(), "--- [dry-run] helm-releases/helm-releases.yaml ---")
+	assert.Contains(t, out.String(), "chart: ./helm-releases/charts/podinfo.tgz")
+}
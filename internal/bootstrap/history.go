@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// compactHistoryMessage is the message of the commit squashing the compacted commits.
+const compactHistoryMessage = "Compact bootstrap history"
+
+// CompactHistory squashes the commits of the management repository. The branch is soft-reset to
+// HEAD~N, where N is the number of commits exceeding keepLast, and the changes of the N commits
+// are committed as a single commit. The rewritten history is force-pushed.
+func (b *Bootstrap) CompactHistory(ctx context.Context, keepLast int) error {
+	if keepLast < 1 {
+		return fmt.Errorf("at least one commit must be kept, got %d", keepLast)
+	}
+
+	if b.repository == nil {
+		return fmt.Errorf("management repository is not set")
+	}
+
+	dir, err := mkdirTempDir("compact-history")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	repo, auth, err := b.cloneManagementRepository(ctx, dir)
+	if err != nil {
+		return err
+	}
+
+	compacted, err := compactHistory(repo, keepLast)
+	if err != nil {
+		return err
+	}
+	if !compacted {
+		return nil
+	}
+
+	branch := plumbing.NewBranchReferenceName(b.defaultBranch)
+	if err := repo.PushContext(ctx, &gogit.PushOptions{
+		Auth:     auth,
+		RefSpecs: []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", branch, branch))},
+		Force:    true,
+	}); err != nil {
+		return fmt.Errorf("failed to push compacted history: %w", err)
+	}
+
+	return nil
+}
+
+// cloneManagementRepository clones the default branch of the management repository into dir.
+// It returns the auth method to push the clone with.
+func (b *Bootstrap) cloneManagementRepository(ctx context.Context, dir string) (*gogit.Repository, transport.AuthMethod, error) {
+	url := b.url
+	if url == "" {
+		var err error
+		url, err = b.getCloneURL(b.repository, gitprovider.TransportTypeHTTPS)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	token, err := b.gitToken(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var auth transport.AuthMethod
+	if token != "" {
+		auth = &http.BasicAuth{Username: b.owner, Password: token}
+	}
+	repo, err := gogit.PlainCloneContext(ctx, dir, false, &gogit.CloneOptions{
+		URL:           url,
+		Auth:          auth,
+		ReferenceName: plumbing.NewBranchReferenceName(b.defaultBranch),
+		SingleBranch:  true,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to clone management repository: %w", err)
+	}
+
+	return repo, auth, nil
+}
+
+// compactHistory squashes all but the first keepLast commits of the first-parent history of HEAD
+// into a single commit. It returns false if there are not enough commits to compact.
+func compactHistory(repo *gogit.Repository, keepLast int) (bool, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return false, fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+
+	var history []*object.Commit
+	for {
+		history = append(history, commit)
+		if commit.NumParents() == 0 {
+			break
+		}
+		if commit, err = commit.Parent(0); err != nil {
+			return false, fmt.Errorf("failed to get parent of commit %s: %w", history[len(history)-1].Hash, err)
+		}
+	}
+
+	// squashing a single commit would only reword it
+	n := len(history) - keepLast
+	if n < 2 {
+		return false, nil
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return false, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	if err := w.Reset(&gogit.ResetOptions{Commit: history[n].Hash, Mode: gogit.SoftReset}); err != nil {
+		return false, fmt.Errorf("failed to reset to %s: %w", history[n].Hash, err)
+	}
+
+	if _, err := w.Commit(compactHistoryMessage, &gogit.CommitOptions{
+		Author: &object.Signature{Name: managedByValue, When: time.Now()},
+	}); err != nil {
+		return false, fmt.Errorf("failed to commit compacted history: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHistoryFixture creates a repository with the given number of commits, each adding a file.
+func newHistoryFixture(t *testing.T, commits int) *gogit.Repository {
+	dir := t.TempDir()
+	repo, err := gogit.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+
+	for i := 0; i < commits; i++ {
+		name := fmt.Sprintf("file-%d.yaml", i)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0o600))
+		_, err := w.Add(name)
+		require.NoError(t, err)
+		_, err = w.Commit(fmt.Sprintf("Add %s", name), &gogit.CommitOptions{
+			Author: &object.Signature{Name: "test", When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+
+	return repo
+}
+
+func historyOf(t *testing.T, repo *gogit.Repository) []*object.Commit {
+	head, err := repo.Head()
+	require.NoError(t, err)
+	iter, err := repo.Log(&gogit.LogOptions{From: head.Hash()})
+	require.NoError(t, err)
+
+	var history []*object.Commit
+	require.NoError(t, iter.ForEach(func(c *object.Commit) error {
+		history = append(history, c)
+		return nil
+	}))
+	return history
+}
+
+func TestCompactHistory(t *testing.T) {
+	repo := newHistoryFixture(t, 10)
+
+	compacted, err := compactHistory(repo, 3)
+	require.NoError(t, err)
+	assert.True(t, compacted)
+
+	history := historyOf(t, repo)
+	require.Len(t, history, 4)
+	assert.Equal(t, compactHistoryMessage, history[0].Message)
+	assert.Equal(t, "Add file-2.yaml", history[1].Message)
+
+	// the compacted commit contains the changes of all squashed commits
+	tree, err := history[0].Tree()
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		_, err := tree.File(fmt.Sprintf("file-%d.yaml", i))
+		assert.NoError(t, err)
+	}
+
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+	status, err := w.Status()
+	require.NoError(t, err)
+	assert.True(t, status.IsClean())
+
+	// nothing left to compact
+	compacted, err = compactHistory(repo, 3)
+	require.NoError(t, err)
+	assert.False(t, compacted)
+}
+
+func TestCompactHistoryPush(t *testing.T) {
+	source := newHistoryFixture(t, 10)
+
+	remoteDir := t.TempDir()
+	remote, err := gogit.PlainInit(remoteDir, true)
+	require.NoError(t, err)
+	_, err = source.CreateRemote(&config.RemoteConfig{Name: "mpas", URLs: []string{remoteDir}})
+	require.NoError(t, err)
+	require.NoError(t, source.Push(&gogit.PushOptions{RemoteName: "mpas"}))
+
+	b := &Bootstrap{
+		repository: &mockGitRepository{},
+		url:        remoteDir,
+		options:    options{defaultBranch: "master"},
+	}
+	require.ErrorContains(t, b.CompactHistory(context.Background(), 0), "at least one commit")
+	require.NoError(t, b.CompactHistory(context.Background(), 3))
+
+	history := historyOf(t, remote)
+	require.Len(t, history, 4)
+	assert.Equal(t, compactHistoryMessage, history[0].Message)
+}
+
+func TestGitToken(t *testing.T) {
+	b := &Bootstrap{options: options{token: "pat"}}
+	token, err := b.gitToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "pat", token)
+
+	// the token function is called for every git operation to refresh short-lived tokens
+	var calls int
+	WithTokenFunc(func(ctx context.Context) (string, error) {
+		calls++
+		return fmt.Sprintf("app-token-%d", calls), nil
+	})(&b.options)
+	for i := 1; i <= 2; i++ {
+		token, err = b.gitToken(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("app-token-%d", i), token)
+	}
+}
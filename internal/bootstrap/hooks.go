@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Hook runs arbitrary Kubernetes operations with the kubeclient of the bootstrap.
+type Hook func(ctx context.Context, c client.Client) error
+
+// WithPreInstallHook registers a hook that is run before any component is installed, e.g. to
+// verify that a storage class exists or that all nodes are ready. Hooks run in registration order.
+// If any hook fails, the bootstrap is aborted before any manifest is pushed or applied.
+func WithPreInstallHook(fn func(ctx context.Context, c client.Client) error) Option {
+	return func(o *options) {
+		o.preInstallHooks = append(o.preInstallHooks, fn)
+	}
+}
+
+// WithPostInstallHook registers a hook that is run after all components are installed and healthy.
+// Hooks run in registration order. A failing hook does not roll back the installation, but the
+// bootstrap returns its error.
+func WithPostInstallHook(fn func(ctx context.Context, c client.Client) error) Option {
+	return func(o *options) {
+		o.postInstallHooks = append(o.postInstallHooks, fn)
+	}
+}
+
+// runPreInstallHooks runs all pre-install hooks and returns their joined errors, so that
+// all failed checks are reported at once.
+func (b *Bootstrap) runPreInstallHooks(ctx context.Context) error {
+	if len(b.preInstallHooks) == 0 {
+		return nil
+	}
+
+	var errs []error
+	if err := b.inSpinner("Running pre-install hooks", func() error {
+		for i, hook := range b.preInstallHooks {
+			if err := hook(ctx, b.kubeclient); err != nil {
+				errs = append(errs, fmt.Errorf("pre-install hook %d: %w", i, err))
+			}
+		}
+		return errors.Join(errs...)
+	}); err != nil {
+		return fmt.Errorf("pre-install hooks failed: %w", err)
+	}
+
+	return nil
+}
+
+// runPostInstallHooks runs all post-install hooks and returns their joined errors.
+// All hooks are run even if one of them fails.
+func (b *Bootstrap) runPostInstallHooks(ctx context.Context) error {
+	if len(b.postInstallHooks) == 0 {
+		return nil
+	}
+
+	var errs []error
+	if err := b.inSpinner("Running post-install hooks", func() error {
+		for i, hook := range b.postInstallHooks {
+			if err := hook(ctx, b.kubeclient); err != nil {
+				errs = append(errs, fmt.Errorf("post-install hook %d: %w", i, err))
+			}
+		}
+		return errors.Join(errs...)
+	}); err != nil {
+		return fmt.Errorf("components were installed, but post-install hooks failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRunPostInstallHooks(t *testing.T) {
+	p, err := printer.Newprinter(io.Discard)
+	require.NoError(t, err)
+
+	var order []string
+	createConfigMap := func(ctx context.Context, c client.Client) error {
+		order = append(order, "configmap")
+		return c.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "ci-config", Namespace: "default"},
+			Data:       map[string]string{"installed": "true"},
+		})
+	}
+	failing := func(context.Context, client.Client) error {
+		order = append(order, "failing")
+		return errors.New("cluster role binding rejected")
+	}
+	last := func(context.Context, client.Client) error {
+		order = append(order, "last")
+		return nil
+	}
+
+	opts := options{kubeclient: fake.NewClientBuilder().Build(), printer: p}
+	for _, opt := range []Option{WithPostInstallHook(createConfigMap), WithPostInstallHook(failing), WithPostInstallHook(last)} {
+		opt(&opts)
+	}
+	b := &Bootstrap{options: opts}
+
+	err = b.runPostInstallHooks(context.Background())
+	assert.EqualError(t, err, "components were installed, but post-install hooks failed: post-install hook 1: cluster role binding rejected")
+	assert.Equal(t, []string{"configmap", "failing", "last"}, order)
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, b.kubeclient.Get(context.Background(), client.ObjectKey{Name: "ci-config", Namespace: "default"}, cm))
+	assert.Equal(t, "true", cm.Data["installed"])
+
+	assert.NoError(t, (&Bootstrap{options: options{printer: p}}).runPostInstallHooks(context.Background()))
+}
+
+func TestPreInstallHooksAbortBootstrap(t *testing.T) {
+	p, err := printer.Newprinter(io.Discard)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dockerConfig := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(dockerConfig, []byte(`{"auths": {}}`), 0o600))
+
+	var ran []string
+	missingStorageClass := func(context.Context, client.Client) error {
+		ran = append(ran, "storage-class")
+		return errors.New("storage class standard not found")
+	}
+	nodesReady := func(context.Context, client.Client) error {
+		ran = append(ran, "nodes-ready")
+		return nil
+	}
+
+	// the provider client panics if the management repository is reconciled
+	b := &Bootstrap{
+		providerClient: &mockProviderClient{},
+		options: options{
+			registry:            server.URL + "/mpas",
+			dockerConfigPath:    dockerConfig,
+			kubeclient:          fake.NewClientBuilder().Build(),
+			printer:             p,
+			skipPreflightChecks: true,
+		},
+	}
+	WithPreInstallHook(missingStorageClass)(&b.options)
+	WithPreInstallHook(nodesReady)(&b.options)
+
+	err = b.Run(context.Background())
+	assert.EqualError(t, err, "pre-install hooks failed: pre-install hook 0: storage class standard not found")
+	assert.Equal(t, []string{"storage-class", "nodes-ready"}, ran)
+	assert.Nil(t, b.repository)
+	assert.Nil(t, b.state)
+}
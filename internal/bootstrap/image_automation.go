@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	imagev1 "github.com/fluxcd/image-automation-controller/api/v1beta1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// imageAutomationDir is the directory below the target path holding the ImageUpdateAutomation.
+	imageAutomationDir = "image-automation"
+	// imageAutomationFileName is the name of the file holding the ImageUpdateAutomation.
+	imageAutomationFileName = "image-update-automation.yaml"
+	// imageUpdateAutomationCRD is the name of the CRD of the ImageUpdateAutomation.
+	imageUpdateAutomationCRD = "imageupdateautomations.image.toolkit.fluxcd.io"
+)
+
+// imageAutomationComponents are the flux controllers updating the image tags in the management repository.
+var imageAutomationComponents = []string{"image-reflector-controller", "image-automation-controller"}
+
+// WithImageAutomation installs the flux image automation controllers and commits an ImageUpdateAutomation
+// updating the image tags marked with setters in the target path of the management repository.
+// The bootstrap component must ship the controllers, otherwise the flux installation fails.
+func WithImageAutomation(enabled bool) Option {
+	return func(o *options) {
+		o.imageAutomation = enabled
+	}
+}
+
+// withImageAutomation requires the image automation controllers in the flux installation.
+func withImageAutomation(enabled bool) fluxOption {
+	return func(o *fluxOptions) {
+		o.imageAutomation = enabled
+	}
+}
+
+// checkImageAutomationComponents returns an error if image automation is enabled but the bootstrap
+// component does not ship the images of the image automation controllers.
+func (f *fluxInstall) checkImageAutomationComponents(components []string) error {
+	if !f.imageAutomation {
+		return nil
+	}
+
+	var missing []string
+	for _, c := range imageAutomationComponents {
+		if !slices.Contains(components, c) {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("image automation requires the bootstrap component to ship the images of %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// checkImageAutomationManifests returns an error if image automation is enabled but the flux
+// manifests lack the Deployments of the image automation controllers or the ImageUpdateAutomation
+// CRD, e.g. because the flux resource of the bootstrap component was built without them.
+func (f *fluxInstall) checkImageAutomationManifests(manifests []byte) error {
+	if !f.imageAutomation {
+		return nil
+	}
+
+	found := map[string]bool{}
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifests), 4096)
+	for {
+		obj := &metav1.PartialObjectMetadata{}
+		if err := decoder.Decode(obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("failed to decode flux manifests: %w", err)
+		}
+		found[obj.Kind+"/"+obj.Name] = true
+	}
+
+	var missing []string
+	for _, c := range imageAutomationComponents {
+		if !found["Deployment/"+c] {
+			missing = append(missing, "Deployment "+c)
+		}
+	}
+	if !found["CustomResourceDefinition/"+imageUpdateAutomationCRD] {
+		missing = append(missing, "CustomResourceDefinition "+imageUpdateAutomationCRD)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("image automation requires the flux resource of the bootstrap component to contain %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// reconcileImageAutomation commits the ImageUpdateAutomation to the management repository.
+func (f *fluxInstall) reconcileImageAutomation(ctx context.Context) error {
+	if !f.imageAutomation {
+		return nil
+	}
+
+	automation, err := f.generateImageUpdateAutomation()
+	if err != nil {
+		return err
+	}
+
+	return f.commitAndPush(ctx, fmt.Sprintf("Add Flux %s image update automation", f.version), map[string]io.Reader{
+		path.Join(f.targetPath, imageAutomationDir, imageAutomationFileName): bytes.NewReader(automation),
+	})
+}
+
+// generateImageUpdateAutomation returns the ImageUpdateAutomation manifest. It pushes the updates
+// through the GitRepository flux syncs the management repository from.
+func (f *fluxInstall) generateImageUpdateAutomation() ([]byte, error) {
+	author := f.commitAuthor()
+	automation := imagev1.ImageUpdateAutomation{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: imagev1.GroupVersion.String(),
+			Kind:       "ImageUpdateAutomation",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      f.namespace,
+			Namespace: f.namespace,
+		},
+		Spec: imagev1.ImageUpdateAutomationSpec{
+			SourceRef: imagev1.CrossNamespaceSourceReference{
+				Kind: sourcev1.GitRepositoryKind,
+				Name: f.namespace,
+			},
+			GitSpec: &imagev1.GitSpec{
+				Commit: imagev1.CommitSpec{
+					Author: imagev1.CommitUser{
+						Name:  author.Name,
+						Email: author.Email,
+					},
+					MessageTemplate: "Update images",
+				},
+				Push: &imagev1.PushSpec{
+					Branch: f.branch,
+				},
+			},
+			Interval: metav1.Duration{Duration: f.interval},
+			Update: &imagev1.UpdateStrategy{
+				Strategy: imagev1.UpdateStrategySetters,
+				Path:     f.targetPath,
+			},
+		},
+	}
+
+	data, err := yaml.Marshal(automation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ImageUpdateAutomation: %w", err)
+	}
+
+	return data, nil
+}
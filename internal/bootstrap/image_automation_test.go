@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	imagev1 "github.com/fluxcd/image-automation-controller/api/v1beta1"
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/gogit"
+	"github.com/fluxcd/pkg/git/repository"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	cfd "github.com/open-component-model/ocm-controller/pkg/configdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// imageAutomationManifests is a flux resource containing the image automation controllers.
+var imageAutomationManifests = []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: imageupdateautomations.image.toolkit.fluxcd.io
+spec:
+  group: image.toolkit.fluxcd.io
+  names:
+    kind: ImageUpdateAutomation
+    plural: imageupdateautomations
+  scope: Namespaced
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: image-reflector-controller
+  namespace: flux-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: ghcr.io/fluxcd/image-reflector-controller:v0.30.0
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: image-automation-controller
+  namespace: flux-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: ghcr.io/fluxcd/image-automation-controller:v0.36.0
+`)
+
+func TestCheckImageAutomationComponents(t *testing.T) {
+	f := &fluxInstall{fluxOptions: &fluxOptions{}}
+	assert.NoError(t, f.checkImageAutomationComponents([]string{"source-controller"}))
+
+	withImageAutomation(true)(f.fluxOptions)
+	assert.NoError(t, f.checkImageAutomationComponents([]string{
+		"source-controller",
+		"image-reflector-controller",
+		"image-automation-controller",
+	}))
+	assert.ErrorContains(t, f.checkImageAutomationComponents([]string{"source-controller", "image-reflector-controller"}),
+		"ship the images of image-automation-controller")
+}
+
+func TestCheckImageAutomationManifests(t *testing.T) {
+	f := &fluxInstall{fluxOptions: &fluxOptions{namespace: "flux-system", dir: t.TempDir()}}
+	withImageAutomation(true)(f.fluxOptions)
+
+	kfile, kus, err := f.generateKustomization(bytes.NewReader(imageAutomationManifests))
+	require.NoError(t, err)
+	res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+	require.NoError(t, err)
+	require.NoError(t, f.checkImageAutomationManifests(res))
+
+	// the generated gotk-components contain the Deployments of the image automation controllers
+	var deployments []string
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(res), 4096)
+	for {
+		obj := &appsv1.Deployment{}
+		if err := decoder.Decode(obj); err != nil {
+			require.ErrorIs(t, err, io.EOF)
+			break
+		}
+		if obj.Kind == "Deployment" {
+			deployments = append(deployments, obj.Name)
+		}
+	}
+	assert.ElementsMatch(t, imageAutomationComponents, deployments)
+
+	// the installation fails fast if the flux resource lacks the controllers
+	err = f.checkImageAutomationManifests(kustomizedDeployment)
+	assert.ErrorContains(t, err, "Deployment image-reflector-controller, Deployment image-automation-controller, "+
+		"CustomResourceDefinition imageupdateautomations.image.toolkit.fluxcd.io")
+
+	withImageAutomation(false)(f.fluxOptions)
+	assert.NoError(t, f.checkImageAutomationManifests(kustomizedDeployment))
+}
+
+func TestReconcileImageAutomation(t *testing.T) {
+	bare := newBareRepository(t, 1)
+
+	gitClient, err := gogit.NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP}, gogit.WithDiskStorage())
+	require.NoError(t, err)
+	_, err = gitClient.Clone(context.Background(), bare, repository.CloneOptions{
+		CheckoutStrategy: repository.CheckoutStrategy{Branch: "main"},
+	})
+	require.NoError(t, err)
+
+	opts := &fluxOptions{
+		gitClient:  gitClient,
+		url:        bare,
+		branch:     "main",
+		targetPath: "clusters",
+		namespace:  "flux-system",
+		interval:   time.Minute,
+	}
+	withImageAutomation(true)(opts)
+	f := &fluxInstall{version: "v2.0.0", fluxOptions: opts}
+	require.NoError(t, f.reconcileImageAutomation(context.Background()))
+
+	data, err := os.ReadFile(filepath.Join(gitClient.Path(), "clusters", "image-automation", "image-update-automation.yaml"))
+	require.NoError(t, err)
+
+	automation := &imagev1.ImageUpdateAutomation{}
+	require.NoError(t, yaml.UnmarshalStrict(data, automation))
+	assert.Equal(t, imagev1.GroupVersion.String(), automation.APIVersion)
+	assert.Equal(t, "ImageUpdateAutomation", automation.Kind)
+	assert.Equal(t, "flux-system", automation.Namespace)
+	// the GitRepository of the same name syncs the management repository
+	assert.Equal(t, imagev1.CrossNamespaceSourceReference{
+		Kind: sourcev1.GitRepositoryKind,
+		Name: "flux-system",
+	}, automation.Spec.SourceRef)
+	require.NotNil(t, automation.Spec.GitSpec)
+	assert.Equal(t, defaultCommitAuthorName, automation.Spec.GitSpec.Commit.Author.Name)
+	require.NotNil(t, automation.Spec.GitSpec.Push)
+	assert.Equal(t, "main", automation.Spec.GitSpec.Push.Branch)
+	assert.Equal(t, time.Minute, automation.Spec.Interval.Duration)
+	require.NotNil(t, automation.Spec.Update)
+	assert.Equal(t, imagev1.UpdateStrategySetters, automation.Spec.Update.Strategy)
+	assert.Equal(t, "clusters", automation.Spec.Update.Path)
+}
+
+func TestReconcileImageAutomationDisabled(t *testing.T) {
+	f := &fluxInstall{fluxOptions: &fluxOptions{}}
+	assert.NoError(t, f.reconcileImageAutomation(context.Background()))
+}
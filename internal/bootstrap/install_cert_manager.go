@@ -32,6 +32,7 @@ type certManagerOptions struct {
 	provider              string
 	timeout               time.Duration
 	commitMessageAppendix string
+	publicKey             []byte
 }
 
 // certManagerInstall is used to install cert-manager
@@ -57,6 +58,7 @@ func newCertManagerInstall(name, version string, repository ocm.Repository, opts
 			repository:    repository,
 			dir:           opts.dir,
 			host:          env.DefaultCertManagerHost,
+			publicKey:     opts.publicKey,
 		}),
 	}
 
@@ -64,7 +66,7 @@ func newCertManagerInstall(name, version string, repository ocm.Repository, opts
 }
 
 func (c *certManagerInstall) Install(ctx context.Context, component string) (string, error) {
-	res, err := c.kustomizer.GenerateKustomizedResourceData(component)
+	res, err := c.kustomizer.GenerateKustomizedResourceData(ctx, component)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate component yaml: %w", err)
 	}
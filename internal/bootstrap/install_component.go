@@ -22,12 +22,26 @@ type componentOptions struct {
 	branch        string
 	targetPath    string
 	namespace     string
-	dir           string
-	provider      string
+	// kustomizeNamespace overrides the namespace of all namespaced resources of the component if set
+	kustomizeNamespace string
+	dir                string
+	provider           string
 	// we bookkeep the installed components so we can cleanup unnecessary namespaces
 	installedNS           map[string][]string
 	commitMessageAppendix string
 	timeout               time.Duration
+	// state is committed alongside the component manifests if set
+	state *bootstrapState
+	// publicKey is used to verify the component's signature if set
+	publicKey []byte
+	// nodeArchitecture is the architecture the deployment images are patched for
+	nodeArchitecture string
+	// imageMirrors maps image prefixes to the mirror to pull them from
+	imageMirrors map[string]string
+	// imagePullSecrets are added to all Deployments of the component
+	imagePullSecrets []string
+	// metrics records the duration of the reconciliation if set
+	metrics *Metrics
 }
 
 // componentInstall is used to install a component
@@ -40,7 +54,11 @@ type componentInstall struct {
 }
 
 // newComponentInstall returns a new component install
-func newComponentInstall(name, version string, repository ocm.Repository, opts *componentOptions) (*componentInstall, error) {
+func newComponentInstall(name, version string, repository ocm.Repository, opts *componentOptions, copts ...componentOption) (*componentInstall, error) {
+	for _, o := range copts {
+		o(opts)
+	}
+
 	c := &componentInstall{
 		componentName:    name,
 		version:          version,
@@ -51,6 +69,9 @@ func newComponentInstall(name, version string, repository ocm.Repository, opts *
 			repository:    repository,
 			dir:           opts.dir,
 			host:          env.DefaultOCMHost,
+			namespace:     opts.kustomizeNamespace,
+			publicKey:     opts.publicKey,
+			imageMirrors:  opts.imageMirrors,
 		}),
 	}
 
@@ -58,7 +79,7 @@ func newComponentInstall(name, version string, repository ocm.Repository, opts *
 }
 
 func (c *componentInstall) install(ctx context.Context, component string) (string, error) {
-	res, err := c.kustomizer.GenerateKustomizedResourceData(component)
+	res, err := c.kustomizer.GenerateKustomizedResourceData(ctx, component)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate component yaml: %w", err)
 	}
@@ -72,6 +93,18 @@ func (c *componentInstall) install(ctx context.Context, component string) (strin
 }
 
 func (c *componentInstall) reconcileComponents(ctx context.Context, content []byte) (string, error) {
+	defer c.metrics.observeStep(c.componentName, stepReconcile, time.Now())
+
+	content, err := patchImageArchitecture(content, c.nodeArchitecture)
+	if err != nil {
+		return "", fmt.Errorf("failed to patch images for architecture %s: %w", c.nodeArchitecture, err)
+	}
+
+	content, err = patchImagePullSecrets(content, c.imagePullSecrets)
+	if err != nil {
+		return "", fmt.Errorf("failed to patch image pull secrets: %w", err)
+	}
+
 	if _, ok := c.installedNS[c.namespace]; ok {
 		// remove ns from content
 		objects, err := kubeutils.YamlToUnstructructured(content)
@@ -91,17 +124,47 @@ func (c *componentInstall) reconcileComponents(ctx context.Context, content []by
 	if c.commitMessageAppendix != "" {
 		commitMsg = commitMsg + "\n\n" + c.commitMessageAppendix
 	}
-	commit, err := c.gitRepository.Commits().Create(ctx,
-		c.branch,
-		commitMsg,
-		[]gitprovider.CommitFile{
-			{
-				Path:    &path,
-				Content: &data,
-			},
+	files := []gitprovider.CommitFile{
+		{
+			Path:    &path,
+			Content: &data,
+		},
+	}
+
+	var state *bootstrapState
+	if c.state != nil {
+		state = c.state.with(newComponentState(c.componentName, c.version, content))
+		stateContent, err := state.marshal()
+		if err != nil {
+			return "", err
+		}
+		statePath := filepath.Join(c.targetPath, bootstrapStateFileName)
+		stateData := SetProviderDataFormat(c.provider, stateContent)
+		files = append(files, gitprovider.CommitFile{
+			Path:    &statePath,
+			Content: &stateData,
 		})
-	if err != nil {
-		return "", fmt.Errorf("failed to create component: %w", err)
+	}
+
+	var commit gitprovider.Commit
+	// gitea does not support committing multiple files at once, see install_certificate_manifests.go
+	switch c.provider {
+	case env.ProviderGitea:
+		for _, file := range files {
+			commit, err = c.gitRepository.Commits().Create(ctx, c.branch, commitMsg, []gitprovider.CommitFile{file})
+			if err != nil {
+				return "", fmt.Errorf("failed to create component: %w", err)
+			}
+		}
+	default:
+		commit, err = c.gitRepository.Commits().Create(ctx, c.branch, commitMsg, files)
+		if err != nil {
+			return "", fmt.Errorf("failed to create component: %w", err)
+		}
+	}
+
+	if state != nil {
+		c.state.Phases = state.Phases
 	}
 
 	return commit.Get().Sha, nil
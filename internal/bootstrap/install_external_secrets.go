@@ -32,6 +32,7 @@ type externalSecretOptions struct {
 	provider              string
 	timeout               time.Duration
 	commitMessageAppendix string
+	publicKey             []byte
 }
 
 // externalSecretInstall is used to install external-secrets
@@ -57,6 +58,7 @@ func newExternalSecretInstall(name, version string, repository ocm.Repository, o
 			repository:    repository,
 			dir:           opts.dir,
 			host:          env.DefaultExternalSecretsHost,
+			publicKey:     opts.publicKey,
 		}),
 	}
 
@@ -64,7 +66,7 @@ func newExternalSecretInstall(name, version string, repository ocm.Repository, o
 }
 
 func (c *externalSecretInstall) Install(ctx context.Context, component string) (string, error) {
-	res, err := c.kustomizer.GenerateKustomizedResourceData(component)
+	res, err := c.kustomizer.GenerateKustomizedResourceData(ctx, component)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate component yaml: %w", err)
 	}
@@ -5,30 +5,34 @@
 package bootstrap
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
-	"sync"
 	"time"
 
 	flux "github.com/fluxcd/flux2/v2/pkg/bootstrap"
 	"github.com/fluxcd/flux2/v2/pkg/log"
 	"github.com/fluxcd/flux2/v2/pkg/manifestgen/install"
-	"github.com/fluxcd/flux2/v2/pkg/manifestgen/sourcesecret"
 	syncOpts "github.com/fluxcd/flux2/v2/pkg/manifestgen/sync"
 	"github.com/fluxcd/pkg/git"
 	"github.com/fluxcd/pkg/git/gogit"
 	"github.com/fluxcd/pkg/git/repository"
 	rateoption "github.com/fluxcd/pkg/runtime/client"
+	sourcev1beta2 "github.com/fluxcd/source-controller/api/v1beta2"
 	"github.com/open-component-model/mpas/internal/env"
 	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/open-component-model/mpas/internal/printer"
 	cfd "github.com/open-component-model/ocm-controller/pkg/configdata"
 	"github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,6 +45,7 @@ type fluxOptions struct {
 	gitClient             repository.Client
 	kubeClient            client.Client
 	restClientGetter      genericclioptions.RESTClientGetter
+	applier               applyFunc
 	url                   string
 	testURL               string
 	transport             string
@@ -53,6 +58,202 @@ type fluxOptions struct {
 	interval              time.Duration
 	timeout               time.Duration
 	caFile                []byte
+	dryRun                bool
+	clusterOnly           bool
+	printer               *printer.Printer
+	registry              string
+	// componentIntervals overrides the interval for the given components
+	componentIntervals map[string]time.Duration
+	// ociSource adds an OCIRepository source for the bootstrap component
+	ociSource bool
+	// publicKey is used to verify the flux component's signature if set
+	publicKey []byte
+	// nodeArchitecture is the architecture the flux images are patched for
+	nodeArchitecture string
+	// maxResourceSize limits the size of the flux resources if positive
+	maxResourceSize int64
+	// imageMirrors maps image prefixes to the mirror to pull them from
+	imageMirrors map[string]string
+	// logger receives the flux bootstrap logs if set
+	logger *slog.Logger
+	// metrics records the duration of cloning and reconciling if set
+	metrics *Metrics
+	// pdbMinAvailable is the minAvailable of the PodDisruptionBudgets of the controllers, none are created if 0
+	pdbMinAvailable int
+	// signingKeyPath is the armored private key ring the component commits are signed with if set
+	signingKeyPath       string
+	signingKeyPassphrase string
+	// additionalCRDPaths are CRD manifests added to the flux components
+	additionalCRDPaths []string
+	// kustomizationPatches are added to the patches of the flux kustomization
+	kustomizationPatches []kustypes.Patch
+	// resourceFilter selects the resources of the flux component by extra identity or label
+	resourceFilter map[string]string
+	// sourceControllerPullPolicy is the imagePullPolicy of the source-controller if set
+	sourceControllerPullPolicy corev1.PullPolicy
+	// cloneDepth limits the history of the management repository clone if positive
+	cloneDepth int
+	// preApplyValidation validates the manifests with a server-side dry-run before they are committed
+	preApplyValidation bool
+	// oidcProvider and oidcIdentity bind the source-controller to a cloud workload identity if set
+	oidcProvider string
+	oidcIdentity string
+	// receivers are the notification Receivers committed alongside the sync manifests
+	receivers []FluxReceiver
+	// scanner scans the images for vulnerabilities above maxSeverity before committing if set
+	scanner     ScannerClient
+	maxSeverity string
+	// sandboxedBuild builds the flux kustomization in memory instead of on disk
+	sandboxedBuild bool
+	// vpaUpdateMode is the updateMode of the VerticalPodAutoscalers of the controllers, none are created if empty
+	vpaUpdateMode string
+	// serviceAccountAnnotations are added to the ServiceAccounts of the controllers, keyed by controller name
+	serviceAccountAnnotations map[string]map[string]string
+	// commitAuthorName and commitAuthorEmail are the author of the component commits
+	commitAuthorName  string
+	commitAuthorEmail string
+	// resourceLimits and resourceRequests are the resources of the controllers, keyed by controller name
+	resourceLimits   map[string]corev1.ResourceList
+	resourceRequests map[string]corev1.ResourceList
+	// annotationPropagation adds the extra identity of the flux resource as annotations to all objects
+	annotationPropagation bool
+	// sshURL is the URL the GitRepository syncs from if the transport is ssh
+	sshURL string
+	// sshKeyPath and sshPassphrase authenticate the GitRepository if the transport is ssh
+	sshKeyPath    string
+	sshPassphrase string
+	// knownHostsPath is the known_hosts file of the GitRepository, the host key is scanned if empty
+	knownHostsPath string
+	// alerts are the notification Alerts and Providers committed after the sync manifests
+	alerts []FluxAlert
+	// imageAutomation adds the image automation controllers and commits an ImageUpdateAutomation
+	imageAutomation bool
+}
+
+const (
+	// defaultCommitAuthorName and defaultCommitAuthorEmail are the author of the component
+	// commits if no author is set.
+	defaultCommitAuthorName  = "mpas-bootstrap"
+	defaultCommitAuthorEmail = "mpas@noreply"
+)
+
+// ociSourceName is the name of the OCIRepository source of the bootstrap component.
+const ociSourceName = "mpas-bootstrap"
+
+// fluxOption is a function that sets an option on the flux install
+type fluxOption func(*fluxOptions)
+
+// WithMaxResourceSize limits the size of the resources read from the flux component.
+// Installing fails if a resource is larger. It is not limited by default.
+func WithMaxResourceSize(bytes int64) Option {
+	return func(o *options) {
+		o.maxResourceSize = bytes
+	}
+}
+
+// withMaxResourceSize limits the size of the resources read from the flux component.
+func withMaxResourceSize(bytes int64) fluxOption {
+	return func(o *fluxOptions) {
+		o.maxResourceSize = bytes
+	}
+}
+
+// WithCommitAuthor sets the author of the commits of the component manifests.
+// It defaults to mpas-bootstrap <mpas@noreply>.
+func WithCommitAuthor(name, email string) Option {
+	return func(o *options) {
+		o.commitAuthorName = name
+		o.commitAuthorEmail = email
+	}
+}
+
+// withCommitAuthor sets the author of the commits of the flux component manifests.
+func withCommitAuthor(name, email string) fluxOption {
+	return func(o *fluxOptions) {
+		o.commitAuthorName = name
+		o.commitAuthorEmail = email
+	}
+}
+
+// WithSandboxedBuild builds the flux kustomization in an in-memory file system, so that the
+// kustomize build does not write to disk.
+func WithSandboxedBuild(sandboxed bool) Option {
+	return func(o *options) {
+		o.sandboxedBuild = sandboxed
+	}
+}
+
+// withSandboxedBuild builds the flux kustomization in memory.
+func withSandboxedBuild(sandboxed bool) fluxOption {
+	return func(o *fluxOptions) {
+		o.sandboxedBuild = sandboxed
+	}
+}
+
+// WithCloneDepth limits the history fetched when cloning the management repository.
+// The Git client only supports shallow clones of depth 1, so 0 (the full history) and 1 are valid.
+// A depth of 1 is safe, as the bootstrap only appends linear commits on top of the fetched HEAD
+// and never needs to rewrite or merge history.
+func WithCloneDepth(n int) Option {
+	return func(o *options) {
+		o.cloneDepth = n
+	}
+}
+
+// withCloneDepth limits the history of the management repository clone.
+func withCloneDepth(n int) fluxOption {
+	return func(o *fluxOptions) {
+		o.cloneDepth = n
+	}
+}
+
+// WithKustomizationPatches adds the given patches to the kustomization of the Flux components.
+// Both strategic merge and JSON6902 patches are supported.
+func WithKustomizationPatches(patches []kustypes.Patch) Option {
+	return func(o *options) {
+		o.kustomizationPatches = append(o.kustomizationPatches, patches...)
+	}
+}
+
+// withKustomizationPatches adds the given patches to the kustomization of the flux components.
+func withKustomizationPatches(patches []kustypes.Patch) fluxOption {
+	return func(o *fluxOptions) {
+		o.kustomizationPatches = append(o.kustomizationPatches, patches...)
+	}
+}
+
+// WithFluxResourceFilter selects the resources of the Flux component whose extra identity or labels
+// match all entries of labels. This disambiguates components bundling resources for different environments.
+func WithFluxResourceFilter(labels map[string]string) Option {
+	return func(o *options) {
+		o.fluxResourceFilter = labels
+	}
+}
+
+// withResourceFilter selects the resources of the flux component by extra identity or label.
+func withResourceFilter(labels map[string]string) fluxOption {
+	return func(o *fluxOptions) {
+		o.resourceFilter = labels
+	}
+}
+
+// withComponentInterval sets the sync interval to use for the given component.
+// Components without a dedicated interval fall back to the default interval.
+func withComponentInterval(component string, interval time.Duration) fluxOption {
+	return func(o *fluxOptions) {
+		if o.componentIntervals == nil {
+			o.componentIntervals = make(map[string]time.Duration)
+		}
+		o.componentIntervals[component] = interval
+	}
+}
+
+// withFluxOCISource sets whether an OCIRepository source pointing at the bootstrap component
+// in the OCM registry is generated alongside the Flux components.
+func withFluxOCISource(enabled bool) fluxOption {
+	return func(o *fluxOptions) {
+		o.ociSource = enabled
+	}
 }
 
 type fluxInstall struct {
@@ -60,13 +261,16 @@ type fluxInstall struct {
 	version          string
 	repository       ocm.Repository
 	components       []string
+	extraIdentity    map[string]string
 	fluxBootstrapper *flux.PlainGitBootstrapper
 	*fluxOptions
-	// mu is used to synchronize access to the kustomization file
-	mu sync.Mutex
 }
 
-func newFluxInstall(name, version, owner string, repository ocm.Repository, opts *fluxOptions) (*fluxInstall, error) {
+func newFluxInstall(name, version, owner string, repository ocm.Repository, opts *fluxOptions, fopts ...fluxOption) (*fluxInstall, error) {
+	for _, o := range fopts {
+		o(opts)
+	}
+
 	f := &fluxInstall{
 		componentName: name,
 		version:       version,
@@ -88,7 +292,7 @@ func newFluxInstall(name, version, owner string, repository ocm.Repository, opts
 	p, err := flux.NewPlainGitProvider(gitClient, f.kubeClient,
 		flux.WithBranch(f.branch),
 		flux.WithRepositoryURL(f.url),
-		flux.WithLogger(log.NopLogger{}),
+		flux.WithLogger(f.fluxLogger()),
 		flux.WithKubeconfig(f.restClientGetter, &rateoption.Options{QPS: env.DefaultKubeAPIQPS, Burst: env.DefaultKubeAPIBurst}),
 	)
 	if err != nil {
@@ -100,25 +304,66 @@ func newFluxInstall(name, version, owner string, repository ocm.Repository, opts
 	return f, nil
 }
 
-func (f *fluxInstall) Install(ctx context.Context, component string) error {
-	cv, err := getComponentVersion(f.repository, f.componentName, f.version)
-	if err != nil {
-		return fmt.Errorf("failed to get component version: %w", err)
-	}
+func (f *fluxInstall) Install(ctx context.Context, component string) (err error) {
+	ctx, span := startSpan(ctx, "fluxInstall.Install", trace.WithAttributes(
+		attribute.String("component", f.componentName),
+		attribute.String("version", f.version),
+	))
+	defer func() {
+		_ = endSpan(span, err)
+		span.End()
+	}()
+
+	var (
+		cv        ocm.ComponentVersionAccess
+		resources resources
+	)
+	if err := traced(ctx, "getResources", func(context.Context) error {
+		cv, err = getComponentVersion(f.repository, f.componentName, f.version)
+		if err != nil {
+			return fmt.Errorf("failed to get component version: %w", err)
+		}
 
-	resources, err := getResources(cv, component)
-	if err != nil {
-		return fmt.Errorf("failed to get resources: %w", err)
+		if err := verifyComponentVersion(cv, f.publicKey); err != nil {
+			return err
+		}
+
+		resources, err = getResources(cv, component, f.maxResourceSize, f.resourceFilter)
+		if err != nil {
+			return fmt.Errorf("failed to get resources: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return err
 	}
+	defer resources.Close()
 
+	if err := f.checkImageAutomationComponents(resources.componentList); err != nil {
+		return err
+	}
 	f.components = resources.componentList
+	f.extraIdentity = resources.extraIdentity
+
+	if f.scanner != nil {
+		if err := traced(ctx, "scanImages", func(ctx context.Context) error {
+			return scanImages(ctx, f.scanner, resources.imagesResources, f.maxSeverity)
+		}); err != nil {
+			return err
+		}
+	}
 
 	if resources.componentResource == nil || resources.ocmConfig == nil {
 		return fmt.Errorf("flux or ocm-config resource not found")
 	}
 
-	kfile, kus, err := f.generateKustomization(resources.componentResource)
-	if err != nil {
+	var (
+		kfile string
+		kus   kustypes.Kustomization
+	)
+	if err := traced(ctx, "generateKustomization", func(context.Context) error {
+		kfile, kus, err = f.generateKustomization(resources.componentResource)
+		return err
+	}); err != nil {
 		return err
 	}
 
@@ -127,32 +372,83 @@ func (f *fluxInstall) Install(ctx context.Context, component string) error {
 		return err
 	}
 
-	res, err := f.generateGOTKComponent(kconfig, resources.imagesResources, kus, kfile)
+	res, err := f.generateGOTKComponent(ctx, kconfig, resources.imagesResources, kus, kfile)
+	if err != nil {
+		return err
+	}
+
+	res, err = patchImageArchitecture(res, f.nodeArchitecture)
 	if err != nil {
+		return fmt.Errorf("failed to patch images for architecture %s: %w", f.nodeArchitecture, err)
+	}
+
+	if err := f.checkImageAutomationManifests(res); err != nil {
 		return err
 	}
 
-	err = f.reconcileComponents(ctx, fmt.Sprintf("%s/%s/%s", f.targetPath, f.namespace, "gotk-components.yaml"), string(res))
+	if f.ociSource {
+		ociRepository, err := f.generateOCIRepository()
+		if err != nil {
+			return err
+		}
+		res = append(append(res, []byte("---\n")...), ociRepository...)
+	}
+
+	err = traced(ctx, "reconcileComponents", func(ctx context.Context) error {
+		return f.reconcileComponents(ctx, fmt.Sprintf("%s/%s/%s", f.targetPath, f.namespace, "gotk-components.yaml"), string(res))
+	})
 	if err != nil {
 		return fmt.Errorf("failed to reconcile components: %w", err)
 	}
 
-	secretOpts := sourcesecret.Options{
-		Name:         f.namespace,
-		Namespace:    f.namespace,
-		TargetPath:   f.targetPath,
-		ManifestFile: sourcesecret.MakeDefaultOptions().ManifestFile,
-		Username:     "git",
-		Password:     f.token,
-		CAFile:       f.caFile,
+	err = traced(ctx, "reconcileHelmCharts", func(ctx context.Context) error {
+		return f.reconcileHelmCharts(ctx, resources.helmCharts)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile helm charts: %w", err)
+	}
+
+	err = traced(ctx, "reconcileReceivers", func(ctx context.Context) error {
+		return f.reconcileReceivers(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile receivers: %w", err)
+	}
+
+	if f.dryRun {
+		return nil
 	}
 
-	if err := f.fluxBootstrapper.ReconcileSourceSecret(ctx, secretOpts); err != nil {
+	if f.clusterOnly {
+		installOpts := install.Options{
+			Namespace:  f.namespace,
+			Components: f.components,
+		}
+		if err := traced(ctx, "reportComponentsHealth", func(ctx context.Context) error {
+			return f.fluxBootstrapper.ReportComponentsHealth(ctx, installOpts, f.timeout)
+		}); err != nil {
+			return fmt.Errorf("failed to report health, please try again later: %w", err)
+		}
+		return nil
+	}
+
+	secretOpts, err := f.sourceSecretOptions()
+	if err != nil {
 		return err
 	}
 
+	// The source secret is applied to the cluster only, it is never committed to the
+	// management repository and therefore needs no encryption at rest, e.g. with SOPS.
+	if err := traced(ctx, "reconcileSourceSecret", func(ctx context.Context) error {
+		return f.reconcileSourceSecret(ctx, secretOpts)
+	}); err != nil {
+		return err
+	}
+
+	// The GitRepository v1 API has no gitImplementation field, the source-controller
+	// only supports go-git since libgit2 was removed.
 	syncOpts := syncOpts.Options{
-		Interval:          f.interval,
+		Interval:          f.componentInterval(component),
 		Name:              f.namespace,
 		Namespace:         f.namespace,
 		URL:               f.url,
@@ -163,48 +459,183 @@ func (f *fluxInstall) Install(ctx context.Context, component string) error {
 		RecurseSubmodules: false,
 	}
 
+	if f.sshURL != "" {
+		syncOpts.URL = f.sshURL
+	}
+
 	if f.testURL != "" {
 		syncOpts.URL = f.testURL
 	}
 
-	if err := f.fluxBootstrapper.ReconcileSyncConfig(ctx, syncOpts); err != nil {
+	if err := traced(ctx, "reconcileSyncConfig", func(ctx context.Context) error {
+		return f.fluxBootstrapper.ReconcileSyncConfig(ctx, syncOpts)
+	}); err != nil {
 		return fmt.Errorf("failed to reconcile sync config: %w", err)
 	}
 
-	var healthErr error
-	if err := f.fluxBootstrapper.ReportKustomizationHealth(ctx, syncOpts, env.DefaultPollInterval, f.timeout); err != nil {
-		healthErr = errors.Join(healthErr, err)
+	if err := traced(ctx, "reconcileAlerts", func(ctx context.Context) error {
+		return f.reconcileAlerts(ctx)
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile alerts: %w", err)
 	}
 
-	installOpts := install.Options{
-		Namespace:  f.namespace,
-		Components: f.components,
-	}
-	if err := f.fluxBootstrapper.ReportComponentsHealth(ctx, installOpts, f.timeout); err != nil {
-		healthErr = errors.Join(healthErr, err)
+	if err := traced(ctx, "reconcileImageAutomation", func(ctx context.Context) error {
+		return f.reconcileImageAutomation(ctx)
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile image automation: %w", err)
 	}
+
+	healthErr := traced(ctx, "reportHealth", func(ctx context.Context) error {
+		installOpts := install.Options{
+			Namespace:  f.namespace,
+			Components: f.components,
+		}
+		return runHealthChecks(ctx, f.timeout,
+			func(ctx context.Context) error {
+				return f.fluxBootstrapper.ReportKustomizationHealth(ctx, syncOpts, env.DefaultPollInterval, f.timeout)
+			},
+			func(ctx context.Context) error {
+				return f.fluxBootstrapper.ReportComponentsHealth(ctx, installOpts, f.timeout)
+			},
+		)
+	})
 	if healthErr != nil {
-		return fmt.Errorf("failed to report health, please try again later: %w", healthErr)
+		err := fmt.Errorf("failed to report health, please try again later: %w", healthErr)
+		reports, reportErr := f.HealthCheck(ctx)
+		if reportErr != nil {
+			return errors.Join(err, reportErr)
+		}
+		return &HealthReportError{Reports: reports, Err: err}
 	}
 
 	return nil
 }
 
-func (f *fluxInstall) generateGOTKComponent(kconfig *cfd.ConfigData, imagesResources map[string]nameTag, kus kustypes.Kustomization, kfile string) ([]byte, error) {
+// fluxLogger returns the logger for the flux bootstrapper. Flux logs are discarded
+// unless a logger is configured.
+func (f *fluxInstall) fluxLogger() log.Logger {
+	if f.logger == nil {
+		return log.NopLogger{}
+	}
+	return newFluxLogger(f.logger)
+}
+
+// withLogger sets the logger receiving the flux bootstrap logs.
+func withLogger(logger *slog.Logger) fluxOption {
+	return func(o *fluxOptions) {
+		o.logger = logger
+	}
+}
+
+// componentInterval returns the sync interval of the given component.
+func (f *fluxInstall) componentInterval(component string) time.Duration {
+	if interval, ok := f.componentIntervals[component]; ok {
+		return interval
+	}
+	return f.interval
+}
+
+func (f *fluxInstall) generateGOTKComponent(ctx context.Context, kconfig *cfd.ConfigData, imagesResources map[string]nameTag, kus kustypes.Kustomization, kfile string) ([]byte, error) {
 	for _, loc := range kconfig.Localization {
 		image := imagesResources[loc.Resource.Name]
 		kus.Images = append(kus.Images, kustypes.Image{
 			Name:    fmt.Sprintf("%s/%s", env.DefaultFluxHost, loc.Resource.Name),
 			NewName: image.Name,
 			NewTag:  image.Tag,
+			Digest:  image.Digest,
 		})
 	}
 
-	return buildKustomization(kus, kfile, f.dir, &f.mu)
+	kus.Images = mirrorImages(kus.Images, f.imageMirrors)
+	kus.Patches = append(kus.Patches, f.kustomizationPatches...)
+	if patch := f.sourceControllerPullPolicyPatch(); patch != nil {
+		kus.Patches = append(kus.Patches, *patch)
+	}
+	// the annotations are added before the OIDC patches, which merge their annotation
+	saPatches, err := f.serviceAccountAnnotationPatches()
+	if err != nil {
+		return nil, err
+	}
+	kus.Patches = append(kus.Patches, saPatches...)
+	oidcPatches, err := f.oidcPatches()
+	if err != nil {
+		return nil, err
+	}
+	kus.Patches = append(kus.Patches, oidcPatches...)
+	resourcePatches, err := f.resourcePatches()
+	if err != nil {
+		return nil, err
+	}
+	kus.Patches = append(kus.Patches, resourcePatches...)
+
+	if f.annotationPropagation && len(f.extraIdentity) > 0 {
+		kus.CommonAnnotations = mergeAnnotations(kus.CommonAnnotations, extraIdentityAnnotations(f.extraIdentity))
+	}
+
+	if err := f.addPodDisruptionBudgets(&kus); err != nil {
+		return nil, err
+	}
+
+	if err := f.addVerticalPodAutoscalers(&kus); err != nil {
+		return nil, err
+	}
+
+	if err := f.addAdditionalCRDs(&kus); err != nil {
+		return nil, err
+	}
+
+	if f.namespace != env.DefaultFluxNamespace {
+		kus.Namespace = f.namespace
+	}
+
+	if f.sandboxedBuild {
+		resources, err := readFiles(f.dir, filepath.Base(kfile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read resources: %w", err)
+		}
+		return buildKustomizationSandboxed(ctx, kus, resources)
+	}
+
+	return buildKustomization(ctx, kus, kfile, f.dir)
+}
+
+// generateOCIRepository generates a Flux OCIRepository referencing the component descriptor
+// of the bootstrap component in the OCM registry.
+func (f *fluxInstall) generateOCIRepository() ([]byte, error) {
+	ociRepository := sourcev1beta2.OCIRepository{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: sourcev1beta2.GroupVersion.String(),
+			Kind:       sourcev1beta2.OCIRepositoryKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ociSourceName,
+			Namespace: f.namespace,
+		},
+		Spec: sourcev1beta2.OCIRepositorySpec{
+			URL: ociSourceURL(f.registry),
+			Reference: &sourcev1beta2.OCIRepositoryRef{
+				SemVer: "*",
+			},
+			Interval: metav1.Duration{Duration: f.componentInterval(ociSourceName)},
+		},
+	}
+
+	data, err := yaml.Marshal(ociRepository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal OCIRepository: %w", err)
+	}
+
+	return data, nil
+}
+
+// ociSourceURL returns the OCI URL of the bootstrap component descriptor in the given OCM registry.
+func ociSourceURL(registry string) string {
+	registry = strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://")
+	return fmt.Sprintf("oci://%s/component-descriptors/%s", strings.TrimSuffix(registry, "/"), env.DefaultBootstrapComponent)
 }
 
-func (f *fluxInstall) generateKustomization(fluxResource []byte) (string, kustypes.Kustomization, error) {
-	if err := os.WriteFile(filepath.Join(f.dir, "gotk-components.yaml"), fluxResource, os.ModePerm); err != nil {
+func (f *fluxInstall) generateKustomization(fluxResource io.Reader) (string, kustypes.Kustomization, error) {
+	if err := writeResource(filepath.Join(f.dir, "gotk-components.yaml"), fluxResource); err != nil {
 		return "", kustypes.Kustomization{}, err
 	}
 
@@ -216,6 +647,30 @@ func (f *fluxInstall) Cleanup(ctx context.Context) error {
 }
 
 func (f *fluxInstall) reconcileComponents(ctx context.Context, path, content string) error {
+	defer f.metrics.observeStep(f.componentName, stepReconcile, time.Now())
+
+	if f.dryRun {
+		printDryRunPreview(f.printer, path, []byte(content))
+		return nil
+	}
+
+	if f.preApplyValidation {
+		if err := validateManifests(ctx, f.kubeClient, []byte(content)); err != nil {
+			return err
+		}
+	}
+
+	if f.clusterOnly {
+		rest, err := f.applyCRDs(ctx, []byte(content))
+		if err != nil {
+			return err
+		}
+		if len(rest) == 0 {
+			return nil
+		}
+		return applyManifest(ctx, f.applier, f.restClientGetter, filepath.Join(f.dir, "cluster-only"), filepath.Base(path), rest)
+	}
+
 	err := f.cloneRepository(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to clone repository: %w", err)
@@ -228,39 +683,65 @@ func (f *fluxInstall) reconcileComponents(ctx context.Context, path, content str
 
 	// Conditionally install manifests
 	if f.mustInstallManifests(ctx) {
+		// the CRDs are applied again with the components, which does not change them
+		if _, err := f.applyCRDs(ctx, []byte(content)); err != nil {
+			return err
+		}
+
 		componentsYAML := filepath.Join(f.gitClient.Path(), path)
-		kfile := filepath.Join(filepath.Dir(componentsYAML), konfig.DefaultKustomizationFileName())
-		if _, err := os.Stat(kfile); err == nil {
-			// Apply the components and their patches
-			if _, err := kubeutils.Apply(ctx, f.restClientGetter, f.gitClient.Path(), kfile); err != nil {
-				return fmt.Errorf("failed to apply components: %w", err)
-			}
-		} else {
-			// Apply the CRDs and controllers
-			if _, err := kubeutils.Apply(ctx, f.restClientGetter, f.gitClient.Path(), componentsYAML); err != nil {
-				return fmt.Errorf("failed to apply components: %w", err)
-			}
+		if err := f.applyComponents(ctx, componentsYAML); err != nil {
+			return fmt.Errorf("failed to apply components: %w", err)
 		}
 	}
 	return nil
 }
 
+// applyComponents applies the components manifest, together with its patches if it has a kustomization.
+func (f *fluxInstall) applyComponents(ctx context.Context, componentsYAML string) error {
+	kfile := filepath.Join(filepath.Dir(componentsYAML), konfig.DefaultKustomizationFileName())
+	if _, err := os.Stat(kfile); err == nil {
+		// Apply the components and their patches
+		_, err := f.applier.apply(ctx, f.restClientGetter, f.gitClient.Path(), kfile)
+		return err
+	}
+
+	// Apply the CRDs and controllers
+	_, err := f.applier.apply(ctx, f.restClientGetter, f.gitClient.Path(), componentsYAML)
+	return err
+}
+
 func (f *fluxInstall) mustInstallManifests(ctx context.Context) bool {
 	return kubeutils.MustInstallKustomization(ctx, f.kubeClient, f.namespace, f.namespace)
 }
 
-func (f *fluxInstall) commitAndPushComponents(ctx context.Context, path string, content string) (err error) {
-	commitMsg := fmt.Sprintf("Add Flux %s component manifests", f.version)
+func (f *fluxInstall) commitAndPushComponents(ctx context.Context, path string, content string) error {
+	return f.commitAndPush(ctx, fmt.Sprintf("Add Flux %s component manifests", f.version), map[string]io.Reader{
+		path: strings.NewReader(content),
+	})
+}
+
+// commitAndPush commits the given files with the commit message and pushes them.
+// Nothing is pushed if the files are unchanged.
+func (f *fluxInstall) commitAndPush(ctx context.Context, commitMsg string, files map[string]io.Reader) (err error) {
 	if f.commitMessageAppendix != "" {
 		commitMsg = commitMsg + "\n\n" + f.commitMessageAppendix
 	}
 
+	commitOpts := []repository.CommitOption{
+		repository.WithFiles(files),
+	}
+	if f.signingKeyPath != "" {
+		signer, err := loadSigningEntity(f.signingKeyPath, f.signingKeyPassphrase)
+		if err != nil {
+			return err
+		}
+		commitOpts = append(commitOpts, repository.WithSigner(signer))
+	}
+
 	_, err = f.gitClient.Commit(git.Commit{
-		Author:  git.Signature{Name: "Flux"},
+		Author:  f.commitAuthor(),
 		Message: commitMsg,
-	}, repository.WithFiles(map[string]io.Reader{
-		path: strings.NewReader(content),
-	}))
+	}, commitOpts...)
 	if err != nil && !errors.Is(err, git.ErrNoStagedFiles) {
 		return fmt.Errorf("failed to commit sync manifests: %w", err)
 	}
@@ -273,7 +754,22 @@ func (f *fluxInstall) commitAndPushComponents(ctx context.Context, path string,
 	return nil
 }
 
+// commitAuthor returns the configured commit author, falling back to the default
+// name and email for the parts that are not set.
+func (f *fluxInstall) commitAuthor() git.Signature {
+	author := git.Signature{Name: f.commitAuthorName, Email: f.commitAuthorEmail}
+	if author.Name == "" {
+		author.Name = defaultCommitAuthorName
+	}
+	if author.Email == "" {
+		author.Email = defaultCommitAuthorEmail
+	}
+	return author
+}
+
 func (f *fluxInstall) cloneRepository(ctx context.Context) error {
+	defer f.metrics.observeStep(f.componentName, stepClone, time.Now())
+
 	if _, err := f.gitClient.Head(); err != nil {
 		if !errors.Is(err, git.ErrNoGitRepository) {
 			return err
@@ -286,6 +782,7 @@ func (f *fluxInstall) cloneRepository(ctx context.Context) error {
 				CheckoutStrategy: repository.CheckoutStrategy{
 					Branch: f.branch,
 				},
+				ShallowClone: f.cloneDepth > 0,
 			})
 			if err != nil {
 				return err
@@ -360,9 +857,23 @@ func generateKustomizationFile(path, resource string) (string, error) {
 	return kfile, os.WriteFile(kfile, kd, os.ModePerm)
 }
 
-func unMarshallConfig(data []byte) (*cfd.ConfigData, error) {
+// writeResource writes the content of the given reader to path.
+func writeResource(path string, r io.Reader) (err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+
+	_, err = io.Copy(f, r)
+	return err
+}
+
+func unMarshallConfig(r io.Reader) (*cfd.ConfigData, error) {
 	k := &cfd.ConfigData{}
-	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewBuffer(data), len(data))
+	decoder := k8syaml.NewYAMLOrJSONDecoder(r, 4096)
 	err := decoder.Decode(k)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode config data: %w", err)
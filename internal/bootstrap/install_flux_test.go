@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sourcev1beta2 "github.com/fluxcd/source-controller/api/v1beta2"
+	"github.com/open-component-model/mpas/internal/printer"
+	cfd "github.com/open-component-model/ocm-controller/pkg/configdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/yaml"
+)
+
+func TestFluxInstallDryRun(t *testing.T) {
+	out := &bytes.Buffer{}
+	p, err := printer.Newprinter(out)
+	require.NoError(t, err)
+
+	kubeClient := fake.NewClientBuilder().Build()
+	gitClient := &recordingGitClient{}
+	f := &fluxInstall{
+		componentName: "ocm.software/mpas/flux",
+		version:       "v2.0.0",
+		fluxOptions: &fluxOptions{
+			gitClient:  gitClient,
+			kubeClient: kubeClient,
+			namespace:  "flux-system",
+			dryRun:     true,
+			printer:    p,
+		},
+	}
+
+	err = f.reconcileComponents(context.Background(), "target/flux-system/gotk-components.yaml", string(kustomizedDeployment))
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "[dry-run] target/flux-system/gotk-components.yaml")
+	assert.Contains(t, out.String(), "image: ghcr.io/user/git-controller:v1.0.0")
+
+	deployments := &appsv1.DeploymentList{}
+	require.NoError(t, kubeClient.List(context.Background(), deployments))
+	assert.Empty(t, deployments.Items, "no objects must be created in dry-run mode")
+	assert.Empty(t, gitClient.calls, "the management repository must not be used in dry-run mode")
+}
+
+func TestFluxInstallComponentInterval(t *testing.T) {
+	opts := &fluxOptions{
+		interval: 5 * time.Minute,
+	}
+	withComponentInterval("flux", 30*time.Second)(opts)
+
+	f := &fluxInstall{fluxOptions: opts}
+	assert.Equal(t, 30*time.Second, f.componentInterval("flux"))
+	assert.Equal(t, 5*time.Minute, f.componentInterval("other"))
+}
+
+func TestFluxInstallClusterOnly(t *testing.T) {
+	var applied []string
+	applier := func(_ context.Context, _ genericclioptions.RESTClientGetter, _, manifestPath string) (string, error) {
+		data, err := os.ReadFile(manifestPath)
+		if err != nil {
+			return "", err
+		}
+		applied = append(applied, filepath.Base(manifestPath))
+		assert.Contains(t, string(data), "image: ghcr.io/user/git-controller:v1.0.0")
+		return "", nil
+	}
+
+	gitClient := &recordingGitClient{}
+	f := &fluxInstall{
+		componentName: "ocm.software/mpas/flux",
+		version:       "v2.0.0",
+		fluxOptions: &fluxOptions{
+			gitClient:   gitClient,
+			applier:     applier,
+			dir:         t.TempDir(),
+			namespace:   "flux-system",
+			clusterOnly: true,
+		},
+	}
+
+	err := f.reconcileComponents(context.Background(), "target/flux-system/gotk-components.yaml", string(kustomizedDeployment))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gotk-components.yaml"}, applied)
+	assert.Empty(t, gitClient.calls, "the management repository must not be used in cluster-only mode")
+}
+
+func TestFluxInstallOCISource(t *testing.T) {
+	opts := &fluxOptions{
+		namespace: "flux-system",
+		registry:  "https://ghcr.io/open-component-model/mpas-bootstrap-component/",
+		interval:  time.Minute,
+	}
+	withFluxOCISource(true)(opts)
+	require.True(t, opts.ociSource)
+
+	f := &fluxInstall{fluxOptions: opts}
+	data, err := f.generateOCIRepository()
+	require.NoError(t, err)
+
+	ociRepository := &sourcev1beta2.OCIRepository{}
+	require.NoError(t, yaml.Unmarshal(data, ociRepository))
+	assert.Equal(t, "OCIRepository", ociRepository.Kind)
+	assert.Equal(t, "flux-system", ociRepository.Namespace)
+	assert.Equal(t, "oci://ghcr.io/open-component-model/mpas-bootstrap-component/component-descriptors/ocm.software/mpas/bootstrap", ociRepository.Spec.URL)
+	assert.Equal(t, time.Minute, ociRepository.Spec.Interval.Duration)
+}
+
+func TestFluxKustomizationPatches(t *testing.T) {
+	b := &Bootstrap{}
+	WithKustomizationPatches([]kustypes.Patch{
+		{
+			Patch: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: git-controller
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        resources:
+          limits:
+            memory: 2Gi
+`,
+		},
+		{
+			Patch:  `[{"op": "replace", "path": "/spec/replicas", "value": 2}]`,
+			Target: &kustypes.Selector{ResId: resid.ResId{Gvk: resid.Gvk{Kind: "Deployment"}, Name: "git-controller"}},
+		},
+	})(&b.options)
+	opts, fopts := b.newFluxOptions(t.TempDir(), nil)
+	for _, o := range fopts {
+		o(opts)
+	}
+
+	f := &fluxInstall{fluxOptions: opts}
+	kfile, kus, err := f.generateKustomization(bytes.NewReader(kustomizedDeployment))
+	require.NoError(t, err)
+	res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+	require.NoError(t, err)
+
+	deployment := &appsv1.Deployment{}
+	require.NoError(t, yaml.Unmarshal(res, deployment))
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+	require.Len(t, deployment.Spec.Template.Spec.Containers, 1)
+	assert.Equal(t, "ghcr.io/user/git-controller:v1.0.0", deployment.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "2Gi", deployment.Spec.Template.Spec.Containers[0].Resources.Limits.Memory().String())
+}
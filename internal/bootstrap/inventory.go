@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/printer"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// HealthStatusHealthy is reported if all deployments of the component are available.
+	HealthStatusHealthy = "Healthy"
+	// HealthStatusUnhealthy is reported if a deployment of the component is not available.
+	HealthStatusUnhealthy = "Unhealthy"
+	// HealthStatusMissing is reported if no deployment of the component exists in the cluster.
+	HealthStatusMissing = "Missing"
+	// HealthStatusUnknown is reported if the cluster could not be queried.
+	HealthStatusUnknown = "Unknown"
+)
+
+// ComponentVersion is a bootstrap component installed by a previous bootstrap.
+type ComponentVersion struct {
+	// Name is the name of the component reference in the bootstrap component, e.g. flux.
+	Name string
+	// Version is the installed version of the component.
+	Version string
+	// Namespace is the namespace the component is installed into.
+	Namespace string
+	// InstalledAt is the time the component was installed. It is zero if the
+	// management repository does not record the installation.
+	InstalledAt time.Time
+	// HealthStatus is the health of the component's deployments in the cluster.
+	HealthStatus string
+}
+
+// Inventory returns the installed bootstrap components and their versions.
+// The components are read from the lock file and the installation times from the bootstrap
+// state file, so the inventory does not depend on Run being called by the same process.
+// If a kubernetes client is configured, the health of the components is checked in the cluster.
+func (b *Bootstrap) Inventory(ctx context.Context) ([]ComponentVersion, error) {
+	if !b.clusterOnly && b.repository == nil {
+		defer b.releaseBootstrapLockOnExit(ctx)
+		if err := b.inSpinner(fmt.Sprintf("Preparing Management repository %s",
+			printer.BoldBlue(b.repositoryName)), func() error {
+			return b.reconcileManagementRepository(ctx)
+		}); err != nil {
+			return nil, fmt.Errorf("failed to prepare management repository: %w", err)
+		}
+	}
+
+	store := b.lockStore()
+	if store == nil {
+		return nil, fmt.Errorf("no lock file store is configured")
+	}
+
+	lock, err := store.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &bootstrapState{}
+	if b.repository != nil {
+		state, err = b.readBootstrapState(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	inventory := make([]ComponentVersion, 0, len(lock.Components))
+	for _, entry := range lock.Components {
+		cv := ComponentVersion{
+			Name:         entry.Name,
+			Version:      entry.Version,
+			Namespace:    b.inventoryNamespace(entry.Name),
+			InstalledAt:  state.completedAt(phaseComponentInstall, entry.ComponentName, entry.Version),
+			HealthStatus: HealthStatusUnknown,
+		}
+
+		if b.kubeclient != nil && cv.Namespace != "" {
+			cv.HealthStatus, err = b.componentHealth(ctx, entry.Name, cv.Namespace)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		inventory = append(inventory, cv)
+	}
+
+	return inventory, nil
+}
+
+// inventoryNamespace returns the namespace the given component is installed into.
+// It returns an empty string for unknown components.
+func (b *Bootstrap) inventoryNamespace(comp string) string {
+	switch comp {
+	case env.FluxName:
+		return b.componentNamespace(comp, env.DefaultFluxNamespace)
+	case env.CertManagerName:
+		return env.DefaultCertManagerNamespace
+	}
+
+	ns, _, err := b.componentDeployments(comp)
+	if err != nil {
+		return ""
+	}
+
+	return ns
+}
+
+// componentHealth returns the health of the deployments of the given component.
+// All deployments in the namespace are checked for components without a known set of deployments.
+func (b *Bootstrap) componentHealth(ctx context.Context, comp, namespace string) (string, error) {
+	var deployments appsv1.DeploymentList
+	if err := b.kubeclient.List(ctx, &deployments, client.InNamespace(namespace)); err != nil {
+		return "", fmt.Errorf("failed to list deployments in namespace %s: %w", namespace, err)
+	}
+
+	names := make(map[string]bool)
+	if _, known, err := defaultComponentDeployments(comp); err == nil {
+		for _, name := range known {
+			names[name] = true
+		}
+	}
+
+	found := 0
+	for _, d := range deployments.Items {
+		if len(names) > 0 && !names[d.Name] {
+			continue
+		}
+		found++
+		if d.Status.AvailableReplicas < ptr.Deref(d.Spec.Replicas, 1) {
+			return HealthStatusUnhealthy, nil
+		}
+	}
+
+	if found == 0 {
+		return HealthStatusMissing, nil
+	}
+
+	return HealthStatusHealthy, nil
+}
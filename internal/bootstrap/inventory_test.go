@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestInventory(t *testing.T) {
+	lockFile := `components:
+- name: flux
+  componentName: ocm.software/mpas/flux
+  version: v2.1.0
+- name: cert-manager
+  componentName: ocm.software/mpas/cert-manager
+  version: v1.13.1
+- name: ocm-controller
+  componentName: ocm.software/mpas/ocm-controller
+  version: v0.14.0
+`
+	stateFile := `{"phases": [
+  {"phase": "component-install", "componentName": "ocm.software/mpas/ocm-controller", "version": "v0.14.0", "completedAt": "2023-10-01T12:00:00Z"},
+  {"phase": "component-install", "componentName": "ocm.software/mpas/flux", "version": "v2.0.0", "completedAt": "2023-09-01T12:00:00Z"}
+]}`
+
+	deployment := func(name, namespace string, available int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](1)},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: available},
+		}
+	}
+
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+	kubeclient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		deployment("source-controller", env.DefaultFluxNamespace, 1),
+		deployment("kustomize-controller", env.DefaultFluxNamespace, 1),
+		deployment("ocm-controller", env.DefaultOCMNamespace, 0),
+		deployment("unrelated", env.DefaultOCMNamespace, 1),
+	).Build()
+
+	b := &Bootstrap{
+		providerClient: &mockProviderClient{providerID: env.ProviderGitea},
+		repository: &mockGitRepository{fileClient: &mockFileClient{files: []*gitprovider.CommitFile{
+			{Path: ptr.To(lockFileName), Content: ptr.To(lockFile)},
+			{Path: ptr.To("clusters/" + bootstrapStateFileName), Content: ptr.To(stateFile)},
+		}}},
+		options: options{defaultBranch: "main", targetPath: "clusters", kubeclient: kubeclient},
+	}
+
+	inventory, err := b.Inventory(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []ComponentVersion{
+		{
+			Name:      "flux",
+			Version:   "v2.1.0",
+			Namespace: env.DefaultFluxNamespace,
+			// InstalledAt is not set because the recorded installation is of a different version
+			HealthStatus: HealthStatusHealthy,
+		},
+		{
+			Name:         "cert-manager",
+			Version:      "v1.13.1",
+			Namespace:    env.DefaultCertManagerNamespace,
+			HealthStatus: HealthStatusMissing,
+		},
+		{
+			Name:         "ocm-controller",
+			Version:      "v0.14.0",
+			Namespace:    env.DefaultOCMNamespace,
+			InstalledAt:  time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC),
+			HealthStatus: HealthStatusUnhealthy,
+		},
+	}, inventory)
+}
+
+func TestInventoryWithoutCluster(t *testing.T) {
+	b := &Bootstrap{
+		providerClient: &mockProviderClient{providerID: env.ProviderGitea},
+		repository: &mockGitRepository{fileClient: &mockFileClient{files: []*gitprovider.CommitFile{
+			{Path: ptr.To(lockFileName), Content: ptr.To("components:\n- name: flux\n  version: v2.1.0\n")},
+		}}},
+		options: options{
+			defaultBranch:       "main",
+			componentNamespaces: map[string]string{env.FluxName: "gitops"},
+		},
+	}
+
+	inventory, err := b.Inventory(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []ComponentVersion{
+		{Name: "flux", Version: "v2.1.0", Namespace: "gitops", HealthStatus: HealthStatusUnknown},
+	}, inventory)
+}
@@ -5,15 +5,18 @@
 package bootstrap
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
-	"sync"
 
 	"github.com/fluxcd/pkg/kustomize"
 	cfd "github.com/open-component-model/ocm-controller/pkg/configdata"
 	"github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	kustypes "sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 	"sigs.k8s.io/yaml"
@@ -25,18 +28,21 @@ type kustomizerOptions struct {
 	componentName string
 	version       string
 	host          string
+	// namespace overrides the namespace of all namespaced resources if set
+	namespace string
+	// publicKey is used to verify the component's signature if set
+	publicKey []byte
+	// imageMirrors maps image prefixes to the mirror to pull them from
+	imageMirrors map[string]string
 }
 
 // Kustomizer can kustomize a given component and change image information.
 type Kustomizer interface {
-	GenerateKustomizedResourceData(component string) ([]byte, error)
+	GenerateKustomizedResourceData(ctx context.Context, component string) ([]byte, error)
 }
 
 type Kustomize struct {
 	*kustomizerOptions
-
-	// mu is used to synchronize access to the kustomization file
-	mu sync.Mutex
 }
 
 // NewKustomizer creates a new kustomizer based on mutation options.
@@ -46,16 +52,30 @@ func NewKustomizer(opts *kustomizerOptions) *Kustomize {
 	}
 }
 
-func (k *Kustomize) GenerateKustomizedResourceData(component string) ([]byte, error) {
+func (k *Kustomize) GenerateKustomizedResourceData(ctx context.Context, component string) (_ []byte, err error) {
+	ctx, span := startSpan(ctx, "Kustomize.GenerateKustomizedResourceData", trace.WithAttributes(
+		attribute.String("component", k.componentName),
+		attribute.String("version", k.version),
+	))
+	defer func() {
+		_ = endSpan(span, err)
+		span.End()
+	}()
+
 	cv, err := getComponentVersion(k.repository, k.componentName, k.version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get component version: %w", err)
 	}
 
-	resources, err := getResources(cv, component)
+	if err := verifyComponentVersion(cv, k.publicKey); err != nil {
+		return nil, err
+	}
+
+	resources, err := getResources(cv, component, 0, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get resources: %w", err)
 	}
+	defer resources.Close()
 
 	if resources.componentResource == nil || resources.ocmConfig == nil {
 		return nil, fmt.Errorf("failed to get component resource or ocm config")
@@ -71,47 +91,116 @@ func (k *Kustomize) GenerateKustomizedResourceData(component string) ([]byte, er
 		return nil, fmt.Errorf("failed to unmarshall config: %w", err)
 	}
 
-	return k.generateComponentYaml(kconfig, resources.imagesResources, kus, kfile)
+	return k.generateComponentYaml(ctx, kconfig, resources.imagesResources, kus, kfile)
 }
 
-func (k *Kustomize) generateKustomization(componentResource []byte) (string, kustypes.Kustomization, error) {
-	if err := os.WriteFile(filepath.Join(k.dir, fmt.Sprintf("%s.yaml", strings.Split(k.componentName, "/")[2])), componentResource, os.ModePerm); err != nil {
+func (k *Kustomize) generateKustomization(componentResource io.Reader) (string, kustypes.Kustomization, error) {
+	if err := writeResource(filepath.Join(k.dir, fmt.Sprintf("%s.yaml", strings.Split(k.componentName, "/")[2])), componentResource); err != nil {
 		return "", kustypes.Kustomization{}, err
 	}
 
 	return genKus(k.dir, fmt.Sprintf("./%s.yaml", strings.Split(k.componentName, "/")[2]))
 }
 
-func (k *Kustomize) generateComponentYaml(kconfig *cfd.ConfigData, imagesResources map[string]nameTag, kus kustypes.Kustomization, kfile string) ([]byte, error) {
+func (k *Kustomize) generateComponentYaml(ctx context.Context, kconfig *cfd.ConfigData, imagesResources map[string]nameTag, kus kustypes.Kustomization, kfile string) ([]byte, error) {
 	for _, loc := range kconfig.Localization {
 		image := imagesResources[loc.Resource.Name]
 		kus.Images = append(kus.Images, kustypes.Image{
 			Name:    fmt.Sprintf("%s/%s", k.host, loc.Resource.Name),
 			NewName: image.Name,
 			NewTag:  image.Tag,
+			Digest:  image.Digest,
 		})
 	}
 
-	return buildKustomization(kus, kfile, k.dir, &k.mu)
+	kus.Images = mirrorImages(kus.Images, k.imageMirrors)
+
+	if k.namespace != "" {
+		kus.Namespace = k.namespace
+	}
+
+	return buildKustomization(ctx, kus, kfile, k.dir)
 }
 
-func buildKustomization(kus kustypes.Kustomization, kfile, dir string, mu sync.Locker) ([]byte, error) {
+// buildKustomization builds kus with the resources in dir. The build runs in an isolated copy of dir,
+// so it is safe to call concurrently.
+func buildKustomization(ctx context.Context, kus kustypes.Kustomization, kfile, dir string) (_ []byte, err error) {
+	_, span := startSpan(ctx, "buildKustomization")
+	defer func() {
+		_ = endSpan(span, err)
+		span.End()
+	}()
+
 	manifest, err := yaml.Marshal(kus)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal kustomization: %w", err)
 	}
 
-	err = os.WriteFile(kfile, manifest, os.ModePerm)
+	buildDir, err := os.MkdirTemp("", "kustomize-build")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create build directory: %w", err)
+	}
+	defer os.RemoveAll(buildDir)
+
+	if err := copyFiles(dir, buildDir, filepath.Base(kfile)); err != nil {
+		return nil, fmt.Errorf("failed to copy resources: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(buildDir, filepath.Base(kfile)), manifest, os.ModePerm)
 	if err != nil {
 		return nil, fmt.Errorf("failed to write file: %w", err)
 	}
 
 	fs := filesys.MakeFsOnDisk()
 
-	mu.Lock()
-	defer mu.Unlock()
+	m, err := kustomize.Build(fs, buildDir)
+	if err != nil {
+		return nil, fmt.Errorf("kustomize build failed: %w", err)
+	}
 
-	m, err := kustomize.Build(fs, dir)
+	res, err := m.AsYaml()
+	if err != nil {
+		return nil, fmt.Errorf("kustomize failed to generate yaml: %w", err)
+	}
+	return res, nil
+}
+
+// sandboxDir is the directory the sandboxed build runs in, within its in-memory file system.
+const sandboxDir = "/kustomize-build"
+
+// buildKustomizationSandboxed builds kus with the given resources, keyed by file name, in an
+// in-memory file system. Nothing is written to disk, at the cost of holding all resources in memory.
+func buildKustomizationSandboxed(ctx context.Context, kus kustypes.Kustomization, resources map[string][]byte) (_ []byte, err error) {
+	_, span := startSpan(ctx, "buildKustomizationSandboxed")
+	defer func() {
+		_ = endSpan(span, err)
+		span.End()
+	}()
+
+	manifest, err := yaml.Marshal(kus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal kustomization: %w", err)
+	}
+
+	fs := filesys.MakeFsInMemory()
+	if err := fs.MkdirAll(sandboxDir); err != nil {
+		return nil, fmt.Errorf("failed to create build directory: %w", err)
+	}
+
+	for name, data := range resources {
+		if name != filepath.Base(name) {
+			return nil, fmt.Errorf("resource %s must be a plain file name", name)
+		}
+		if err := fs.WriteFile(filepath.Join(sandboxDir, name), data); err != nil {
+			return nil, fmt.Errorf("failed to write resource %s: %w", name, err)
+		}
+	}
+
+	if err := fs.WriteFile(filepath.Join(sandboxDir, "kustomization.yaml"), manifest); err != nil {
+		return nil, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	m, err := kustomize.Build(fs, sandboxDir)
 	if err != nil {
 		return nil, fmt.Errorf("kustomize build failed: %w", err)
 	}
@@ -122,3 +211,43 @@ func buildKustomization(kus kustypes.Kustomization, kfile, dir string, mu sync.L
 	}
 	return res, nil
 }
+
+// readFiles reads the regular files at the top level of dir, except for the file named skip.
+func readFiles(dir, skip string) (map[string][]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte, len(entries))
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || entry.Name() == skip {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files[entry.Name()] = data
+	}
+
+	return files, nil
+}
+
+// copyFiles copies the regular files at the top level of src to dst, except for the file named skip.
+// Subdirectories are not copied, as dir may also hold the clone of the management repository.
+func copyFiles(src, dst, skip string) error {
+	files, err := readFiles(src, skip)
+	if err != nil {
+		return err
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dst, name), data, os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
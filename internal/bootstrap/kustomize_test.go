@@ -2,11 +2,17 @@ package bootstrap
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/open-component-model/ocm-controller/pkg/fakes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	kustypes "sigs.k8s.io/kustomize/api/types"
 )
 
 var testConfigData = []byte(`apiVersion: config.ocm.software/v1alpha1
@@ -41,10 +47,8 @@ spec:
         image: ghcr.io/user/git-controller:v1.0.0
 `)
 
-func TestKustomize(t *testing.T) {
-	tmp := t.TempDir()
-	componentName := "ocm.software/mpas/git-controller"
-	repo := &mockRepository{
+func newKustomizeTestRepository(componentName string) *mockRepository {
+	return &mockRepository{
 		name:    "test-repo",
 		version: "v1.0.0",
 		cv: []*mockComponentAccess{
@@ -91,15 +95,130 @@ func TestKustomize(t *testing.T) {
 			},
 		},
 	}
+}
+
+func TestKustomize(t *testing.T) {
+	componentName := "ocm.software/mpas/git-controller"
 	kustomizer := NewKustomizer(&kustomizerOptions{
-		dir:           tmp,
-		repository:    repo,
+		dir:           t.TempDir(),
+		repository:    newKustomizeTestRepository(componentName),
 		componentName: componentName,
 		version:       "v1.0.0",
 		host:          "ghcr.io/user",
 	})
 
-	out, err := kustomizer.GenerateKustomizedResourceData(componentName)
+	out, err := kustomizer.GenerateKustomizedResourceData(context.Background(), componentName)
 	require.NoError(t, err)
 	assert.True(t, bytes.Contains(out, []byte("ghcr.io/new-user/git-controller:v1.0.0")), "expected localized image to be present in output")
 }
+
+func TestKustomizeImageMirror(t *testing.T) {
+	componentName := "ocm.software/mpas/git-controller"
+	kustomizer := NewKustomizer(&kustomizerOptions{
+		dir:           t.TempDir(),
+		repository:    newKustomizeTestRepository(componentName),
+		componentName: componentName,
+		version:       "v1.0.0",
+		host:          "ghcr.io/user",
+		imageMirrors:  map[string]string{"ghcr.io/new-user": "my-mirror.corp/new-user"},
+	})
+
+	out, err := kustomizer.GenerateKustomizedResourceData(context.Background(), componentName)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "image: my-mirror.corp/new-user/git-controller:v1.0.0")
+}
+
+// newBuildKustomizationFixture writes the test component and its kustomization to a temporary directory.
+func newBuildKustomizationFixture(tb testing.TB) (string, string, kustypes.Kustomization) {
+	dir := tb.TempDir()
+	require.NoError(tb, os.WriteFile(filepath.Join(dir, "git-controller.yaml"), testComponentData, 0o600))
+	kfile, kus, err := genKus(dir, "./git-controller.yaml")
+	require.NoError(tb, err)
+	return dir, kfile, kus
+}
+
+func TestBuildKustomizationConcurrent(t *testing.T) {
+	dir, kfile, kus := newBuildKustomizationFixture(t)
+
+	var wg sync.WaitGroup
+	results := make([][]byte, 10)
+	errs := make([]error, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			k := kus
+			k.Images = []kustypes.Image{{Name: "ghcr.io/user/git-controller", NewTag: fmt.Sprintf("v1.0.%d", i)}}
+			results[i], errs[i] = buildKustomization(context.Background(), k, kfile, dir)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range results {
+		require.NoError(t, errs[i])
+		assert.Contains(t, string(results[i]), fmt.Sprintf("image: ghcr.io/user/git-controller:v1.0.%d", i))
+	}
+
+	// the build directories are cleaned up and the resources are left untouched
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func BenchmarkBuildKustomizationSequential(b *testing.B) {
+	dir, kfile, kus := newBuildKustomizationFixture(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 2; j++ {
+			if _, err := buildKustomization(context.Background(), kus, kfile, dir); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkBuildKustomizationConcurrent(b *testing.B) {
+	dir, kfile, kus := newBuildKustomizationFixture(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var wg sync.WaitGroup
+		for j := 0; j < 2; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := buildKustomization(context.Background(), kus, kfile, dir); err != nil {
+					b.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func TestBuildKustomizationSandboxed(t *testing.T) {
+	dir, kfile, kus := newBuildKustomizationFixture(t)
+	kus.Images = []kustypes.Image{{Name: "ghcr.io/user/git-controller", NewTag: "v1.0.1"}}
+
+	expected, err := buildKustomization(context.Background(), kus, kfile, dir)
+	require.NoError(t, err)
+
+	out, err := buildKustomizationSandboxed(context.Background(), kus, map[string][]byte{"git-controller.yaml": testComponentData})
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(out))
+
+	_, err = buildKustomizationSandboxed(context.Background(), kus, map[string][]byte{"../git-controller.yaml": testComponentData})
+	assert.ErrorContains(t, err, "must be a plain file name")
+}
+
+func BenchmarkBuildKustomizationSandboxed(b *testing.B) {
+	_, _, kus := newBuildKustomizationFixture(b)
+	resources := map[string][]byte{"git-controller.yaml": testComponentData}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 2; j++ {
+			if _, err := buildKustomizationSandboxed(context.Background(), kus, resources); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,390 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	ocmmetav1 "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/meta/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+// lockFileName is the name of the lock file and the ConfigMap key it is stored under.
+// It has no manifest extension, as the lock file is stored in the Flux sync path, which
+// kustomize-controller would otherwise try to decode.
+const lockFileName = "mpas.lock"
+
+// LockEntry pins a bootstrap component to a version.
+type LockEntry struct {
+	// Name is the name of the component reference in the bootstrap component, e.g. flux.
+	Name string `json:"name"`
+	// ComponentName is the name of the referenced component.
+	ComponentName string `json:"componentName"`
+	// Version is the resolved version of the component.
+	Version string `json:"version"`
+	// Digest is the OCI digest of the component version.
+	Digest string `json:"digest,omitempty"`
+}
+
+// LockFile pins the versions of the bootstrap components.
+type LockFile struct {
+	Components []LockEntry `json:"components"`
+}
+
+// LockStore reads and writes the lock file.
+type LockStore interface {
+	// Read returns the lock file. An empty lock file is returned if none was written yet.
+	Read(ctx context.Context) (*LockFile, error)
+	// Write stores the lock file, replacing the existing one.
+	Write(ctx context.Context, lock *LockFile) error
+}
+
+// WithEnforceLock uses the component versions pinned in the lock file instead of resolving
+// the latest bootstrap component. Components missing from the lock file cause the bootstrap to fail.
+func WithEnforceLock(enforce bool) Option {
+	return func(o *options) {
+		o.enforceLock = enforce
+	}
+}
+
+// WithLockConfigMap stores the lock file in the ConfigMap with the given name and namespace
+// instead of the management repository.
+func WithLockConfigMap(name, namespace string) Option {
+	return func(o *options) {
+		o.lockConfigMapName = name
+		o.lockConfigMapNamespace = namespace
+	}
+}
+
+// lockStore returns the store of the lock file. The lock file is stored in the management repository
+// unless a ConfigMap is configured. It returns nil if there is neither.
+func (b *Bootstrap) lockStore() LockStore {
+	if b.lockConfigMapName != "" {
+		return NewConfigMapLockStore(b.kubeclient, b.lockConfigMapName, b.lockConfigMapNamespace)
+	}
+	if b.repository != nil {
+		return &gitLockStore{
+			repository:            b.repository,
+			branch:                b.defaultBranch,
+			targetPath:            b.targetPath,
+			provider:              string(b.providerClient.ProviderID()),
+			commitMessageAppendix: b.commitMessageAppendix,
+		}
+	}
+	return nil
+}
+
+// readLockedReferences returns the component references pinned in the lock file.
+// The pinned digests are compared with the digests of the component versions in ociRepo.
+func (b *Bootstrap) readLockedReferences(ctx context.Context, ociRepo om.Repository) (map[string]compdesc.ComponentReference, error) {
+	store := b.lockStore()
+	if store == nil {
+		return nil, fmt.Errorf("no lock file store is configured")
+	}
+
+	lock, err := store.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lock.Components) == 0 {
+		return nil, fmt.Errorf("lock file %s not found", lockFileName)
+	}
+
+	refs, err := lock.references(b.components)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyLockedDigests(ociRepo, refs); err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+// verifyLockedDigests fails if the digest of a pinned component version in ociRepo
+// does not match the digest in the lock file. References without a digest are not checked.
+func verifyLockedDigests(ociRepo om.Repository, refs map[string]compdesc.ComponentReference) error {
+	for _, name := range getOrderedKeys(refs) {
+		ref := refs[name]
+		if ref.Digest == nil || ref.Digest.Value == "" {
+			continue
+		}
+
+		cv, err := ociRepo.LookupComponentVersion(ref.GetComponentName(), ref.GetVersion())
+		if err != nil {
+			return fmt.Errorf("failed to get component %s:%s: %w", ref.GetComponentName(), ref.GetVersion(), err)
+		}
+		digest, err := componentVersionDigest(cv)
+		cv.Close()
+		if err != nil {
+			return err
+		}
+
+		if digest != ref.Digest.Value {
+			return fmt.Errorf("digest %s of component %s:%s does not match the digest %s pinned in the lock file",
+				digest, ref.GetComponentName(), ref.GetVersion(), ref.Digest.Value)
+		}
+	}
+
+	return nil
+}
+
+// lockComponentVersions writes the lock file after a successful bootstrap.
+// The lock file is left unchanged if the versions were read from it.
+func (b *Bootstrap) lockComponentVersions(ctx context.Context, refs map[string]compdesc.ComponentReference) error {
+	if b.enforceLock {
+		return nil
+	}
+
+	if err := b.inSpinner("Writing lock file", func() error {
+		return b.writeLockFile(ctx, refs)
+	}); err != nil {
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+
+	return nil
+}
+
+// writeLockFile pins the given component references in the lock file.
+// Nothing is written if the lock file cannot be stored.
+func (b *Bootstrap) writeLockFile(ctx context.Context, refs map[string]compdesc.ComponentReference) error {
+	store := b.lockStore()
+	if store == nil {
+		return nil
+	}
+
+	return store.Write(ctx, newLockFile(refs))
+}
+
+// newLockFile returns a lock file pinning the given component references.
+func newLockFile(refs map[string]compdesc.ComponentReference) *LockFile {
+	lock := &LockFile{}
+	for _, name := range getOrderedKeys(refs) {
+		ref := refs[name]
+		entry := LockEntry{
+			Name:          name,
+			ComponentName: ref.GetComponentName(),
+			Version:       ref.GetVersion(),
+		}
+		if ref.Digest != nil {
+			entry.Digest = ref.Digest.Value
+		}
+		lock.Components = append(lock.Components, entry)
+	}
+	return lock
+}
+
+// references returns the pinned references of the given components.
+func (l *LockFile) references(components []string) (map[string]compdesc.ComponentReference, error) {
+	entries := make(map[string]LockEntry, len(l.Components))
+	for _, entry := range l.Components {
+		entries[entry.Name] = entry
+	}
+
+	refs := make(map[string]compdesc.ComponentReference, len(components))
+	for _, component := range components {
+		entry, ok := entries[component]
+		if !ok {
+			return nil, fmt.Errorf("component %s is not pinned in the lock file", component)
+		}
+
+		ref := compdesc.ComponentReference{
+			ElementMeta: compdesc.ElementMeta{
+				Name:    entry.Name,
+				Version: entry.Version,
+			},
+			ComponentName: entry.ComponentName,
+		}
+		if entry.Digest != "" {
+			ref.Digest = &ocmmetav1.DigestSpec{Value: entry.Digest}
+		}
+		refs[component] = ref
+	}
+
+	return refs, nil
+}
+
+// entry returns the lock entry of the given component.
+func (l *LockFile) entry(component string) (*LockEntry, error) {
+	for i := range l.Components {
+		if l.Components[i].Name == component {
+			return &l.Components[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("component %s is not pinned in the lock file", component)
+}
+
+// gitLockStore stores the lock file in the target path of the management repository,
+// so that clusters bootstrapped from the same repository each have their own lock file.
+type gitLockStore struct {
+	repository            gitprovider.UserRepository
+	branch                string
+	targetPath            string
+	provider              string
+	commitMessageAppendix string
+}
+
+var _ LockStore = &gitLockStore{}
+
+// Read returns the lock file from the management repository.
+func (s *gitLockStore) Read(ctx context.Context) (*LockFile, error) {
+	dir := path.Dir(s.path())
+	if dir == "." {
+		dir = ""
+	}
+
+	files, err := s.repository.Files().Get(ctx, dir, s.branch)
+	if err != nil {
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return &LockFile{}, nil
+		}
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+
+	for _, file := range files {
+		if file.Path == nil || file.Content == nil || path.Base(*file.Path) != lockFileName {
+			continue
+		}
+
+		return unmarshalLockFile([]byte(*file.Content))
+	}
+
+	return &LockFile{}, nil
+}
+
+// Write commits the lock file to the management repository.
+// Nothing is committed if the lock file is unchanged.
+func (s *gitLockStore) Write(ctx context.Context, lock *LockFile) error {
+	data, err := lock.marshal()
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.Read(ctx)
+	if err != nil {
+		return err
+	}
+	if len(existing.Components) > 0 {
+		existingData, err := existing.marshal()
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(existingData, data) {
+			return nil
+		}
+	}
+
+	lockPath := s.path()
+	content := SetProviderDataFormat(s.provider, data)
+	commitMsg := "Update mpas lock file"
+	if s.commitMessageAppendix != "" {
+		commitMsg = commitMsg + "\n\n" + s.commitMessageAppendix
+	}
+
+	if _, err := s.repository.Commits().Create(ctx, s.branch, commitMsg, []gitprovider.CommitFile{
+		{
+			Path:    &lockPath,
+			Content: &content,
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to commit lock file: %w", err)
+	}
+
+	return nil
+}
+
+// path returns the path of the lock file in the management repository.
+func (s *gitLockStore) path() string {
+	return path.Join(s.targetPath, lockFileName)
+}
+
+// ConfigMapLockStore stores the lock file in a ConfigMap.
+type ConfigMapLockStore struct {
+	kubeclient client.Client
+	name       string
+	namespace  string
+}
+
+var _ LockStore = &ConfigMapLockStore{}
+
+// NewConfigMapLockStore returns a LockStore storing the lock file in the ConfigMap with the given name and namespace.
+func NewConfigMapLockStore(kubeclient client.Client, name, namespace string) *ConfigMapLockStore {
+	return &ConfigMapLockStore{
+		kubeclient: kubeclient,
+		name:       name,
+		namespace:  namespace,
+	}
+}
+
+// Read returns the lock file from the ConfigMap.
+func (s *ConfigMapLockStore) Read(ctx context.Context) (*LockFile, error) {
+	cm := &corev1.ConfigMap{}
+	if err := s.kubeclient.Get(ctx, client.ObjectKey{Name: s.name, Namespace: s.namespace}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &LockFile{}, nil
+		}
+		return nil, fmt.Errorf("failed to get lock ConfigMap %s/%s: %w", s.namespace, s.name, err)
+	}
+
+	data, ok := cm.Data[lockFileName]
+	if !ok {
+		return &LockFile{}, nil
+	}
+
+	return unmarshalLockFile([]byte(data))
+}
+
+// Write stores the lock file in the ConfigMap, creating it if it does not exist.
+func (s *ConfigMapLockStore) Write(ctx context.Context, lock *LockFile) error {
+	data, err := lock.marshal()
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: s.name, Namespace: s.namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, s.kubeclient, cm, func() error {
+		setManagedByLabel(cm)
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[lockFileName] = string(data)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to write lock ConfigMap %s/%s: %w", s.namespace, s.name, err)
+	}
+
+	return nil
+}
+
+func (l *LockFile) marshal() ([]byte, error) {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal lock file: %w", err)
+	}
+	return data, nil
+}
+
+func unmarshalLockFile(data []byte) (*LockFile, error) {
+	lock := &LockFile{}
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal lock file: %w", err)
+	}
+	return lock, nil
+}
@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/open-component-model/ocm-controller/pkg/fakes"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	ocmmetav1 "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/meta/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigMapLockStore(t *testing.T) {
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+	kubeclient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	b := &Bootstrap{options: options{kubeclient: kubeclient}}
+	assert.Nil(t, b.lockStore())
+	WithLockConfigMap("mpas-lock", "mpas-system")(&b.options)
+	store := b.lockStore()
+	require.NotNil(t, store)
+
+	// nothing has been written yet
+	lock, err := store.Read(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, lock.Components)
+
+	want := &LockFile{Components: []LockEntry{
+		{Name: "flux", ComponentName: "ocm.software/mpas/flux", Version: "v2.1.0", Digest: "abc"},
+		{Name: "ocm-controller", ComponentName: "ocm.software/mpas/ocm-controller", Version: "v0.14.0", Digest: "def"},
+	}}
+	require.NoError(t, store.Write(context.Background(), want))
+
+	got, err := store.Read(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	// the lock file is replaced on subsequent writes
+	want.Components = want.Components[:1]
+	require.NoError(t, store.Write(context.Background(), want))
+	got, err = store.Read(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, kubeclient.Get(context.Background(), client.ObjectKey{Name: "mpas-lock", Namespace: "mpas-system"}, cm))
+	assert.Equal(t, managedByValue, cm.Labels[managedByLabel])
+	assert.Contains(t, cm.Data[lockFileName], "version: v2.1.0")
+}
+
+func TestGitLockStore(t *testing.T) {
+	commitClient := &mockCommitClient{commit: &mockCommit{sha: "sha"}}
+	b := &Bootstrap{
+		providerClient: &mockProviderClient{providerID: env.ProviderGitea},
+		repository:     &mockGitRepository{commitClient: commitClient, fileClient: &mockFileClient{}},
+		options:        options{defaultBranch: "main", targetPath: "clusters/eu-west"},
+	}
+
+	refs := map[string]compdesc.ComponentReference{
+		"ocm-controller": {
+			ElementMeta:   compdesc.ElementMeta{Name: "ocm-controller", Version: "v0.14.0"},
+			ComponentName: "ocm.software/mpas/ocm-controller",
+			Digest:        &ocmmetav1.DigestSpec{HashAlgorithm: "SHA-256", Value: "def"},
+		},
+		"flux": {
+			ElementMeta:   compdesc.ElementMeta{Name: "flux", Version: "v2.1.0"},
+			ComponentName: "ocm.software/mpas/flux",
+		},
+	}
+	require.NoError(t, b.writeLockFile(context.Background(), refs))
+
+	// the lock file is committed to the target path of the management repository
+	require.Len(t, commitClient.calledWidth, 1)
+	args := commitClient.calledWidth[0]
+	assert.Equal(t, "main", args[0])
+	assert.Equal(t, "Update mpas lock file", args[1])
+	files := args[2].([]gitprovider.CommitFile)
+	require.Len(t, files, 1)
+	assert.Equal(t, "clusters/eu-west/mpas.lock", *files[0].Path)
+	content, err := base64.StdEncoding.DecodeString(*files[0].Content)
+	require.NoError(t, err)
+	assert.Equal(t, `components:
+- componentName: ocm.software/mpas/flux
+  name: flux
+  version: v2.1.0
+- componentName: ocm.software/mpas/ocm-controller
+  digest: def
+  name: ocm-controller
+  version: v0.14.0
+`, string(content))
+
+	ociRepo := &mockRepository{
+		cv: []*mockComponentAccess{
+			{
+				name: "ocm.software/mpas/ocm-controller",
+				cva: map[string]*fakes.Component{
+					"v0.14.0": {Name: "ocm.software/mpas/ocm-controller", Version: "v0.14.0"},
+				},
+			},
+		},
+	}
+	digest := "def"
+	componentVersionDigest = func(cv om.ComponentVersionAccess) (string, error) {
+		return digest, nil
+	}
+	t.Cleanup(func() { componentVersionDigest = defaultComponentVersionDigest })
+
+	// without a lock file enforcing fails
+	WithComponents([]string{"flux", "ocm-controller"})(&b.options)
+	_, err = b.readLockedReferences(context.Background(), ociRepo)
+	require.ErrorContains(t, err, "lock file mpas.lock not found")
+
+	// re-runs use the pinned versions
+	path, data := "clusters/eu-west/mpas.lock", string(content)
+	b.repository = &mockGitRepository{
+		commitClient: commitClient,
+		fileClient:   &mockFileClient{files: []*gitprovider.CommitFile{{Path: &path, Content: &data}}},
+	}
+	pinned, err := b.readLockedReferences(context.Background(), ociRepo)
+	require.NoError(t, err)
+	assert.Equal(t, "v2.1.0", pinned["flux"].GetVersion())
+	assert.Equal(t, "ocm.software/mpas/flux", pinned["flux"].GetComponentName())
+	assert.Equal(t, "v0.14.0", pinned["ocm-controller"].GetVersion())
+	assert.Equal(t, "ocm.software/mpas/ocm-controller", pinned["ocm-controller"].GetComponentName())
+	assert.Equal(t, "def", pinned["ocm-controller"].Digest.Value)
+
+	// an unchanged lock file is not committed again
+	require.NoError(t, b.writeLockFile(context.Background(), refs))
+	assert.Len(t, commitClient.calledWidth, 1)
+
+	// a component version that changed since it was pinned causes an error
+	digest = "changed"
+	_, err = b.readLockedReferences(context.Background(), ociRepo)
+	require.ErrorContains(t, err, "does not match the digest def pinned in the lock file")
+
+	// components missing from the lock file cause an error
+	WithComponents([]string{"flux", "ocm-controller", "git-controller"})(&b.options)
+	_, err = b.readLockedReferences(context.Background(), ociRepo)
+	require.ErrorContains(t, err, "component git-controller is not pinned in the lock file")
+}
+
+func TestGitLockStoreCommitError(t *testing.T) {
+	errCommit := errors.New("commit failed")
+	b := &Bootstrap{
+		providerClient: &mockProviderClient{providerID: env.ProviderGithub},
+		repository:     &mockGitRepository{commitClient: &mockCommitClient{err: errCommit}, fileClient: &mockFileClient{}},
+	}
+	require.ErrorIs(t, b.writeLockFile(context.Background(), nil), errCommit)
+
+	// with enforcement the lock file is left unchanged
+	WithEnforceLock(true)(&b.options)
+	require.NoError(t, b.lockComponentVersions(context.Background(), nil))
+}
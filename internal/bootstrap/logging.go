@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	fluxlog "github.com/fluxcd/flux2/v2/pkg/log"
+	"github.com/open-component-model/mpas/internal/printer"
+)
+
+// WithLogger sets the handler used to log the bootstrap operations, e.g. slog.NewJSONHandler
+// for machine-parseable output. By default messages are printed human-readable with the printer.
+func WithLogger(handler slog.Handler) Option {
+	return func(o *options) {
+		o.logHandler = handler
+	}
+}
+
+// log returns the logger of the bootstrap.
+func (b *Bootstrap) log() *slog.Logger {
+	if b.logHandler != nil {
+		return slog.New(b.logHandler)
+	}
+
+	return slog.New(&printerHandler{printer: b.printer})
+}
+
+// logCompleted logs the final message of an operation, separated by a blank line in human-readable output.
+func (b *Bootstrap) logCompleted(ctx context.Context, msg string, args ...any) {
+	if b.logHandler == nil {
+		b.printer.Printf("\n")
+	}
+	b.log().InfoContext(ctx, msg, args...)
+}
+
+// logEvent logs the progress event with its phase and component as attributes.
+// Progress is only logged with a configured logger, the printer shows progress with spinners instead.
+func (b *Bootstrap) logEvent(event ProgressEvent) {
+	if b.logHandler == nil {
+		return
+	}
+
+	args := []any{slog.String("phase", event.Phase)}
+	if event.Component != "" {
+		args = append(args, slog.String("component", event.Component))
+	}
+
+	if event.Err != nil {
+		b.log().Error(event.Message, append(args, slog.Any("error", event.Err))...)
+		return
+	}
+	b.log().Info(event.Message, args...)
+}
+
+// printerHandler is a slog.Handler printing the messages human-readable with the printer.
+// Attributes are not printed, instead the string attribute values contained in the message are highlighted.
+type printerHandler struct {
+	printer *printer.Printer
+}
+
+var _ slog.Handler = &printerHandler{}
+
+func (h *printerHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo
+}
+
+func (h *printerHandler) Handle(_ context.Context, r slog.Record) error {
+	if h.printer == nil {
+		return nil
+	}
+
+	msg := r.Message
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Value.Kind() == slog.KindString && a.Value.String() != "" {
+			msg = strings.Replace(msg, a.Value.String(), printer.BoldBlue(a.Value.String()), 1)
+		}
+		return true
+	})
+
+	switch {
+	case r.Level >= slog.LevelError:
+		h.printer.Printf("%s %s\n", printer.BoldRed("error:"), msg)
+	case r.Level >= slog.LevelWarn:
+		h.printer.Printf("%s %s\n", printer.BoldRed("warning:"), msg)
+	default:
+		h.printer.Printf("%s\n", msg)
+	}
+	return nil
+}
+
+func (h *printerHandler) WithAttrs(_ []slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *printerHandler) WithGroup(_ string) slog.Handler {
+	return h
+}
+
+// fluxLogger bridges the Flux bootstrap logger to slog.
+type fluxLogger struct {
+	logger *slog.Logger
+}
+
+var _ fluxlog.Logger = &fluxLogger{}
+
+func newFluxLogger(logger *slog.Logger) *fluxLogger {
+	return &fluxLogger{logger: logger.With(slog.String("component", "flux"))}
+}
+
+func (l *fluxLogger) Actionf(format string, a ...interface{}) {
+	l.logger.Info(fmt.Sprintf(format, a...))
+}
+
+func (l *fluxLogger) Generatef(format string, a ...interface{}) {
+	l.logger.Info(fmt.Sprintf(format, a...))
+}
+
+func (l *fluxLogger) Waitingf(format string, a ...interface{}) {
+	l.logger.Info(fmt.Sprintf(format, a...))
+}
+
+func (l *fluxLogger) Successf(format string, a ...interface{}) {
+	l.logger.Info(fmt.Sprintf(format, a...))
+}
+
+func (l *fluxLogger) Warningf(format string, a ...interface{}) {
+	l.logger.Warn(fmt.Sprintf(format, a...))
+}
+
+func (l *fluxLogger) Failuref(format string, a ...interface{}) {
+	l.logger.Error(fmt.Sprintf(format, a...))
+}
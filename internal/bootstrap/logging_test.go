@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLogging(t *testing.T) {
+	var buf bytes.Buffer
+	b := &Bootstrap{}
+	WithLogger(slog.NewJSONHandler(&buf, nil))(&b.options)
+
+	errHealth := errors.New("not ready")
+	require.NoError(t, b.trackProgress(ProgressPhaseComponentInstall, "ocm-controller", func() error { return nil })())
+	require.ErrorIs(t, b.trackProgress(ProgressPhaseHealthCheck, "ocm-system", func() error { return errHealth })(), errHealth)
+	newFluxLogger(b.log()).Successf("installed %s", "source-controller")
+	b.logCompleted(context.Background(), "Bootstrap completed successfully!")
+
+	var records []map[string]any
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var record map[string]any
+		require.NoError(t, dec.Decode(&record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 6)
+
+	assert.Equal(t, "started", records[0]["msg"])
+	assert.Equal(t, ProgressPhaseComponentInstall, records[0]["phase"])
+	assert.Equal(t, "ocm-controller", records[0]["component"])
+	assert.Equal(t, "INFO", records[1]["level"])
+	assert.Equal(t, "finished", records[1]["msg"])
+
+	assert.Equal(t, "ERROR", records[3]["level"])
+	assert.Equal(t, "failed", records[3]["msg"])
+	assert.Equal(t, ProgressPhaseHealthCheck, records[3]["phase"])
+	assert.Equal(t, "not ready", records[3]["error"])
+
+	assert.Equal(t, "installed source-controller", records[4]["msg"])
+	assert.Equal(t, "flux", records[4]["component"])
+
+	assert.Equal(t, "Bootstrap completed successfully!", records[5]["msg"])
+	for _, record := range records {
+		assert.Contains(t, record, "time")
+	}
+}
+
+func TestPrinterHandlerHighlightsAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	p, err := printer.Newprinter(&buf)
+	require.NoError(t, err)
+
+	b := &Bootstrap{options: options{printer: p}}
+	b.log().Info("Skipping flux with version v2.0.0", slog.String("component", "flux"), slog.String("version", "v2.0.0"))
+	assert.Equal(t, fmt.Sprintf("Skipping %s with version %s\n", printer.BoldBlue("flux"), printer.BoldBlue("v2.0.0")), buf.String())
+
+	// structured output carries the identifiers as attributes, not as colour codes in the message
+	buf.Reset()
+	WithLogger(slog.NewJSONHandler(&buf, nil))(&b.options)
+	b.log().Info("Skipping flux with version v2.0.0", slog.String("component", "flux"), slog.String("version", "v2.0.0"))
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "Skipping flux with version v2.0.0", record["msg"])
+	assert.Equal(t, "flux", record["component"])
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "mpas"
+	metricsSubsystem = "bootstrap"
+
+	// bootstrapStatusSuccess and bootstrapStatusFailure are the values of the status label.
+	bootstrapStatusSuccess = "success"
+	bootstrapStatusFailure = "failure"
+
+	// bootstrapMetricsComponent is the component label of the whole bootstrap run.
+	bootstrapMetricsComponent = "mpas"
+
+	stepClone     = "clone"
+	stepReconcile = "reconcile"
+)
+
+// Metrics records Prometheus metrics of the bootstrap operations.
+// All methods are no-ops on a nil Metrics.
+type Metrics struct {
+	registerer   prometheus.Registerer
+	runs         *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	stepDuration *prometheus.HistogramVec
+}
+
+// WithMetrics registers the bootstrap metrics with the given registerer.
+func WithMetrics(registerer prometheus.Registerer) Option {
+	return func(o *options) {
+		o.metricsRegisterer = registerer
+	}
+}
+
+// NewMetrics creates the bootstrap metrics and registers them with the registerer.
+func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		registerer: registerer,
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "total",
+			Help:      "Total number of bootstrap runs by status.",
+		}, []string{"status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "duration_seconds",
+			Help:      "Duration of the bootstrap run and of each component install in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+		}, []string{"component"}),
+		stepDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "step_duration_seconds",
+			Help:      "Duration of cloning the management repository and reconciling component manifests in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+		}, []string{"component", "step"}),
+	}
+
+	var err error
+	if m.runs, err = register(registerer, m.runs); err != nil {
+		return nil, err
+	}
+	if m.duration, err = register(registerer, m.duration); err != nil {
+		return nil, err
+	}
+	if m.stepDuration, err = register(registerer, m.stepDuration); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// register registers the collector with the registerer. If an equal collector is already registered,
+// as it is when several bootstraps share a registerer, the registered collector is returned instead.
+func register[T prometheus.Collector](registerer prometheus.Registerer, c T) (T, error) {
+	if err := registerer.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing, nil
+			}
+		}
+		return c, fmt.Errorf("failed to register bootstrap metrics: %w", err)
+	}
+
+	return c, nil
+}
+
+// observeRun records the outcome and duration of a bootstrap run started at start.
+func (m *Metrics) observeRun(start time.Time, err error) {
+	if m == nil {
+		return
+	}
+
+	status := bootstrapStatusSuccess
+	if err != nil {
+		status = bootstrapStatusFailure
+	}
+	m.runs.WithLabelValues(status).Inc()
+	m.duration.WithLabelValues(bootstrapMetricsComponent).Observe(time.Since(start).Seconds())
+}
+
+// observeComponent records the install duration of a component started at start.
+func (m *Metrics) observeComponent(component string, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.duration.WithLabelValues(component).Observe(time.Since(start).Seconds())
+}
+
+// observeStep records the duration of a step of a component install started at start.
+func (m *Metrics) observeStep(component, step string, start time.Time) {
+	if m == nil {
+		return
+	}
+	m.stepDuration.WithLabelValues(component, step).Observe(time.Since(start).Seconds())
+}
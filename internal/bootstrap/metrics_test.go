@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := NewMetrics(registry)
+	require.NoError(t, err)
+
+	// a second bootstrap sharing the registry reuses the registered collectors
+	shared, err := NewMetrics(registry)
+	require.NoError(t, err)
+	assert.Same(t, metrics.runs, shared.runs)
+	assert.Same(t, metrics.duration, shared.duration)
+	assert.Same(t, metrics.stepDuration, shared.stepDuration)
+
+	newInstall := func(commitErr error) *componentInstall {
+		return &componentInstall{
+			componentName: "ocm.software/mpas/test-component",
+			version:       "v1.0.1",
+			componentOptions: &componentOptions{
+				gitRepository: &mockGitRepository{
+					commitClient: &mockCommitClient{commit: &mockCommit{sha: "sha"}, err: commitErr},
+				},
+				dir:        t.TempDir(),
+				branch:     "main",
+				targetPath: "target",
+				namespace:  "ocm-system",
+				provider:   "gitea",
+				metrics:    metrics,
+			},
+			kustomizer: &mockKustomizer{out: kustomizedDeployment},
+		}
+	}
+
+	// successful run
+	start := time.Now()
+	_, err = newInstall(nil).install(context.Background(), "test-component")
+	require.NoError(t, err)
+	metrics.observeComponent("test-component", start)
+	metrics.observeRun(start, nil)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.runs.WithLabelValues(bootstrapStatusSuccess)))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.runs.WithLabelValues(bootstrapStatusFailure)))
+
+	// failed run
+	errCommit := errors.New("commit failed")
+	_, err = newInstall(errCommit).install(context.Background(), "test-component")
+	require.ErrorIs(t, err, errCommit)
+	metrics.observeRun(start, err)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.runs.WithLabelValues(bootstrapStatusSuccess)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.runs.WithLabelValues(bootstrapStatusFailure)))
+
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.duration, "mpas_bootstrap_duration_seconds"))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.stepDuration, "mpas_bootstrap_step_duration_seconds"))
+	count, err := testutil.GatherAndCount(registry, "mpas_bootstrap_total")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// a nil Metrics records nothing
+	var nilMetrics *Metrics
+	nilMetrics.observeRun(start, nil)
+	nilMetrics.observeComponent("test-component", start)
+	nilMetrics.observeStep("test-component", stepClone, start)
+}
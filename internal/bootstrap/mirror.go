@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"strings"
+
+	kustypes "sigs.k8s.io/kustomize/api/types"
+)
+
+// WithImageMirror rewrites images starting with originalPrefix to start with mirrorPrefix instead,
+// e.g. ghcr.io/fluxcd to my-mirror.corp/fluxcd. It can be set multiple times to configure several mirrors.
+func WithImageMirror(originalPrefix, mirrorPrefix string) Option {
+	return func(o *options) {
+		if o.imageMirrors == nil {
+			o.imageMirrors = make(map[string]string)
+		}
+		o.imageMirrors[strings.TrimSuffix(originalPrefix, "/")] = strings.TrimSuffix(mirrorPrefix, "/")
+	}
+}
+
+// withImageMirrors sets the image mirrors used for the flux images.
+func withImageMirrors(mirrors map[string]string) fluxOption {
+	return func(o *fluxOptions) {
+		o.imageMirrors = mirrors
+	}
+}
+
+// mirrorImages rewrites the NewName of the given images using the mirror with the longest matching prefix.
+// A prefix only matches whole path segments, so ghcr.io/flux does not match ghcr.io/fluxcd/source-controller.
+// The order of the images is preserved.
+func mirrorImages(images []kustypes.Image, mirrors map[string]string) []kustypes.Image {
+	if len(mirrors) == 0 {
+		return images
+	}
+
+	result := make([]kustypes.Image, 0, len(images))
+	for _, image := range images {
+		var prefix string
+		for original := range mirrors {
+			if len(original) > len(prefix) && (image.NewName == original || strings.HasPrefix(image.NewName, original+"/")) {
+				prefix = original
+			}
+		}
+
+		if prefix != "" {
+			image.NewName = mirrors[prefix] + strings.TrimPrefix(image.NewName, prefix)
+		}
+		result = append(result, image)
+	}
+
+	return result
+}
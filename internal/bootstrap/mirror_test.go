@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+)
+
+func TestMirrorImages(t *testing.T) {
+	images := []kustypes.Image{
+		{Name: "ghcr.io/fluxcd/source-controller", NewName: "ghcr.io/fluxcd/source-controller", NewTag: "v1.0.0"},
+		{Name: "ghcr.io/fluxcdx/other", NewName: "ghcr.io/fluxcdx/other", NewTag: "v1.0.0"},
+		{Name: "registry.k8s.io/pause", NewName: "registry.k8s.io/pause", NewTag: "3.9"},
+		{Name: "docker.io/library/busybox", NewName: "docker.io/library/busybox", NewTag: "1.36"},
+		{Name: "ghcr.io/fluxcd/helm/helm-controller", NewName: "ghcr.io/fluxcd/helm/helm-controller", NewTag: "v0.1.0"},
+	}
+	mirrors := map[string]string{
+		"ghcr.io/fluxcd":      "my-mirror.corp/fluxcd",
+		"ghcr.io/fluxcd/helm": "helm-mirror.corp",
+		"registry.k8s.io":     "my-mirror.corp/k8s",
+	}
+
+	got := mirrorImages(images, mirrors)
+
+	assert.Equal(t, []string{
+		"my-mirror.corp/fluxcd/source-controller",
+		"ghcr.io/fluxcdx/other",
+		"my-mirror.corp/k8s/pause",
+		"docker.io/library/busybox",
+		"helm-mirror.corp/helm-controller",
+	}, newNames(got))
+	for i := range images {
+		assert.Equal(t, images[i].Name, got[i].Name)
+		assert.Equal(t, images[i].NewTag, got[i].NewTag)
+	}
+
+	assert.Equal(t, images, mirrorImages(images, nil))
+}
+
+func TestWithImageMirror(t *testing.T) {
+	o := &options{}
+	WithImageMirror("ghcr.io/fluxcd/", "my-mirror.corp/fluxcd/")(o)
+	WithImageMirror("registry.k8s.io", "my-mirror.corp/k8s")(o)
+	assert.Equal(t, map[string]string{
+		"ghcr.io/fluxcd":  "my-mirror.corp/fluxcd",
+		"registry.k8s.io": "my-mirror.corp/k8s",
+	}, o.imageMirrors)
+}
+
+func newNames(images []kustypes.Image) []string {
+	names := make([]string, 0, len(images))
+	for _, image := range images {
+		names = append(names, image.NewName)
+	}
+	return names
+}
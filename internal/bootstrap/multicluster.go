@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// WithClusterName sets the name identifying the cluster in a MultiClusterBootstrap.
+func WithClusterName(name string) Option {
+	return func(o *options) {
+		o.clusterName = name
+	}
+}
+
+// MultiClusterOption is a function that sets an option on the multi-cluster bootstrap.
+type MultiClusterOption func(*MultiClusterBootstrap)
+
+// WithClusterConcurrency limits the number of clusters bootstrapped at the same time.
+// All clusters are bootstrapped at the same time by default.
+func WithClusterConcurrency(n int) MultiClusterOption {
+	return func(m *MultiClusterBootstrap) {
+		m.concurrency = n
+	}
+}
+
+// MultiClusterBootstrap bootstraps several clusters from the same management repository
+// and registry. Each Bootstrap is configured with the kubeclient and RESTClientGetter of its
+// cluster, a cluster name and a cluster-specific target path in the management repository.
+//
+// Commits of concurrently bootstrapped clusters may conflict, use WithClusterConcurrency(1)
+// to bootstrap the clusters one after another.
+type MultiClusterBootstrap struct {
+	bootstraps  []*Bootstrap
+	concurrency int
+	// run bootstraps a single cluster, it is replaced in tests
+	run func(ctx context.Context, b *Bootstrap) error
+}
+
+// NewMultiClusterBootstrap returns a MultiClusterBootstrap for the given bootstraps.
+// The cluster names and target paths of the bootstraps must be unique.
+func NewMultiClusterBootstrap(bootstraps []*Bootstrap, opts ...MultiClusterOption) (*MultiClusterBootstrap, error) {
+	m := &MultiClusterBootstrap{
+		bootstraps: bootstraps,
+		run:        func(ctx context.Context, b *Bootstrap) error { return b.Run(ctx) },
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.concurrency < 0 {
+		return nil, fmt.Errorf("cluster concurrency must not be negative, got %d", m.concurrency)
+	}
+
+	names := make(map[string]bool, len(bootstraps))
+	targetPaths := make(map[string]string, len(bootstraps))
+	for _, b := range bootstraps {
+		if b.clusterName == "" {
+			return nil, fmt.Errorf("cluster name must be set")
+		}
+		if names[b.clusterName] {
+			return nil, fmt.Errorf("cluster name %s is not unique", b.clusterName)
+		}
+		names[b.clusterName] = true
+
+		if other, ok := targetPaths[b.targetPath]; ok {
+			return nil, fmt.Errorf("clusters %s and %s have the same target path %q", other, b.clusterName, b.targetPath)
+		}
+		targetPaths[b.targetPath] = b.clusterName
+	}
+
+	return m, nil
+}
+
+// Run bootstraps all clusters and returns the result of each cluster keyed by its name.
+// The result is nil if the cluster was bootstrapped successfully. A failing cluster does not
+// abort the bootstrap of the others.
+func (m *MultiClusterBootstrap) Run(ctx context.Context) map[string]error {
+	concurrency := m.concurrency
+	if concurrency == 0 {
+		concurrency = len(m.bootstraps)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		sem     = make(chan struct{}, concurrency)
+		results = make(map[string]error, len(m.bootstraps))
+	)
+	for _, b := range m.bootstraps {
+		wg.Add(1)
+		go func(b *Bootstrap) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			err := m.run(ctx, b)
+			if err != nil {
+				err = fmt.Errorf("failed to bootstrap cluster %s: %w", b.clusterName, err)
+			}
+
+			mu.Lock()
+			results[b.clusterName] = err
+			mu.Unlock()
+		}(b)
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newClusterBootstrap(name, targetPath string) *Bootstrap {
+	b := &Bootstrap{}
+	for _, opt := range []Option{
+		WithClusterName(name),
+		WithTarget(targetPath),
+		WithKubeClient(fake.NewClientBuilder().Build()),
+	} {
+		opt(&b.options)
+	}
+	return b
+}
+
+func TestMultiClusterBootstrapRun(t *testing.T) {
+	clusters := []*Bootstrap{
+		newClusterBootstrap("eu-west", "clusters/eu-west"),
+		newClusterBootstrap("us-east", "clusters/us-east"),
+	}
+
+	m, err := NewMultiClusterBootstrap(clusters)
+	require.NoError(t, err)
+
+	// apply the same manifests to each cluster, as a bootstrap from the same registry would
+	m.run = func(ctx context.Context, b *Bootstrap) error {
+		objects, err := kubeutils.YamlToUnstructructured(testComponentData)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objects {
+			if err := b.kubeclient.Create(ctx, obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	results := m.Run(context.Background())
+	assert.Equal(t, map[string]error{"eu-west": nil, "us-east": nil}, results)
+
+	var images []string
+	for _, b := range clusters {
+		deployment := &appsv1.Deployment{}
+		require.NoError(t, b.kubeclient.Get(context.Background(), client.ObjectKey{Name: "git-controller", Namespace: "ocm-system"}, deployment))
+		images = append(images, deployment.Spec.Template.Spec.Containers[0].Image)
+	}
+	assert.Equal(t, []string{"ghcr.io/user/git-controller:v1.0.0", "ghcr.io/user/git-controller:v1.0.0"}, images)
+}
+
+func TestMultiClusterBootstrapFailures(t *testing.T) {
+	m, err := NewMultiClusterBootstrap([]*Bootstrap{
+		newClusterBootstrap("eu-west", "clusters/eu-west"),
+		newClusterBootstrap("us-east", "clusters/us-east"),
+		newClusterBootstrap("ap-south", "clusters/ap-south"),
+	}, WithClusterConcurrency(1))
+	require.NoError(t, err)
+
+	var running, maxRunning int32
+	m.run = func(_ context.Context, b *Bootstrap) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		if n > atomic.LoadInt32(&maxRunning) {
+			atomic.StoreInt32(&maxRunning, n)
+		}
+
+		if b.clusterName == "us-east" {
+			return errors.New("cluster unreachable")
+		}
+		return nil
+	}
+
+	results := m.Run(context.Background())
+	require.Len(t, results, 3)
+	assert.NoError(t, results["eu-west"])
+	assert.NoError(t, results["ap-south"])
+	assert.EqualError(t, results["us-east"], "failed to bootstrap cluster us-east: cluster unreachable")
+	assert.Equal(t, int32(1), maxRunning)
+}
+
+func TestNewMultiClusterBootstrapValidation(t *testing.T) {
+	_, err := NewMultiClusterBootstrap([]*Bootstrap{newClusterBootstrap("", "clusters/a")})
+	assert.EqualError(t, err, "cluster name must be set")
+
+	_, err = NewMultiClusterBootstrap([]*Bootstrap{
+		newClusterBootstrap("a", "clusters/a"),
+		newClusterBootstrap("a", "clusters/b"),
+	})
+	assert.EqualError(t, err, "cluster name a is not unique")
+
+	_, err = NewMultiClusterBootstrap([]*Bootstrap{
+		newClusterBootstrap("a", "clusters"),
+		newClusterBootstrap("b", "clusters"),
+	})
+	assert.EqualError(t, err, `clusters a and b have the same target path "clusters"`)
+
+	_, err = NewMultiClusterBootstrap(nil, WithClusterConcurrency(-1))
+	assert.EqualError(t, err, "cluster concurrency must not be negative, got -1")
+}
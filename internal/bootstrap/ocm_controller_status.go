@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/open-component-model/mpas/internal/env"
+	ocmv1alpha1 "github.com/open-component-model/ocm-controller/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OCMControllerStatus is the status of the ocm-controller installed in the cluster.
+type OCMControllerStatus struct {
+	// Replicas is the number of desired replicas of the ocm-controller deployment.
+	Replicas int32
+	// AvailableReplicas is the number of available replicas of the ocm-controller deployment.
+	AvailableReplicas int32
+	// NotReadyComponentVersions are the ComponentVersion objects whose Ready condition is False.
+	NotReadyComponentVersions []ComponentVersionStatus
+}
+
+// ComponentVersionStatus is the status of a ComponentVersion object.
+type ComponentVersionStatus struct {
+	// Name is the name of the ComponentVersion object.
+	Name string
+	// Namespace is the namespace of the ComponentVersion object.
+	Namespace string
+	// Reason is the reason of the Ready condition.
+	Reason string
+	// Message is the message of the Ready condition.
+	Message string
+}
+
+// GetOCMControllerStatus returns the status of the ocm-controller deployment and
+// the ComponentVersion objects that are not ready.
+func (b *Bootstrap) GetOCMControllerStatus(ctx context.Context) (*OCMControllerStatus, error) {
+	deployment := &appsv1.Deployment{}
+	key := client.ObjectKey{Name: env.OcmControllerName, Namespace: b.componentNamespace(env.OcmControllerName, env.DefaultOCMNamespace)}
+	if err := b.kubeclient.Get(ctx, key, deployment); err != nil {
+		return nil, fmt.Errorf("failed to get %s deployment: %w", env.OcmControllerName, err)
+	}
+
+	status := &OCMControllerStatus{
+		AvailableReplicas: deployment.Status.AvailableReplicas,
+	}
+	if deployment.Spec.Replicas != nil {
+		status.Replicas = *deployment.Spec.Replicas
+	}
+
+	var cvs ocmv1alpha1.ComponentVersionList
+	if err := b.kubeclient.List(ctx, &cvs); err != nil {
+		return nil, fmt.Errorf("failed to list component versions: %w", err)
+	}
+
+	for _, cv := range cvs.Items {
+		cond := apimeta.FindStatusCondition(cv.Status.Conditions, meta.ReadyCondition)
+		if cond == nil || cond.Status != metav1.ConditionFalse {
+			continue
+		}
+
+		status.NotReadyComponentVersions = append(status.NotReadyComponentVersions, ComponentVersionStatus{
+			Name:      cv.Name,
+			Namespace: cv.Namespace,
+			Reason:    cond.Reason,
+			Message:   cond.Message,
+		})
+	}
+
+	return status, nil
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	ocmv1alpha1 "github.com/open-component-model/ocm-controller/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetOCMControllerStatus(t *testing.T) {
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+
+	componentVersion := func(name string, status metav1.ConditionStatus) *ocmv1alpha1.ComponentVersion {
+		return &ocmv1alpha1.ComponentVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "mpas-system"},
+			Status: ocmv1alpha1.ComponentVersionStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:    "Ready",
+						Status:  status,
+						Reason:  "CheckVersionFailed",
+						Message: "failed to check version",
+					},
+				},
+			},
+		}
+	}
+
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "ocm-controller", Namespace: "ocm-system"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: 1},
+		},
+		componentVersion("ready", metav1.ConditionTrue),
+		componentVersion("not-ready", metav1.ConditionFalse),
+		&ocmv1alpha1.ComponentVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: "unknown", Namespace: "mpas-system"},
+		},
+	).Build()
+
+	b := &Bootstrap{options: options{kubeclient: kubeClient}}
+	status, err := b.GetOCMControllerStatus(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, &OCMControllerStatus{
+		Replicas:          2,
+		AvailableReplicas: 1,
+		NotReadyComponentVersions: []ComponentVersionStatus{
+			{
+				Name:      "not-ready",
+				Namespace: "mpas-system",
+				Reason:    "CheckVersionFailed",
+				Message:   "failed to check version",
+			},
+		},
+	}, status)
+}
+
+func TestGetOCMControllerStatusNotInstalled(t *testing.T) {
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+
+	b := &Bootstrap{options: options{kubeclient: fake.NewClientBuilder().WithScheme(scheme).Build()}}
+	_, err = b.GetOCMControllerStatus(context.Background())
+	require.ErrorContains(t, err, "failed to get ocm-controller deployment")
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"fmt"
+
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+)
+
+const (
+	// OIDCProviderAWS uses EKS IAM roles for service accounts.
+	OIDCProviderAWS = "aws"
+	// OIDCProviderGCP uses GKE workload identity.
+	OIDCProviderGCP = "gcp"
+	// OIDCProviderAzure uses Azure AD workload identity.
+	OIDCProviderAzure = "azure"
+)
+
+// WithFluxOIDCProvider configures the source-controller to pull from OCI registries with the workload
+// identity of the given cloud provider. The identity is the AWS IAM role ARN, the GCP service account
+// email or the Azure client ID the source-controller service account is bound to.
+func WithFluxOIDCProvider(provider, identity string) Option {
+	return func(o *options) {
+		o.fluxOIDCProvider = provider
+		o.fluxOIDCIdentity = identity
+	}
+}
+
+// withFluxOIDCProvider binds the source-controller service account to the workload identity of the provider.
+func withFluxOIDCProvider(provider, identity string) fluxOption {
+	return func(o *fluxOptions) {
+		o.oidcProvider = provider
+		o.oidcIdentity = identity
+	}
+}
+
+// oidcServiceAccountAnnotation returns the service account annotation binding it to the workload identity.
+func oidcServiceAccountAnnotation(provider string) (string, error) {
+	switch provider {
+	case OIDCProviderAWS:
+		return "eks.amazonaws.com/role-arn", nil
+	case OIDCProviderGCP:
+		return "iam.gke.io/gcp-service-account", nil
+	case OIDCProviderAzure:
+		return "azure.workload.identity/client-id", nil
+	default:
+		return "", fmt.Errorf("unsupported OIDC provider %q, must be one of %s, %s or %s", provider, OIDCProviderAWS, OIDCProviderGCP, OIDCProviderAzure)
+	}
+}
+
+// oidcPatches returns the patches annotating the source-controller service account with the workload
+// identity and referencing it from the source-controller Deployment. It returns nil if no provider is set.
+func (f *fluxInstall) oidcPatches() ([]kustypes.Patch, error) {
+	if f.oidcProvider == "" {
+		return nil, nil
+	}
+
+	annotation, err := oidcServiceAccountAnnotation(f.oidcProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	// azure workload identity only injects the token into pods with this label
+	var podLabels string
+	if f.oidcProvider == OIDCProviderAzure {
+		podLabels = `
+    metadata:
+      labels:
+        azure.workload.identity/use: "true"`
+	}
+
+	return []kustypes.Patch{
+		{
+			Patch: fmt.Sprintf(`apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: %s
+  annotations:
+    %s: %q
+`, sourceControllerName, annotation, f.oidcIdentity),
+			Target: &kustypes.Selector{
+				ResId: resid.ResId{
+					Gvk:  resid.Gvk{Kind: "ServiceAccount"},
+					Name: sourceControllerName,
+				},
+			},
+		},
+		{
+			Patch: fmt.Sprintf(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: %s
+spec:
+  template:%s
+    spec:
+      serviceAccountName: %s
+`, sourceControllerName, podLabels, sourceControllerName),
+			Target: &kustypes.Selector{
+				ResId: resid.ResId{
+					Gvk:  resid.Gvk{Group: "apps", Kind: "Deployment"},
+					Name: sourceControllerName,
+				},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	cfd "github.com/open-component-model/ocm-controller/pkg/configdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var sourceControllerManifests = []byte(`apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: source-controller
+  namespace: flux-system
+---
+`)
+
+func TestFluxOIDCProvider(t *testing.T) {
+	testCases := []struct {
+		provider    string
+		annotation  string
+		identity    string
+		podLabels   map[string]string
+		expectedErr string
+	}{
+		{
+			provider:   "aws",
+			annotation: "eks.amazonaws.com/role-arn",
+			identity:   "arn:aws:iam::123456789012:role/flux-source-controller",
+		},
+		{
+			provider:   "gcp",
+			annotation: "iam.gke.io/gcp-service-account",
+			identity:   "flux@my-project.iam.gserviceaccount.com",
+		},
+		{
+			provider:   "azure",
+			annotation: "azure.workload.identity/client-id",
+			identity:   "00000000-0000-0000-0000-000000000000",
+			podLabels:  map[string]string{"azure.workload.identity/use": "true"},
+		},
+		{
+			provider:    "oracle",
+			identity:    "flux",
+			expectedErr: `unsupported OIDC provider "oracle"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.provider, func(t *testing.T) {
+			b := &Bootstrap{}
+			WithFluxOIDCProvider(tc.provider, tc.identity)(&b.options)
+			opts, fopts := b.newFluxOptions(t.TempDir(), nil)
+			for _, o := range fopts {
+				o(opts)
+			}
+
+			f := &fluxInstall{fluxOptions: opts}
+			kfile, kus, err := f.generateKustomization(bytes.NewReader(append(sourceControllerManifests, fluxControllers...)))
+			require.NoError(t, err)
+			res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			objects, err := kubeutils.YamlToUnstructructured(res)
+			require.NoError(t, err)
+			for _, obj := range objects {
+				switch {
+				case obj.GetKind() == "ServiceAccount":
+					assert.Equal(t, map[string]string{tc.annotation: tc.identity}, obj.GetAnnotations())
+				case obj.GetName() == "source-controller":
+					sa, _, err := unstructured.NestedString(obj.Object, "spec", "template", "spec", "serviceAccountName")
+					require.NoError(t, err)
+					assert.Equal(t, "source-controller", sa)
+					labels, _, err := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "labels")
+					require.NoError(t, err)
+					assert.Equal(t, tc.podLabels, labels)
+				default:
+					// other controllers are not bound to the workload identity
+					_, ok, err := unstructured.NestedString(obj.Object, "spec", "template", "spec", "serviceAccountName")
+					require.NoError(t, err)
+					assert.False(t, ok)
+				}
+			}
+		})
+	}
+}
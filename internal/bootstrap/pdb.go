@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// pdbFileName is the name of the file holding the PodDisruptionBudgets of the Flux controllers.
+const pdbFileName = "pod-disruption-budgets.yaml"
+
+// WithFluxPodDisruptionBudgets adds a PodDisruptionBudget with the given minimum number of
+// available pods for each Flux controller. No PodDisruptionBudgets are created if it is 0.
+func WithFluxPodDisruptionBudgets(minAvailable int) Option {
+	return func(o *options) {
+		o.fluxPDBMinAvailable = minAvailable
+	}
+}
+
+// withFluxPodDisruptionBudgets adds a PodDisruptionBudget for each Flux controller to the kustomization.
+func withFluxPodDisruptionBudgets(minAvailable int) fluxOption {
+	return func(o *fluxOptions) {
+		o.pdbMinAvailable = minAvailable
+	}
+}
+
+// addPodDisruptionBudgets writes a PodDisruptionBudget for each Flux controller to dir
+// and adds them to the resources of the kustomization.
+func (f *fluxInstall) addPodDisruptionBudgets(kus *kustypes.Kustomization) error {
+	if f.pdbMinAvailable <= 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, component := range f.components {
+		data, err := yaml.Marshal(newPodDisruptionBudget(component, f.namespace, f.pdbMinAvailable))
+		if err != nil {
+			return fmt.Errorf("failed to marshal PodDisruptionBudget for %s: %w", component, err)
+		}
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+
+	if err := os.WriteFile(filepath.Join(f.dir, pdbFileName), buf.Bytes(), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to write PodDisruptionBudgets: %w", err)
+	}
+
+	kus.Resources = append(kus.Resources, "./"+pdbFileName)
+	return nil
+}
+
+// newPodDisruptionBudget returns a PodDisruptionBudget for the pods of the given Flux controller.
+func newPodDisruptionBudget(component, namespace string, minAvailable int) *policyv1.PodDisruptionBudget {
+	minAvailablePods := intstr.FromInt(minAvailable)
+	return &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: policyv1.SchemeGroupVersion.String(),
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      component,
+			Namespace: namespace,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailablePods,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": component},
+			},
+		},
+	}
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	cfd "github.com/open-component-model/ocm-controller/pkg/configdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestFluxPodDisruptionBudgets(t *testing.T) {
+	b := &Bootstrap{}
+	WithFluxPodDisruptionBudgets(1)(&b.options)
+	opts, fopts := b.newFluxOptions(t.TempDir(), nil)
+	for _, o := range fopts {
+		o(opts)
+	}
+
+	f := &fluxInstall{
+		fluxOptions: opts,
+		components:  []string{"source-controller", "kustomize-controller"},
+	}
+	kfile, kus, err := f.generateKustomization(bytes.NewReader(kustomizedDeployment))
+	require.NoError(t, err)
+	res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+	require.NoError(t, err)
+
+	objects, err := kubeutils.YamlToUnstructructured(res)
+	require.NoError(t, err)
+
+	var pdbs []string
+	for _, obj := range objects {
+		if obj.GetKind() != "PodDisruptionBudget" {
+			continue
+		}
+		pdbs = append(pdbs, obj.GetName())
+		assert.Equal(t, "flux-system", obj.GetNamespace())
+		assert.Equal(t, "policy/v1", obj.GetAPIVersion())
+
+		minAvailable, _, err := unstructured.NestedInt64(obj.Object, "spec", "minAvailable")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), minAvailable)
+		app, _, err := unstructured.NestedString(obj.Object, "spec", "selector", "matchLabels", "app")
+		require.NoError(t, err)
+		assert.Equal(t, obj.GetName(), app)
+	}
+	assert.ElementsMatch(t, []string{"source-controller", "kustomize-controller"}, pdbs)
+}
+
+func TestFluxPodDisruptionBudgetsDisabled(t *testing.T) {
+	f := &fluxInstall{
+		fluxOptions: &fluxOptions{dir: t.TempDir(), namespace: "flux-system"},
+		components:  []string{"source-controller"},
+	}
+	kfile, kus, err := f.generateKustomization(bytes.NewReader(kustomizedDeployment))
+	require.NoError(t, err)
+	res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(res), "PodDisruptionBudget")
+	assert.Equal(t, intstr.FromInt(2), *newPodDisruptionBudget("source-controller", "flux-system", 2).Spec.MinAvailable)
+}
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/ocm"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	"k8s.io/client-go/discovery"
+)
+
+// defaultMinKubernetesVersion is the minimum Kubernetes version supported by the bootstrap components.
+const defaultMinKubernetesVersion = ">= 1.25.0-0"
+
+const (
+	// PreflightCheckToken is the check verifying that the token can create repositories.
+	PreflightCheckToken = "token"
+	// PreflightCheckCluster is the check verifying that the cluster is reachable and supported.
+	PreflightCheckCluster = "cluster"
+	// PreflightCheckRegistry is the check verifying that the OCI registry is reachable.
+	PreflightCheckRegistry = "registry"
+	// PreflightCheckComponents is the check verifying that all component version constraints are satisfiable.
+	PreflightCheckComponents = "components"
+	// PreflightCheckSecrets is the check verifying that the required secrets exist.
+	PreflightCheckSecrets = "secrets"
+)
+
+// PreflightError is a failed pre-flight check.
+type PreflightError struct {
+	// Check is the name of the failed check.
+	Check string
+	// Err is the reason the check failed.
+	Err error
+}
+
+// Error implements the error interface.
+func (e PreflightError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Check, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e PreflightError) Unwrap() error {
+	return e.Err
+}
+
+// WithMinKubernetesVersion sets the semver constraint the Kubernetes version of the cluster must satisfy,
+// e.g. ">= 1.27.0-0". It defaults to the minimum version supported by the bootstrap components.
+func WithMinKubernetesVersion(constraint string) Option {
+	return func(o *options) {
+		o.minKubernetesVersion = constraint
+	}
+}
+
+// WithSkipPreflightChecks disables the pre-flight checks run before the bootstrap mutates anything.
+func WithSkipPreflightChecks(skip bool) Option {
+	return func(o *options) {
+		o.skipPreflightChecks = skip
+	}
+}
+
+// PreflightCheck verifies the prerequisites of the bootstrap without mutating anything.
+// All checks are run and the failures of the fatal checks are returned together.
+func (b *Bootstrap) PreflightCheck(ctx context.Context) []PreflightError {
+	_, err := b.PreFlight(ctx)
+	if err == nil {
+		return nil
+	}
+
+	var errs []PreflightError
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var perr PreflightError
+		if errors.As(err, &perr) {
+			errs = append(errs, perr)
+		}
+	}
+
+	return errs
+}
+
+// checkTokenPermissions verifies that the token of the provider client can create repositories.
+// Providers that cannot report token permissions pass the check.
+func (b *Bootstrap) checkTokenPermissions(ctx context.Context) error {
+	ok, err := b.providerClient.HasTokenPermission(ctx, gitprovider.TokenPermissionRWRepository)
+	if err != nil {
+		if errors.Is(err, gitprovider.ErrNoProviderSupport) {
+			return nil
+		}
+		return fmt.Errorf("failed to check token permissions: %w", err)
+	}
+
+	if !ok {
+		return fmt.Errorf("token does not have permission to create repositories")
+	}
+
+	return nil
+}
+
+// checkKubernetesVersion verifies that the cluster is reachable and its Kubernetes version
+// satisfies the minimum version constraint.
+func checkKubernetesVersion(dc discovery.ServerVersionInterface, minVersion string) error {
+	v, err := serverVersion(dc)
+	if err != nil {
+		return err
+	}
+
+	constraint, err := semver.NewConstraint(minVersion)
+	if err != nil {
+		return fmt.Errorf("invalid minimum Kubernetes version %q: %w", minVersion, err)
+	}
+
+	if !constraint.Check(v) {
+		return fmt.Errorf("kubernetes version %s does not satisfy the required minimum version %q", v.Original(), minVersion)
+	}
+
+	return nil
+}
+
+// kubernetesVersionConstraint returns the constraint the Kubernetes version of the cluster must satisfy.
+func (b *Bootstrap) kubernetesVersionConstraint() string {
+	if b.minKubernetesVersion != "" {
+		return b.minKubernetesVersion
+	}
+	return defaultMinKubernetesVersion
+}
+
+// serverVersion returns the Kubernetes version of the cluster.
+func serverVersion(dc discovery.ServerVersionInterface) (*semver.Version, error) {
+	info, err := dc.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach cluster: %w", err)
+	}
+
+	v, err := semver.NewVersion(info.GitVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Kubernetes version %q: %w", info.GitVersion, err)
+	}
+
+	return v, nil
+}
+
+// checkRegistry verifies that the OCI registry, or one of the fallback registries, is reachable
+// with the configured credentials. The returned repository must be closed by the caller.
+func (b *Bootstrap) checkRegistry(ctx context.Context, octx om.Context) (om.Repository, error) {
+	// when transferring from a file the bootstrap component is not yet in the registry
+	if b.fromFile != "" {
+		ociRepo, err := ocm.MakeRepositoryWithDockerConfig(octx, b.registry, b.dockerConfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create repository for %s: %w", b.registry, err)
+		}
+		return ociRepo, nil
+	}
+
+	return b.makeOCIRepositoryWithFallback(ctx, octx)
+}
+
+// listBootstrapComponentVersions lists the versions of the bootstrap component, which requires
+// the registry to be reachable and the credentials to be valid.
+func listBootstrapComponentVersions(repository om.Repository) error {
+	c, err := repository.LookupComponent(env.DefaultBootstrapComponent)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.ListVersions()
+	return err
+}
+
+// checkComponentConstraints verifies that a version satisfying the constraint of each reference exists.
+func checkComponentConstraints(repository om.Repository, refs map[string]compdesc.ComponentReference) error {
+	var errs []error
+	for _, comp := range getOrderedKeys(refs) {
+		ref := refs[comp]
+		cv, err := getComponentVersion(repository, ref.GetComponentName(), ref.GetVersion())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("component %s with version %s: %w", ref.GetComponentName(), ref.GetVersion(), err))
+			continue
+		}
+		cv.Close()
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/ocm"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PreflightCheckNamespaces is the check reporting whether the namespaces of the components exist.
+// Missing namespaces are created by the bootstrap, so the check is not fatal.
+const PreflightCheckNamespaces = "namespaces"
+
+// CheckResult is the result of a single pre-flight check.
+type CheckResult struct {
+	// Name is the name of the check, e.g. registry.
+	Name string
+	// Passed is true if the check succeeded.
+	Passed bool
+	// Message describes why the check failed, or is "passed".
+	Message string
+}
+
+// PreFlightReport holds the results of all pre-flight checks in the order they were defined.
+type PreFlightReport struct {
+	Checks []CheckResult
+}
+
+// preFlightCheck is a pre-flight check. A failed fatal check fails the pre-flight.
+type preFlightCheck struct {
+	name  string
+	fatal bool
+	run   func(ctx context.Context) error
+}
+
+// PreFlight runs all pre-flight checks in parallel and reports their results.
+// An error is returned if any fatal check fails, the report is returned in any case.
+func (b *Bootstrap) PreFlight(ctx context.Context) (*PreFlightReport, error) {
+	return runPreFlightChecks(ctx, b.preFlightChecks())
+}
+
+// preFlightChecks returns the pre-flight checks of the bootstrap.
+func (b *Bootstrap) preFlightChecks() []preFlightCheck {
+	var checks []preFlightCheck
+	if !b.clusterOnly {
+		checks = append(checks, preFlightCheck{name: PreflightCheckToken, fatal: true, run: b.checkTokenPermissions})
+	}
+
+	return append(checks,
+		preFlightCheck{name: PreflightCheckCluster, fatal: true, run: func(context.Context) error {
+			dc, err := b.restClientGetter.ToDiscoveryClient()
+			if err != nil {
+				return fmt.Errorf("failed to create discovery client: %w", err)
+			}
+			return checkKubernetesVersion(dc, b.kubernetesVersionConstraint())
+		}},
+		preFlightCheck{name: PreflightCheckRegistry, fatal: true, run: func(ctx context.Context) error {
+			ociRepo, err := b.checkRegistry(ctx, om.DefaultContext())
+			if err != nil {
+				return err
+			}
+			return ociRepo.Close()
+		}},
+		preFlightCheck{name: PreflightCheckComponents, fatal: true, run: b.checkBootstrapComponents},
+		preFlightCheck{name: PreflightCheckSecrets, fatal: true, run: func(ctx context.Context) error {
+			var errs []error
+			for _, err := range b.ValidateSecrets(ctx, b.requiredSecrets) {
+				errs = append(errs, err)
+			}
+			return errors.Join(errs...)
+		}},
+		preFlightCheck{name: PreflightCheckNamespaces, run: b.checkNamespaces},
+	)
+}
+
+// runPreFlightChecks runs the checks in parallel. The returned error joins the failures of
+// the fatal checks as PreflightErrors.
+func runPreFlightChecks(ctx context.Context, checks []preFlightCheck) (*PreFlightReport, error) {
+	report := &PreFlightReport{Checks: make([]CheckResult, len(checks))}
+	errs := make([]error, len(checks))
+
+	var g errgroup.Group
+	for i, check := range checks {
+		i, check := i, check
+		g.Go(func() error {
+			result := CheckResult{Name: check.name, Passed: true, Message: "passed"}
+			if err := check.run(ctx); err != nil {
+				result.Passed = false
+				result.Message = err.Error()
+				if check.fatal {
+					errs[i] = PreflightError{Check: check.name, Err: err}
+				}
+			}
+			report.Checks[i] = result
+			return nil
+		})
+	}
+	// the checks never return an error, so that all of them run to completion
+	_ = g.Wait()
+
+	return report, errors.Join(errs...)
+}
+
+// checkBootstrapComponents verifies that all component version constraints of the bootstrap
+// component are satisfiable. When transferring from a file the file is checked instead of the registry.
+func (b *Bootstrap) checkBootstrapComponents(ctx context.Context) error {
+	var source om.Repository
+	if b.fromFile != "" {
+		ctf, err := ocm.RepositoryFromCTF(b.fromFile)
+		if err != nil {
+			return fmt.Errorf("failed to open CTF %q: %w", b.fromFile, err)
+		}
+		source = ctf
+	} else {
+		ociRepo, err := b.checkRegistry(ctx, om.DefaultContext())
+		if err != nil {
+			return err
+		}
+		source = ociRepo
+	}
+	defer source.Close()
+
+	refs, err := b.fetchBootstrapComponentReferences(source)
+	if err != nil {
+		return err
+	}
+
+	return checkComponentConstraints(source, refs)
+}
+
+// checkNamespaces reports the namespaces of the components which do not exist yet.
+func (b *Bootstrap) checkNamespaces(ctx context.Context) error {
+	namespaces := map[string]struct{}{
+		b.componentNamespace(env.FluxName, env.DefaultFluxNamespace): {},
+		env.DefaultCertManagerNamespace:                              {},
+	}
+	for _, comp := range []string{env.OcmControllerName, env.MpasProductControllerName} {
+		ns, _, err := b.componentDeployments(comp)
+		if err != nil {
+			return err
+		}
+		namespaces[ns] = struct{}{}
+	}
+
+	var missing []string
+	for ns := range namespaces {
+		if err := b.kubeclient.Get(ctx, client.ObjectKey{Name: ns}, &corev1.Namespace{}); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to get namespace %s: %w", ns, err)
+			}
+			missing = append(missing, ns)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("namespaces %s do not exist and will be created", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
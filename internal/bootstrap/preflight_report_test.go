@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRunPreFlightChecks(t *testing.T) {
+	pass := func(context.Context) error { return nil }
+	fail := func(msg string) func(context.Context) error {
+		return func(context.Context) error { return errors.New(msg) }
+	}
+
+	testCases := []struct {
+		name        string
+		checks      []preFlightCheck
+		expected    []CheckResult
+		expectedErr string
+	}{
+		{
+			name: "all checks pass",
+			checks: []preFlightCheck{
+				{name: PreflightCheckToken, fatal: true, run: pass},
+				{name: PreflightCheckRegistry, fatal: true, run: pass},
+			},
+			expected: []CheckResult{
+				{Name: PreflightCheckToken, Passed: true, Message: "passed"},
+				{Name: PreflightCheckRegistry, Passed: true, Message: "passed"},
+			},
+		},
+		{
+			name: "non-fatal check fails",
+			checks: []preFlightCheck{
+				{name: PreflightCheckToken, fatal: true, run: pass},
+				{name: PreflightCheckNamespaces, run: fail("namespaces flux-system do not exist and will be created")},
+			},
+			expected: []CheckResult{
+				{Name: PreflightCheckToken, Passed: true, Message: "passed"},
+				{Name: PreflightCheckNamespaces, Message: "namespaces flux-system do not exist and will be created"},
+			},
+		},
+		{
+			name: "fatal checks fail",
+			checks: []preFlightCheck{
+				{name: PreflightCheckToken, fatal: true, run: fail("token does not have permission to create repositories")},
+				{name: PreflightCheckCluster, fatal: true, run: pass},
+				{name: PreflightCheckRegistry, fatal: true, run: fail("registry unreachable")},
+				{name: PreflightCheckNamespaces, run: fail("namespaces ocm-system do not exist and will be created")},
+			},
+			expected: []CheckResult{
+				{Name: PreflightCheckToken, Message: "token does not have permission to create repositories"},
+				{Name: PreflightCheckCluster, Passed: true, Message: "passed"},
+				{Name: PreflightCheckRegistry, Message: "registry unreachable"},
+				{Name: PreflightCheckNamespaces, Message: "namespaces ocm-system do not exist and will be created"},
+			},
+			expectedErr: "token: token does not have permission to create repositories\nregistry: registry unreachable",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := runPreFlightChecks(context.Background(), tc.checks)
+			require.NotNil(t, report)
+			assert.Equal(t, tc.expected, report.Checks)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				var perr PreflightError
+				assert.ErrorAs(t, err, &perr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPreFlightCheckNamespaces(t *testing.T) {
+	kubeclient := fake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "flux-system"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ocm-system"}},
+	).Build()
+
+	b := &Bootstrap{options: options{kubeclient: kubeclient}}
+	err := b.checkNamespaces(context.Background())
+	assert.EqualError(t, err, "namespaces cert-manager, mpas-system do not exist and will be created")
+}
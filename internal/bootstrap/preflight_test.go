@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/ocm-controller/pkg/fakes"
+	"github.com/open-component-model/ocm/pkg/contexts/datacontext"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestPreflightCheckTokenPermissions(t *testing.T) {
+	testCases := []struct {
+		name        string
+		client      *mockProviderClient
+		expectedErr string
+	}{
+		{
+			name:   "token can create repositories",
+			client: &mockProviderClient{hasPermission: true},
+		},
+		{
+			name:        "token cannot create repositories",
+			client:      &mockProviderClient{hasPermission: false},
+			expectedErr: "token does not have permission to create repositories",
+		},
+		{
+			name:   "provider does not support permission checks",
+			client: &mockProviderClient{permissionErr: gitprovider.ErrNoProviderSupport},
+		},
+		{
+			name:        "permission check fails",
+			client:      &mockProviderClient{permissionErr: errors.New("boom")},
+			expectedErr: "failed to check token permissions: boom",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &Bootstrap{providerClient: tc.client}
+			err := b.checkTokenPermissions(context.Background())
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPreflightCheckKubernetesVersion(t *testing.T) {
+	testCases := []struct {
+		name        string
+		gitVersion  string
+		minVersion  string
+		expectedErr string
+	}{
+		{
+			name:       "supported version",
+			gitVersion: "v1.27.3",
+		},
+		{
+			name:       "supported vendor version",
+			gitVersion: "v1.25.5-gke.1200",
+		},
+		{
+			name:        "unsupported version",
+			gitVersion:  "v1.24.9",
+			expectedErr: "kubernetes version v1.24.9 does not satisfy",
+		},
+		{
+			name:        "custom minimum version",
+			gitVersion:  "v1.25.9",
+			minVersion:  ">=1.26.0",
+			expectedErr: `kubernetes version v1.25.9 does not satisfy the required minimum version ">=1.26.0"`,
+		},
+		{
+			name:       "custom minimum version satisfied",
+			gitVersion: "v1.26.1",
+			minVersion: ">=1.26.0",
+		},
+		{
+			name:        "invalid minimum version",
+			gitVersion:  "v1.27.3",
+			minVersion:  "latest",
+			expectedErr: `invalid minimum Kubernetes version "latest"`,
+		},
+		{
+			name:        "invalid version",
+			gitVersion:  "unknown",
+			expectedErr: "failed to parse Kubernetes version",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dc := &fakediscovery.FakeDiscovery{
+				Fake:               &clienttesting.Fake{},
+				FakedServerVersion: &version.Info{GitVersion: tc.gitVersion},
+			}
+			b := &Bootstrap{}
+			WithMinKubernetesVersion(tc.minVersion)(&b.options)
+			err := checkKubernetesVersion(dc, b.kubernetesVersionConstraint())
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPreflightCheckRegistryFromFile(t *testing.T) {
+	// the registry is not contacted when the bootstrap component is transferred from a file
+	b := &Bootstrap{options: options{registry: "ghcr.io/open-component-model/mpas-bootstrap", fromFile: "bundle.tar"}}
+	repo, err := b.checkRegistry(context.Background(), om.New(datacontext.MODE_SHARED))
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+	require.NoError(t, repo.Close())
+}
+
+func TestPreflightCheckComponentConstraints(t *testing.T) {
+	repo := &mockRepository{
+		cv: []*mockComponentAccess{
+			{
+				name:     "ocm.software/ocm-controller",
+				versions: []string{"v0.1.0"},
+				cva: map[string]*fakes.Component{
+					"v0.1.0": {Name: "ocm.software/ocm-controller", Version: "v0.1.0"},
+				},
+			},
+		},
+	}
+
+	ref := func(name, componentName, version string) compdesc.ComponentReference {
+		return compdesc.ComponentReference{
+			ElementMeta:   compdesc.ElementMeta{Name: name, Version: version},
+			ComponentName: componentName,
+		}
+	}
+
+	refs := map[string]compdesc.ComponentReference{
+		"ocm-controller": ref("ocm-controller", "ocm.software/ocm-controller", ">=v0.1.0"),
+	}
+	assert.NoError(t, checkComponentConstraints(repo, refs))
+
+	refs["ocm-controller"] = ref("ocm-controller", "ocm.software/ocm-controller", ">=v0.2.0")
+	refs["git-controller"] = ref("git-controller", "ocm.software/git-controller", "v0.1.0")
+	err := checkComponentConstraints(repo, refs)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "component ocm.software/git-controller with version v0.1.0")
+	assert.ErrorContains(t, err, "component ocm.software/ocm-controller with version >=v0.2.0: no matching version found")
+}
+
+func TestPreflightError(t *testing.T) {
+	errCluster := errors.New("connection refused")
+	err := PreflightError{Check: PreflightCheckCluster, Err: errCluster}
+	assert.EqualError(t, err, "cluster: connection refused")
+	assert.ErrorIs(t, err, errCluster)
+}
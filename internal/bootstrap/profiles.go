@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BootstrapProfile is a named set of options for a common deployment pattern.
+type BootstrapProfile string
+
+const (
+	// ProfileMinimal installs the components with short timeouts and without any hardening.
+	ProfileMinimal BootstrapProfile = "minimal"
+	// ProfileProduction uses conservative timeouts, a private repository, signature verification,
+	// commit signing, PodDisruptionBudgets and server-side validation of the manifests.
+	ProfileProduction BootstrapProfile = "production"
+	// ProfileAirGapped syncs the components from the OCI registry instead of the management
+	// repository and verifies their signatures.
+	ProfileAirGapped BootstrapProfile = "air-gapped"
+)
+
+// profiles are the options of each bootstrap profile.
+var profiles = map[BootstrapProfile][]Option{
+	ProfileMinimal: {
+		WithTimeout(5 * time.Minute),
+		WithInterval(time.Minute),
+	},
+	ProfileProduction: {
+		WithTimeout(15 * time.Minute),
+		WithInterval(10 * time.Minute),
+		WithVisibility("private"),
+		withRequireSignatureVerification(),
+		withRequireCommitSigning(),
+		WithFluxPodDisruptionBudgets(1),
+		WithPreApplyValidation(true),
+	},
+	ProfileAirGapped: {
+		WithTimeout(15 * time.Minute),
+		WithInterval(10 * time.Minute),
+		WithVisibility("private"),
+		WithFluxOCISource(true),
+		withRequireSignatureVerification(),
+	},
+}
+
+// WithBootstrapProfile applies the options of the profile. Options following it override the
+// options of the profile. Profiles enabling signature verification or commit signing require
+// the keys to be set with WithVerifySignatures and WithSigningKey.
+func WithBootstrapProfile(profile BootstrapProfile) Option {
+	return func(o *options) {
+		o.profile = profile
+		for _, opt := range profiles[profile] {
+			opt(o)
+		}
+	}
+}
+
+// ListProfiles returns the names of the bootstrap profiles.
+func ListProfiles() []string {
+	names := make([]string, 0, len(profiles))
+	for profile := range profiles {
+		names = append(names, string(profile))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withRequireSignatureVerification requires a public key to verify the component signatures.
+func withRequireSignatureVerification() Option {
+	return func(o *options) {
+		o.requireSignatureVerification = true
+	}
+}
+
+// withRequireCommitSigning requires a key to sign the component commits.
+func withRequireCommitSigning() Option {
+	return func(o *options) {
+		o.requireCommitSigning = true
+	}
+}
+
+// validateProfile verifies that the profile exists and that the keys it requires are set.
+func validateProfile(opts *options) error {
+	if _, ok := profiles[opts.profile]; !ok {
+		return fmt.Errorf("unknown bootstrap profile %q, must be one of %s", opts.profile, strings.Join(ListProfiles(), ", "))
+	}
+
+	if opts.requireSignatureVerification && opts.publicKeyPath == "" {
+		return fmt.Errorf("bootstrap profile %s requires a public key to verify signatures", opts.profile)
+	}
+
+	if opts.requireCommitSigning && opts.signingKeyPath == "" {
+		return fmt.Errorf("bootstrap profile %s requires a key to sign commits", opts.profile)
+	}
+
+	return nil
+}
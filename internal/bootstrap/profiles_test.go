@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithBootstrapProfileOrder(t *testing.T) {
+	testCases := []struct {
+		name               string
+		opts               []Option
+		expectedTimeout    time.Duration
+		expectedVisibility string
+	}{
+		{
+			name:               "profile defaults",
+			opts:               []Option{WithBootstrapProfile(ProfileProduction)},
+			expectedTimeout:    15 * time.Minute,
+			expectedVisibility: "private",
+		},
+		{
+			name: "options after the profile override it",
+			opts: []Option{
+				WithBootstrapProfile(ProfileProduction),
+				WithTimeout(time.Minute),
+				WithVisibility("public"),
+			},
+			expectedTimeout:    time.Minute,
+			expectedVisibility: "public",
+		},
+		{
+			name: "profile overrides options before it",
+			opts: []Option{
+				WithTimeout(time.Minute),
+				WithVisibility("public"),
+				WithBootstrapProfile(ProfileProduction),
+			},
+			expectedTimeout:    15 * time.Minute,
+			expectedVisibility: "private",
+		},
+		{
+			name: "profiles compose",
+			opts: []Option{
+				WithBootstrapProfile(ProfileProduction),
+				WithBootstrapProfile(ProfileMinimal),
+			},
+			expectedTimeout:    5 * time.Minute,
+			expectedVisibility: "private",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var o options
+			for _, opt := range tc.opts {
+				opt(&o)
+			}
+			assert.Equal(t, tc.expectedTimeout, o.timeout)
+			assert.Equal(t, tc.expectedVisibility, o.visibility)
+		})
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	testCases := []struct {
+		name        string
+		opts        []Option
+		expectedErr string
+	}{
+		{
+			name: "production with keys",
+			opts: []Option{
+				WithBootstrapProfile(ProfileProduction),
+				WithVerifySignatures("public.pem"),
+				WithSigningKey("signing.asc", ""),
+			},
+		},
+		{
+			name:        "production without public key",
+			opts:        []Option{WithBootstrapProfile(ProfileProduction), WithSigningKey("signing.asc", "")},
+			expectedErr: "bootstrap profile production requires a public key to verify signatures",
+		},
+		{
+			name:        "production without signing key",
+			opts:        []Option{WithBootstrapProfile(ProfileProduction), WithVerifySignatures("public.pem")},
+			expectedErr: "bootstrap profile production requires a key to sign commits",
+		},
+		{
+			name: "minimal",
+			opts: []Option{WithBootstrapProfile(ProfileMinimal)},
+		},
+		{
+			name:        "unknown profile",
+			opts:        []Option{WithBootstrapProfile("staging")},
+			expectedErr: `unknown bootstrap profile "staging", must be one of air-gapped, minimal, production`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var o options
+			for _, opt := range tc.opts {
+				opt(&o)
+			}
+			err := validateProfile(&o)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestListProfiles(t *testing.T) {
+	assert.Equal(t, []string{"air-gapped", "minimal", "production"}, ListProfiles())
+}
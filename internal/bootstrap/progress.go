@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+const (
+	// ProgressPhaseRepository is the phase of preparing the management repository.
+	ProgressPhaseRepository = "repository"
+	// ProgressPhaseComponentInstall is the phase of installing a component.
+	ProgressPhaseComponentInstall = "component-install"
+	// ProgressPhaseHealthCheck is the phase of waiting for components to become ready.
+	ProgressPhaseHealthCheck = "health-check"
+	// ProgressPhaseComplete is emitted once the bootstrap completed successfully.
+	ProgressPhaseComplete = "complete"
+)
+
+// ProgressEvent is a structured progress update emitted during bootstrap.
+type ProgressEvent struct {
+	// Phase is the bootstrap phase the event belongs to.
+	Phase string
+	// Component is the name of the component or namespace the event refers to, if any.
+	Component string
+	// Message is a human readable description of the event.
+	Message string
+	// Err is set if the phase failed.
+	Err error
+}
+
+// WithProgressChan sets the channel progress events are sent to.
+// Events are dropped if the channel is not ready to receive, so a slow consumer never stalls the bootstrap.
+// If no channel is set, progress is only reported through the printer.
+func WithProgressChan(progressChan chan<- ProgressEvent) Option {
+	return func(o *options) {
+		o.progressChan = progressChan
+	}
+}
+
+// emit sends the event to the progress channel without blocking.
+func (b *Bootstrap) emit(event ProgressEvent) {
+	b.logEvent(event)
+	b.writeAuditEvent(event)
+
+	if b.progressChan == nil {
+		return
+	}
+
+	select {
+	case b.progressChan <- event:
+	default:
+	}
+}
+
+// trackProgress wraps f so that a start event is emitted before f runs and
+// a finish event, carrying the error of f, is emitted afterwards.
+func (b *Bootstrap) trackProgress(phase, component string, f func() error) func() error {
+	return func() error {
+		b.emit(ProgressEvent{Phase: phase, Component: component, Message: "started"})
+
+		err := f()
+		event := ProgressEvent{Phase: phase, Component: component, Message: "finished", Err: err}
+		if err != nil {
+			event.Message = "failed"
+		}
+		b.emit(event)
+
+		return err
+	}
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressEvents(t *testing.T) {
+	events := make(chan ProgressEvent, 10)
+	b := &Bootstrap{}
+	WithProgressChan(events)(&b.options)
+
+	errHealth := errors.New("not ready")
+	require.NoError(t, b.trackProgress(ProgressPhaseRepository, "mpas-management", func() error { return nil })())
+	require.NoError(t, b.trackProgress(ProgressPhaseComponentInstall, "ocm-controller", func() error { return nil })())
+	require.ErrorIs(t, b.trackProgress(ProgressPhaseHealthCheck, "ocm-system", func() error { return errHealth })(), errHealth)
+	b.emit(ProgressEvent{Phase: ProgressPhaseComplete, Message: "bootstrap completed successfully"})
+	close(events)
+
+	var got []ProgressEvent
+	for event := range events {
+		got = append(got, event)
+	}
+
+	assert.Equal(t, []ProgressEvent{
+		{Phase: ProgressPhaseRepository, Component: "mpas-management", Message: "started"},
+		{Phase: ProgressPhaseRepository, Component: "mpas-management", Message: "finished"},
+		{Phase: ProgressPhaseComponentInstall, Component: "ocm-controller", Message: "started"},
+		{Phase: ProgressPhaseComponentInstall, Component: "ocm-controller", Message: "finished"},
+		{Phase: ProgressPhaseHealthCheck, Component: "ocm-system", Message: "started"},
+		{Phase: ProgressPhaseHealthCheck, Component: "ocm-system", Message: "failed", Err: errHealth},
+		{Phase: ProgressPhaseComplete, Message: "bootstrap completed successfully"},
+	}, got)
+}
+
+func TestProgressEventsNonBlocking(t *testing.T) {
+	// nobody receives from the unbuffered channel, so every event must be dropped
+	events := make(chan ProgressEvent)
+	b := &Bootstrap{}
+	WithProgressChan(events)(&b.options)
+
+	called := false
+	require.NoError(t, b.trackProgress(ProgressPhaseComponentInstall, "flux", func() error {
+		called = true
+		return nil
+	})())
+	assert.True(t, called)
+
+	// no channel configured
+	b = &Bootstrap{}
+	require.NoError(t, b.trackProgress(ProgressPhaseComponentInstall, "flux", func() error { return nil })())
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+// propagatedAnnotationPrefix is the prefix of the annotations propagated from the extra identity
+// of the flux resource.
+const propagatedAnnotationPrefix = "ocm.software/"
+
+// WithAnnotationPropagation adds the extra identity of the flux resource of the bootstrap component,
+// e.g. team: platform, as annotations prefixed with ocm.software/ to all Flux objects.
+func WithAnnotationPropagation(propagate bool) Option {
+	return func(o *options) {
+		o.annotationPropagation = propagate
+	}
+}
+
+// withAnnotationPropagation adds the extra identity of the flux resource as common annotations to the kustomization.
+func withAnnotationPropagation(propagate bool) fluxOption {
+	return func(o *fluxOptions) {
+		o.annotationPropagation = propagate
+	}
+}
+
+// extraIdentityAnnotations returns the extra identity as annotations prefixed with ocm.software/.
+func extraIdentityAnnotations(identity map[string]string) map[string]string {
+	if len(identity) == 0 {
+		return nil
+	}
+
+	annotations := make(map[string]string, len(identity))
+	for k, v := range identity {
+		annotations[propagatedAnnotationPrefix+k] = v
+	}
+
+	return annotations
+}
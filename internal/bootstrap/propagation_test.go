@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	cfd "github.com/open-component-model/ocm-controller/pkg/configdata"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	ocmmetav1 "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/meta/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotationPropagation(t *testing.T) {
+	meta := &compdesc.ResourceMeta{
+		ElementMeta: compdesc.ElementMeta{
+			Name:          "flux",
+			Version:       "v2.0.0",
+			ExtraIdentity: ocmmetav1.Identity{"team": "platform"},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		propagate bool
+		expected  map[string]string
+	}{
+		{
+			name:      "propagated",
+			propagate: true,
+			expected:  map[string]string{"ocm.software/team": "platform"},
+		},
+		{
+			name: "not propagated",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &Bootstrap{}
+			WithAnnotationPropagation(tc.propagate)(&b.options)
+			opts, fopts := b.newFluxOptions(t.TempDir(), nil)
+			for _, o := range fopts {
+				o(opts)
+			}
+
+			f := &fluxInstall{fluxOptions: opts, extraIdentity: meta.ExtraIdentity}
+			kfile, kus, err := f.generateKustomization(bytes.NewReader(fluxControllers))
+			require.NoError(t, err)
+			res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+			require.NoError(t, err)
+
+			objects, err := kubeutils.YamlToUnstructructured(res)
+			require.NoError(t, err)
+			require.NotEmpty(t, objects)
+			for _, obj := range objects {
+				if tc.expected == nil {
+					assert.Empty(t, obj.GetAnnotations(), obj.GetName())
+					continue
+				}
+				assert.Equal(t, tc.expected, obj.GetAnnotations(), obj.GetName())
+			}
+		})
+	}
+}
+
+func TestExtraIdentityAnnotations(t *testing.T) {
+	assert.Nil(t, extraIdentityAnnotations(nil))
+	assert.Equal(t, map[string]string{
+		"ocm.software/team":        "platform",
+		"ocm.software/environment": "production",
+	}, extraIdentityAnnotations(map[string]string{"team": "platform", "environment": "production"}))
+}
@@ -0,0 +1,922 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/mpas/internal/env"
+)
+
+const (
+	// azureDevOpsAPIVersion is the version of the Azure DevOps REST API.
+	azureDevOpsAPIVersion = "7.0"
+	// azureDevOpsAgileProcess is the id of the Agile process template used for new projects.
+	azureDevOpsAgileProcess = "adcc42ab-9882-485e-a3ed-7678f01f66bc"
+	// azureDevOpsEmptyObjectID is the object id of a branch that does not exist yet.
+	azureDevOpsEmptyObjectID = "0000000000000000000000000000000000000000"
+)
+
+var (
+	// azureDevOpsPollInterval is the interval to poll for a newly created project.
+	azureDevOpsPollInterval = 2 * time.Second
+	// azureDevOpsPollAttempts is the number of times to poll for a newly created project.
+	azureDevOpsPollAttempts = 30
+)
+
+// azureDevOpsProviderFunc returns a new gitprovider.Client for Azure DevOps Repos.
+// The hostname is the organization URL, e.g. dev.azure.com/myorg, and the owner of the
+// management repository is the Azure DevOps project.
+func azureDevOpsProviderFunc(opts ProviderOptions) (gitprovider.Client, error) {
+	if opts.Hostname == "" {
+		return nil, fmt.Errorf("the organization URL, e.g. dev.azure.com/myorg, is required for Azure DevOps")
+	}
+
+	hostname := strings.TrimSuffix(strings.TrimPrefix(opts.Hostname, "https://"), "/")
+	return newAzureDevOpsClient("https://"+hostname, hostname, opts.Token, opts.TokenType, opts.DestructiveActions), nil
+}
+
+// azureDevOpsClient is a gitprovider.Client for Azure DevOps Repos.
+// Only project repositories, accessed through OrgRepositories, are supported.
+type azureDevOpsClient struct {
+	baseURL     string
+	domain      string
+	token       string
+	tokenType   string
+	destructive bool
+	httpClient  *http.Client
+}
+
+var _ gitprovider.Client = &azureDevOpsClient{}
+
+func newAzureDevOpsClient(baseURL, domain, token, tokenType string, destructive bool) *azureDevOpsClient {
+	return &azureDevOpsClient{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		domain:      domain,
+		token:       token,
+		tokenType:   tokenType,
+		destructive: destructive,
+		httpClient:  http.DefaultClient,
+	}
+}
+
+// SupportedDomain returns the organization URL.
+func (c *azureDevOpsClient) SupportedDomain() string {
+	return c.domain
+}
+
+// ProviderID returns the provider id of Azure DevOps.
+func (c *azureDevOpsClient) ProviderID() gitprovider.ProviderID {
+	return gitprovider.ProviderID(env.ProviderAzureDevOps)
+}
+
+// Raw returns the client itself as there is no underlying SDK client.
+func (c *azureDevOpsClient) Raw() interface{} {
+	return c
+}
+
+// HasTokenPermission is not supported by Azure DevOps.
+func (c *azureDevOpsClient) HasTokenPermission(_ context.Context, _ gitprovider.TokenPermission) (bool, error) {
+	return false, gitprovider.ErrNoProviderSupport
+}
+
+// OrgRepositories returns a client for the repositories of Azure DevOps projects.
+func (c *azureDevOpsClient) OrgRepositories() gitprovider.OrgRepositoriesClient {
+	return &azureDevOpsOrgRepositoriesClient{client: c}
+}
+
+// Organizations returns a client failing with gitprovider.ErrNoProviderSupport,
+// as the projects are managed through OrgRepositories.
+func (c *azureDevOpsClient) Organizations() gitprovider.OrganizationsClient {
+	return azureDevOpsOrganizationsClient{}
+}
+
+// UserRepositories returns a client failing with gitprovider.ErrNoProviderSupport,
+// as Azure DevOps repositories always belong to a project.
+func (c *azureDevOpsClient) UserRepositories() gitprovider.UserRepositoriesClient {
+	return azureDevOpsUserRepositoriesClient{}
+}
+
+// do sends a request to the Azure DevOps REST API and decodes the response into out if set.
+// A 404 response is returned as gitprovider.ErrNotFound.
+func (c *azureDevOpsClient) do(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("api-version", azureDevOpsAPIVersion)
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s?%s", c.baseURL, path, query.Encode()), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if strings.EqualFold(c.tokenType, "bearer") {
+		// Azure AD access token
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else {
+		// personal access tokens use basic auth with an empty username
+		req.SetBasicAuth("", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return gitprovider.ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("%s %s failed with status code %d: %s", method, path, resp.StatusCode, apiErr.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+type azureDevOpsProject struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+// getProject returns the project with the given name.
+func (c *azureDevOpsClient) getProject(ctx context.Context, name string) (*azureDevOpsProject, error) {
+	project := &azureDevOpsProject{}
+	if err := c.do(ctx, http.MethodGet, "_apis/projects/"+url.PathEscape(name), nil, nil, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// ensureProject returns the project with the given name and creates it if it does not exist.
+// Projects are created asynchronously, so the project is polled until it is well formed.
+func (c *azureDevOpsClient) ensureProject(ctx context.Context, name, visibility string) (*azureDevOpsProject, error) {
+	project, err := c.getProject(ctx, name)
+	if err == nil {
+		return project, nil
+	}
+	if !errors.Is(err, gitprovider.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get project %s: %w", name, err)
+	}
+
+	req := map[string]any{
+		"name":       name,
+		"visibility": visibility,
+		"capabilities": map[string]any{
+			"versioncontrol":  map[string]string{"sourceControlType": "Git"},
+			"processTemplate": map[string]string{"templateTypeId": azureDevOpsAgileProcess},
+		},
+	}
+	if err := c.do(ctx, http.MethodPost, "_apis/projects", nil, req, nil); err != nil {
+		return nil, fmt.Errorf("failed to create project %s: %w", name, err)
+	}
+
+	for i := 0; i < azureDevOpsPollAttempts; i++ {
+		project, err = c.getProject(ctx, name)
+		if err == nil && project.State == "wellFormed" {
+			return project, nil
+		}
+		if err != nil && !errors.Is(err, gitprovider.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get project %s: %w", name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(azureDevOpsPollInterval):
+		}
+	}
+
+	return nil, fmt.Errorf("project %s was not created in time", name)
+}
+
+// azureDevOpsOrgRepositoriesClient manages the repositories of Azure DevOps projects.
+// The organization of the repository reference is the project.
+type azureDevOpsOrgRepositoriesClient struct {
+	client *azureDevOpsClient
+}
+
+var _ gitprovider.OrgRepositoriesClient = &azureDevOpsOrgRepositoriesClient{}
+
+// Get returns the repository or gitprovider.ErrNotFound if it does not exist.
+func (c *azureDevOpsOrgRepositoriesClient) Get(ctx context.Context, ref gitprovider.OrgRepositoryRef) (gitprovider.OrgRepository, error) {
+	info := azureDevOpsRepositoryInfo{}
+	path := fmt.Sprintf("%s/_apis/git/repositories/%s", url.PathEscape(ref.Organization), url.PathEscape(ref.RepositoryName))
+	if err := c.client.do(ctx, http.MethodGet, path, nil, nil, &info); err != nil {
+		return nil, err
+	}
+
+	return newAzureDevOpsRepository(c.client, ref, info), nil
+}
+
+// List returns the repositories of the project.
+func (c *azureDevOpsOrgRepositoriesClient) List(ctx context.Context, ref gitprovider.OrganizationRef) ([]gitprovider.OrgRepository, error) {
+	var infos struct {
+		Value []azureDevOpsRepositoryInfo `json:"value"`
+	}
+	path := fmt.Sprintf("%s/_apis/git/repositories", url.PathEscape(ref.Organization))
+	if err := c.client.do(ctx, http.MethodGet, path, nil, nil, &infos); err != nil {
+		return nil, err
+	}
+
+	repos := make([]gitprovider.OrgRepository, 0, len(infos.Value))
+	for _, info := range infos.Value {
+		repos = append(repos, newAzureDevOpsRepository(c.client, gitprovider.OrgRepositoryRef{
+			OrganizationRef: ref,
+			RepositoryName:  info.Name,
+		}, info))
+	}
+
+	return repos, nil
+}
+
+// Create creates the repository, and the project if it does not exist.
+func (c *azureDevOpsOrgRepositoriesClient) Create(ctx context.Context, ref gitprovider.OrgRepositoryRef, req gitprovider.RepositoryInfo, _ ...gitprovider.RepositoryCreateOption) (gitprovider.OrgRepository, error) {
+	visibility := "private"
+	if req.Visibility != nil && *req.Visibility == gitprovider.RepositoryVisibilityPublic {
+		visibility = "public"
+	}
+
+	project, err := c.client.ensureProject(ctx, ref.Organization, visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	info := azureDevOpsRepositoryInfo{}
+	body := map[string]any{
+		"name":    ref.RepositoryName,
+		"project": map[string]string{"id": project.ID},
+	}
+	path := fmt.Sprintf("%s/_apis/git/repositories", url.PathEscape(ref.Organization))
+	if err := c.client.do(ctx, http.MethodPost, path, nil, body, &info); err != nil {
+		return nil, fmt.Errorf("failed to create repository %s: %w", ref.RepositoryName, err)
+	}
+
+	// the default branch is only set once the first commit is pushed
+	if info.DefaultBranch == "" && req.DefaultBranch != nil {
+		info.DefaultBranch = "refs/heads/" + *req.DefaultBranch
+	}
+
+	return newAzureDevOpsRepository(c.client, ref, info), nil
+}
+
+// Reconcile creates the repository if it does not exist. Existing repositories are not updated.
+func (c *azureDevOpsOrgRepositoriesClient) Reconcile(ctx context.Context, ref gitprovider.OrgRepositoryRef, req gitprovider.RepositoryInfo, _ ...gitprovider.RepositoryReconcileOption) (gitprovider.OrgRepository, bool, error) {
+	repo, err := c.Get(ctx, ref)
+	if err == nil {
+		return repo, false, nil
+	}
+	if !errors.Is(err, gitprovider.ErrNotFound) {
+		return nil, false, err
+	}
+
+	repo, err = c.Create(ctx, ref, req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return repo, true, nil
+}
+
+// azureDevOpsRepositoryInfo is the Azure DevOps representation of a Git repository.
+type azureDevOpsRepositoryInfo struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	DefaultBranch string `json:"defaultBranch"`
+	RemoteURL     string `json:"remoteUrl"`
+	SSHURL        string `json:"sshUrl"`
+}
+
+// azureDevOpsRepository is a repository of an Azure DevOps project.
+type azureDevOpsRepository struct {
+	client *azureDevOpsClient
+	ref    gitprovider.OrgRepositoryRef
+	info   azureDevOpsRepositoryInfo
+}
+
+var (
+	_ gitprovider.OrgRepository = &azureDevOpsRepository{}
+	_ gitprovider.CloneableURL  = &azureDevOpsRepository{}
+)
+
+func newAzureDevOpsRepository(client *azureDevOpsClient, ref gitprovider.OrgRepositoryRef, info azureDevOpsRepositoryInfo) *azureDevOpsRepository {
+	return &azureDevOpsRepository{
+		client: client,
+		ref:    ref,
+		info:   info,
+	}
+}
+
+// Get returns the repository information.
+func (r *azureDevOpsRepository) Get() gitprovider.RepositoryInfo {
+	var info gitprovider.RepositoryInfo
+	if branch := strings.TrimPrefix(r.info.DefaultBranch, "refs/heads/"); branch != "" {
+		info.DefaultBranch = gitprovider.StringVar(branch)
+	}
+	return info
+}
+
+// Repository returns the repository reference.
+func (r *azureDevOpsRepository) Repository() gitprovider.RepositoryRef {
+	return r.ref
+}
+
+// APIObject returns the Azure DevOps representation of the repository.
+func (r *azureDevOpsRepository) APIObject() interface{} {
+	return &r.info
+}
+
+// GetCloneURL returns the clone URL of the repository without credentials.
+func (r *azureDevOpsRepository) GetCloneURL(_ string, transport gitprovider.TransportType) string {
+	if transport == gitprovider.TransportTypeSSH {
+		return r.info.SSHURL
+	}
+
+	u, err := url.Parse(r.info.RemoteURL)
+	if err != nil {
+		return r.info.RemoteURL
+	}
+	// the remote URL contains the organization as username
+	u.User = nil
+	return u.String()
+}
+
+// CloneURLWithToken returns the HTTPS clone URL with the given token embedded.
+func (r *azureDevOpsRepository) CloneURLWithToken(token string) (string, error) {
+	u, err := url.Parse(r.GetCloneURL("", gitprovider.TransportTypeHTTPS))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse clone URL: %w", err)
+	}
+	u.User = url.UserPassword("git", token)
+	return u.String(), nil
+}
+
+// Delete deletes the repository if destructive actions are allowed.
+func (r *azureDevOpsRepository) Delete(ctx context.Context) error {
+	if !r.client.destructive {
+		return gitprovider.ErrDestructiveCallDisallowed
+	}
+
+	path := fmt.Sprintf("%s/_apis/git/repositories/%s", url.PathEscape(r.ref.Organization), r.info.ID)
+	return r.client.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+// Set is not supported, the repository settings are managed in Azure DevOps.
+func (r *azureDevOpsRepository) Set(_ gitprovider.RepositoryInfo) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// Update is not supported, the repository settings are managed in Azure DevOps.
+func (r *azureDevOpsRepository) Update(_ context.Context) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// Reconcile is not supported, the repository settings are managed in Azure DevOps.
+func (r *azureDevOpsRepository) Reconcile(_ context.Context) (bool, error) {
+	return false, gitprovider.ErrNoProviderSupport
+}
+
+// Commits returns a client to list and create commits.
+func (r *azureDevOpsRepository) Commits() gitprovider.CommitClient {
+	return &azureDevOpsCommitClient{repository: r}
+}
+
+// Branches returns a client to create branches.
+func (r *azureDevOpsRepository) Branches() gitprovider.BranchClient {
+	return &azureDevOpsBranchClient{repository: r}
+}
+
+// PullRequests returns a client to manage pull requests.
+func (r *azureDevOpsRepository) PullRequests() gitprovider.PullRequestClient {
+	return &azureDevOpsPullRequestClient{repository: r}
+}
+
+// Files returns a client to read files.
+func (r *azureDevOpsRepository) Files() gitprovider.FileClient {
+	return &azureDevOpsFileClient{repository: r}
+}
+
+// DeployKeys returns a client failing with gitprovider.ErrNoProviderSupport.
+func (r *azureDevOpsRepository) DeployKeys() gitprovider.DeployKeyClient {
+	return azureDevOpsDeployKeyClient{}
+}
+
+// DeployTokens is not supported by Azure DevOps.
+func (r *azureDevOpsRepository) DeployTokens() (gitprovider.DeployTokenClient, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Trees returns a client failing with gitprovider.ErrNoProviderSupport.
+func (r *azureDevOpsRepository) Trees() gitprovider.TreeClient {
+	return azureDevOpsTreeClient{}
+}
+
+// TeamAccess returns a client failing with gitprovider.ErrNoProviderSupport.
+func (r *azureDevOpsRepository) TeamAccess() gitprovider.TeamAccessClient {
+	return azureDevOpsTeamAccessClient{}
+}
+
+// webURL returns the URL of the repository in the browser.
+func (r *azureDevOpsRepository) webURL() string {
+	return r.GetCloneURL("", gitprovider.TransportTypeHTTPS)
+}
+
+// path returns the API path of the given repository resource.
+func (r *azureDevOpsRepository) path(resource string) string {
+	return fmt.Sprintf("%s/_apis/git/repositories/%s/%s", url.PathEscape(r.ref.Organization), r.info.ID, resource)
+}
+
+// exists returns whether the file exists on the given branch.
+func (r *azureDevOpsRepository) exists(ctx context.Context, path, branch string) (bool, error) {
+	query := url.Values{"path": {"/" + path}, "versionDescriptor.version": {branch}}
+	err := r.client.do(ctx, http.MethodGet, r.path("items"), query, nil, nil)
+	if errors.Is(err, gitprovider.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+type azureDevOpsCommitClient struct {
+	repository *azureDevOpsRepository
+}
+
+var _ gitprovider.CommitClient = &azureDevOpsCommitClient{}
+
+// Create pushes a commit with the given files to the branch. The branch is created if it does not exist.
+func (c *azureDevOpsCommitClient) Create(ctx context.Context, branch, message string, files []gitprovider.CommitFile) (gitprovider.Commit, error) {
+	var refs struct {
+		Value []struct {
+			ObjectID string `json:"objectId"`
+		} `json:"value"`
+	}
+	if err := c.repository.client.do(ctx, http.MethodGet, c.repository.path("refs"), url.Values{"filter": {"heads/" + branch}}, nil, &refs); err != nil {
+		return nil, fmt.Errorf("failed to get branch %s: %w", branch, err)
+	}
+
+	oldObjectID := azureDevOpsEmptyObjectID
+	if len(refs.Value) > 0 {
+		oldObjectID = refs.Value[0].ObjectID
+	}
+
+	changes := make([]map[string]any, 0, len(files))
+	for _, file := range files {
+		if file.Path == nil {
+			continue
+		}
+		path := strings.TrimPrefix(*file.Path, "/")
+
+		exists := false
+		if oldObjectID != azureDevOpsEmptyObjectID {
+			var err error
+			if exists, err = c.repository.exists(ctx, path, branch); err != nil {
+				return nil, fmt.Errorf("failed to check file %s: %w", path, err)
+			}
+		}
+
+		change := map[string]any{"item": map[string]string{"path": "/" + path}}
+		switch {
+		case file.Content == nil:
+			if !exists {
+				continue
+			}
+			change["changeType"] = "delete"
+		case exists:
+			change["changeType"] = "edit"
+		default:
+			change["changeType"] = "add"
+		}
+		if file.Content != nil {
+			change["newContent"] = map[string]string{"content": *file.Content, "contentType": "rawtext"}
+		}
+		changes = append(changes, change)
+	}
+
+	push := map[string]any{
+		"refUpdates": []map[string]string{{"name": "refs/heads/" + branch, "oldObjectId": oldObjectID}},
+		"commits":    []map[string]any{{"comment": message, "changes": changes}},
+	}
+	var resp struct {
+		Commits []struct {
+			CommitID string `json:"commitId"`
+		} `json:"commits"`
+	}
+	if err := c.repository.client.do(ctx, http.MethodPost, c.repository.path("pushes"), nil, push, &resp); err != nil {
+		return nil, fmt.Errorf("failed to push commit: %w", err)
+	}
+	if len(resp.Commits) == 0 {
+		return nil, fmt.Errorf("failed to push commit: no commit returned")
+	}
+
+	return &azureDevOpsCommit{info: gitprovider.CommitInfo{
+		Sha:     resp.Commits[0].CommitID,
+		Message: message,
+	}}, nil
+}
+
+// ListPage returns the commits of the branch, newest first. The pages start at 1, page 0 is the first page.
+func (c *azureDevOpsCommitClient) ListPage(ctx context.Context, branch string, perPage, page int) ([]gitprovider.Commit, error) {
+	skip := 0
+	if page > 1 {
+		skip = (page - 1) * perPage
+	}
+
+	var commits struct {
+		Value []struct {
+			CommitID string `json:"commitId"`
+			Comment  string `json:"comment"`
+			URL      string `json:"url"`
+			Author   struct {
+				Name string    `json:"name"`
+				Date time.Time `json:"date"`
+			} `json:"author"`
+		} `json:"value"`
+	}
+	query := url.Values{
+		"searchCriteria.itemVersion.version": {branch},
+		"searchCriteria.$top":                {fmt.Sprint(perPage)},
+		"searchCriteria.$skip":               {fmt.Sprint(skip)},
+	}
+	if err := c.repository.client.do(ctx, http.MethodGet, c.repository.path("commits"), query, nil, &commits); err != nil {
+		return nil, fmt.Errorf("failed to list commits of branch %s: %w", branch, err)
+	}
+
+	result := make([]gitprovider.Commit, 0, len(commits.Value))
+	for _, commit := range commits.Value {
+		result = append(result, &azureDevOpsCommit{info: gitprovider.CommitInfo{
+			Sha:       commit.CommitID,
+			Author:    commit.Author.Name,
+			Message:   commit.Comment,
+			CreatedAt: commit.Author.Date,
+			URL:       commit.URL,
+		}})
+	}
+
+	return result, nil
+}
+
+type azureDevOpsCommit struct {
+	info gitprovider.CommitInfo
+}
+
+// Get returns the commit information.
+func (c *azureDevOpsCommit) Get() gitprovider.CommitInfo {
+	return c.info
+}
+
+// APIObject returns the commit information.
+func (c *azureDevOpsCommit) APIObject() interface{} {
+	return &c.info
+}
+
+type azureDevOpsFileClient struct {
+	repository *azureDevOpsRepository
+}
+
+var _ gitprovider.FileClient = &azureDevOpsFileClient{}
+
+// Get returns the files of the given directory on the branch.
+func (c *azureDevOpsFileClient) Get(ctx context.Context, path, branch string, _ ...gitprovider.FilesGetOption) ([]*gitprovider.CommitFile, error) {
+	var items struct {
+		Value []struct {
+			Path     string `json:"path"`
+			IsFolder bool   `json:"isFolder"`
+		} `json:"value"`
+	}
+	query := url.Values{
+		"scopePath":                 {"/" + strings.TrimPrefix(path, "/")},
+		"recursionLevel":            {"OneLevel"},
+		"versionDescriptor.version": {branch},
+	}
+	if err := c.repository.client.do(ctx, http.MethodGet, c.repository.path("items"), query, nil, &items); err != nil {
+		return nil, err
+	}
+
+	var files []*gitprovider.CommitFile
+	for _, item := range items.Value {
+		if item.IsFolder {
+			continue
+		}
+
+		var content struct {
+			Content string `json:"content"`
+		}
+		query := url.Values{
+			"path":                      {item.Path},
+			"includeContent":            {"true"},
+			"versionDescriptor.version": {branch},
+		}
+		if err := c.repository.client.do(ctx, http.MethodGet, c.repository.path("items"), query, nil, &content); err != nil {
+			return nil, fmt.Errorf("failed to get file %s: %w", item.Path, err)
+		}
+
+		files = append(files, &gitprovider.CommitFile{
+			Path:    gitprovider.StringVar(strings.TrimPrefix(item.Path, "/")),
+			Content: gitprovider.StringVar(content.Content),
+		})
+	}
+
+	return files, nil
+}
+
+type azureDevOpsBranchClient struct {
+	repository *azureDevOpsRepository
+}
+
+var _ gitprovider.BranchClient = &azureDevOpsBranchClient{}
+
+// Create creates the branch pointing to the commit sha.
+func (c *azureDevOpsBranchClient) Create(ctx context.Context, branch, sha string) error {
+	update := []map[string]string{{
+		"name":        "refs/heads/" + branch,
+		"oldObjectId": azureDevOpsEmptyObjectID,
+		"newObjectId": sha,
+	}}
+	var resp struct {
+		Value []struct {
+			Success       bool   `json:"success"`
+			CustomMessage string `json:"customMessage"`
+		} `json:"value"`
+	}
+	if err := c.repository.client.do(ctx, http.MethodPost, c.repository.path("refs"), nil, update, &resp); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+	if len(resp.Value) == 0 || !resp.Value[0].Success {
+		msg := ""
+		if len(resp.Value) > 0 {
+			msg = resp.Value[0].CustomMessage
+		}
+		return fmt.Errorf("failed to create branch %s: %s", branch, msg)
+	}
+
+	return nil
+}
+
+// azureDevOpsPullRequestInfo is the Azure DevOps representation of a pull request.
+type azureDevOpsPullRequestInfo struct {
+	PullRequestID         int    `json:"pullRequestId"`
+	Status                string `json:"status"`
+	Title                 string `json:"title"`
+	LastMergeSourceCommit *struct {
+		CommitID string `json:"commitId"`
+	} `json:"lastMergeSourceCommit,omitempty"`
+}
+
+type azureDevOpsPullRequestClient struct {
+	repository *azureDevOpsRepository
+}
+
+var _ gitprovider.PullRequestClient = &azureDevOpsPullRequestClient{}
+
+// List returns the active pull requests of the repository.
+func (c *azureDevOpsPullRequestClient) List(ctx context.Context) ([]gitprovider.PullRequest, error) {
+	var prs struct {
+		Value []azureDevOpsPullRequestInfo `json:"value"`
+	}
+	if err := c.repository.client.do(ctx, http.MethodGet, c.repository.path("pullrequests"), nil, nil, &prs); err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	result := make([]gitprovider.PullRequest, 0, len(prs.Value))
+	for _, pr := range prs.Value {
+		result = append(result, c.pullRequest(pr))
+	}
+
+	return result, nil
+}
+
+// Create opens a pull request merging branch into baseBranch.
+func (c *azureDevOpsPullRequestClient) Create(ctx context.Context, title, branch, baseBranch, description string) (gitprovider.PullRequest, error) {
+	req := map[string]string{
+		"sourceRefName": "refs/heads/" + branch,
+		"targetRefName": "refs/heads/" + baseBranch,
+		"title":         title,
+		"description":   description,
+	}
+	pr := azureDevOpsPullRequestInfo{}
+	if err := c.repository.client.do(ctx, http.MethodPost, c.repository.path("pullrequests"), nil, req, &pr); err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+
+	return c.pullRequest(pr), nil
+}
+
+// Get returns the pull request with the given id.
+func (c *azureDevOpsPullRequestClient) Get(ctx context.Context, number int) (gitprovider.PullRequest, error) {
+	pr, err := c.get(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.pullRequest(*pr), nil
+}
+
+// Edit updates the title of the pull request.
+func (c *azureDevOpsPullRequestClient) Edit(ctx context.Context, number int, opts gitprovider.EditOptions) (gitprovider.PullRequest, error) {
+	req := map[string]string{}
+	if opts.Title != nil {
+		req["title"] = *opts.Title
+	}
+	pr := azureDevOpsPullRequestInfo{}
+	if err := c.repository.client.do(ctx, http.MethodPatch, c.repository.path(fmt.Sprintf("pullrequests/%d", number)), nil, req, &pr); err != nil {
+		return nil, fmt.Errorf("failed to edit pull request %d: %w", number, err)
+	}
+
+	return c.pullRequest(pr), nil
+}
+
+// Merge completes the pull request with the given merge method.
+func (c *azureDevOpsPullRequestClient) Merge(ctx context.Context, number int, mergeMethod gitprovider.MergeMethod, message string) error {
+	pr, err := c.get(ctx, number)
+	if err != nil {
+		return err
+	}
+	if pr.LastMergeSourceCommit == nil {
+		return fmt.Errorf("failed to merge pull request %d: no source commit", number)
+	}
+
+	strategy := "noFastForward"
+	if mergeMethod == gitprovider.MergeMethodSquash {
+		strategy = "squash"
+	}
+	req := map[string]any{
+		"status":                "completed",
+		"lastMergeSourceCommit": map[string]string{"commitId": pr.LastMergeSourceCommit.CommitID},
+		"completionOptions": map[string]string{
+			"mergeStrategy":      strategy,
+			"mergeCommitMessage": message,
+		},
+	}
+	if err := c.repository.client.do(ctx, http.MethodPatch, c.repository.path(fmt.Sprintf("pullrequests/%d", number)), nil, req, nil); err != nil {
+		return fmt.Errorf("failed to merge pull request %d: %w", number, err)
+	}
+
+	return nil
+}
+
+// get returns the Azure DevOps representation of the pull request with the given id.
+func (c *azureDevOpsPullRequestClient) get(ctx context.Context, number int) (*azureDevOpsPullRequestInfo, error) {
+	pr := &azureDevOpsPullRequestInfo{}
+	if err := c.repository.client.do(ctx, http.MethodGet, c.repository.path(fmt.Sprintf("pullrequests/%d", number)), nil, nil, pr); err != nil {
+		return nil, fmt.Errorf("failed to get pull request %d: %w", number, err)
+	}
+	return pr, nil
+}
+
+// pullRequest returns the pull request with its web URL in the repository.
+func (c *azureDevOpsPullRequestClient) pullRequest(pr azureDevOpsPullRequestInfo) *azureDevOpsPullRequest {
+	return &azureDevOpsPullRequest{
+		apiObject: pr,
+		info: gitprovider.PullRequestInfo{
+			Number: pr.PullRequestID,
+			Merged: pr.Status == "completed",
+			WebURL: fmt.Sprintf("%s/pullrequest/%d", c.repository.webURL(), pr.PullRequestID),
+		},
+	}
+}
+
+type azureDevOpsPullRequest struct {
+	apiObject azureDevOpsPullRequestInfo
+	info      gitprovider.PullRequestInfo
+}
+
+var _ gitprovider.PullRequest = &azureDevOpsPullRequest{}
+
+// Get returns the pull request information.
+func (p *azureDevOpsPullRequest) Get() gitprovider.PullRequestInfo {
+	return p.info
+}
+
+// APIObject returns the Azure DevOps representation of the pull request.
+func (p *azureDevOpsPullRequest) APIObject() interface{} {
+	return &p.apiObject
+}
+
+// The following clients are not supported by Azure DevOps. Their methods fail with
+// gitprovider.ErrNoProviderSupport instead of panicking on a nil interface.
+
+type azureDevOpsOrganizationsClient struct{}
+
+var _ gitprovider.OrganizationsClient = azureDevOpsOrganizationsClient{}
+
+func (azureDevOpsOrganizationsClient) Get(_ context.Context, _ gitprovider.OrganizationRef) (gitprovider.Organization, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+func (azureDevOpsOrganizationsClient) List(_ context.Context) ([]gitprovider.Organization, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+func (azureDevOpsOrganizationsClient) Children(_ context.Context, _ gitprovider.OrganizationRef) ([]gitprovider.Organization, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+type azureDevOpsUserRepositoriesClient struct{}
+
+var _ gitprovider.UserRepositoriesClient = azureDevOpsUserRepositoriesClient{}
+
+func (azureDevOpsUserRepositoriesClient) Get(_ context.Context, _ gitprovider.UserRepositoryRef) (gitprovider.UserRepository, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+func (azureDevOpsUserRepositoriesClient) List(_ context.Context, _ gitprovider.UserRef) ([]gitprovider.UserRepository, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+func (azureDevOpsUserRepositoriesClient) Create(_ context.Context, _ gitprovider.UserRepositoryRef, _ gitprovider.RepositoryInfo, _ ...gitprovider.RepositoryCreateOption) (gitprovider.UserRepository, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+func (azureDevOpsUserRepositoriesClient) Reconcile(_ context.Context, _ gitprovider.UserRepositoryRef, _ gitprovider.RepositoryInfo, _ ...gitprovider.RepositoryReconcileOption) (gitprovider.UserRepository, bool, error) {
+	return nil, false, gitprovider.ErrNoProviderSupport
+}
+
+type azureDevOpsDeployKeyClient struct{}
+
+var _ gitprovider.DeployKeyClient = azureDevOpsDeployKeyClient{}
+
+func (azureDevOpsDeployKeyClient) Get(_ context.Context, _ string) (gitprovider.DeployKey, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+func (azureDevOpsDeployKeyClient) List(_ context.Context) ([]gitprovider.DeployKey, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+func (azureDevOpsDeployKeyClient) Create(_ context.Context, _ gitprovider.DeployKeyInfo) (gitprovider.DeployKey, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+func (azureDevOpsDeployKeyClient) Reconcile(_ context.Context, _ gitprovider.DeployKeyInfo) (gitprovider.DeployKey, bool, error) {
+	return nil, false, gitprovider.ErrNoProviderSupport
+}
+
+type azureDevOpsTeamAccessClient struct{}
+
+var _ gitprovider.TeamAccessClient = azureDevOpsTeamAccessClient{}
+
+func (azureDevOpsTeamAccessClient) Get(_ context.Context, _ string) (gitprovider.TeamAccess, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+func (azureDevOpsTeamAccessClient) List(_ context.Context) ([]gitprovider.TeamAccess, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+func (azureDevOpsTeamAccessClient) Create(_ context.Context, _ gitprovider.TeamAccessInfo) (gitprovider.TeamAccess, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+func (azureDevOpsTeamAccessClient) Reconcile(_ context.Context, _ gitprovider.TeamAccessInfo) (gitprovider.TeamAccess, bool, error) {
+	return nil, false, gitprovider.ErrNoProviderSupport
+}
+
+type azureDevOpsTreeClient struct{}
+
+var _ gitprovider.TreeClient = azureDevOpsTreeClient{}
+
+func (azureDevOpsTreeClient) Create(_ context.Context, _ *gitprovider.TreeInfo) (*gitprovider.TreeInfo, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+func (azureDevOpsTreeClient) Get(_ context.Context, _ string, _ bool) (*gitprovider.TreeInfo, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+func (azureDevOpsTreeClient) List(_ context.Context, _, _ string, _ bool) ([]*gitprovider.TreeEntry, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
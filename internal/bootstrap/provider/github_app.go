@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	// githubAPIURL is the API URL of github.com.
+	githubAPIURL = "https://api.github.com"
+	// appTokenRefreshWindow is the time before expiry at which a cached installation token is refreshed.
+	appTokenRefreshWindow = 5 * time.Minute
+	// appJWTLifetime is the lifetime of the JWT used to request an installation token.
+	// GitHub allows at most 10 minutes.
+	appJWTLifetime = 9 * time.Minute
+	// appTokenRequestTimeout is the timeout of a request for an installation token.
+	appTokenRequestTimeout = 30 * time.Second
+)
+
+var (
+	// appTokenSources caches the installation token sources so that the tokens
+	// are reused within the same process.
+	appTokenSources   = make(map[appTokenKey]*appTokenSource)
+	appTokenSourcesMu sync.Mutex
+)
+
+type appTokenKey struct {
+	hostname       string
+	appID          int64
+	installationID int64
+}
+
+// appTokenSource creates and caches GitHub App installation tokens.
+type appTokenSource struct {
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	baseURL        string
+	httpClient     *http.Client
+	now            func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// GitHubAppTokenFunc returns a function returning an installation token of the GitHub App configured in opts.
+// The token is refreshed before it expires. It authenticates git operations on the management repository
+// the same way the provider client built from opts authenticates its API requests.
+func GitHubAppTokenFunc(opts ProviderOptions) (func(ctx context.Context) (string, error), error) {
+	source, err := githubAppTokenSource(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return source.Token, nil
+}
+
+// githubAppTokenSource returns the cached token source of the GitHub App configured in opts.
+func githubAppTokenSource(opts ProviderOptions) (*appTokenSource, error) {
+	key := appTokenKey{hostname: opts.Hostname, appID: opts.AppID, installationID: opts.InstallationID}
+
+	appTokenSourcesMu.Lock()
+	defer appTokenSourcesMu.Unlock()
+
+	if source, ok := appTokenSources[key]; ok {
+		return source, nil
+	}
+
+	source, err := newAppTokenSource(opts)
+	if err != nil {
+		return nil, err
+	}
+	appTokenSources[key] = source
+
+	return source, nil
+}
+
+func newAppTokenSource(opts ProviderOptions) (*appTokenSource, error) {
+	if opts.InstallationID == 0 || opts.AppPrivateKeyPath == "" {
+		return nil, fmt.Errorf("installation id and private key path are required for GitHub App authentication")
+	}
+
+	data, err := os.ReadFile(opts.AppPrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %w", err)
+	}
+
+	baseURL := githubAPIURL
+	if opts.Hostname != "" && opts.Hostname != "github.com" {
+		// GitHub Enterprise Server
+		baseURL = fmt.Sprintf("https://%s/api/v3", opts.Hostname)
+	}
+
+	return &appTokenSource{
+		appID:          opts.AppID,
+		installationID: opts.InstallationID,
+		key:            key,
+		baseURL:        baseURL,
+		httpClient:     &http.Client{Timeout: appTokenRequestTimeout},
+		now:            time.Now,
+	}, nil
+}
+
+// Token returns the cached installation token or requests a new one if the cached token
+// expires within the refresh window.
+func (s *appTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && s.now().Add(appTokenRefreshWindow).Before(s.expiresAt) {
+		return s.token, nil
+	}
+
+	token, expiresAt, err := s.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token, s.expiresAt = token, expiresAt
+
+	return token, nil
+}
+
+// transport returns a round tripper authenticating the requests with the installation token.
+func (s *appTokenSource) transport(next http.RoundTripper) http.RoundTripper {
+	return &appTransport{source: s, next: next}
+}
+
+// appTransport sets a valid installation token on every request, so that long running
+// bootstraps keep working after the first installation token expired.
+type appTransport struct {
+	source *appTokenSource
+	next   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return next.RoundTrip(req)
+}
+
+func (s *appTokenSource) requestToken(ctx context.Context) (string, time.Time, error) {
+	now := s.now()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		// backdate the token to allow for clock drift
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(appJWTLifetime)),
+		Issuer:    strconv.FormatInt(s.appID, 10),
+	}).SignedString(s.key)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign GitHub App token: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", s.baseURL, s.installationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+signed)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to request GitHub App installation token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", time.Time{}, fmt.Errorf("failed to request GitHub App installation token: unexpected status code %d", resp.StatusCode)
+	}
+
+	var token struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decode GitHub App installation token: %w", err)
+	}
+
+	return token.Token, token.ExpiresAt, nil
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppTokenSource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "app.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}), 0o600))
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/app/installations/42/access_tokens", r.URL.Path)
+
+		claims := &jwt.RegisteredClaims{}
+		_, err := jwt.ParseWithClaims(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), claims,
+			func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil },
+			jwt.WithoutClaimsValidation())
+		assert.NoError(t, err)
+		assert.Equal(t, "1234", claims.Issuer)
+
+		requests++
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"token":      fmt.Sprintf("token-%d", requests),
+			"expires_at": now.Add(time.Hour),
+		})
+	}))
+	defer server.Close()
+
+	source, err := newAppTokenSource(ProviderOptions{
+		AppID:             1234,
+		InstallationID:    42,
+		AppPrivateKeyPath: keyPath,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, githubAPIURL, source.baseURL)
+	source.baseURL = server.URL
+	source.now = func() time.Time { return now }
+
+	token, err := source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+
+	// the cached token is reused
+	token, err = source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+	assert.Equal(t, 1, requests)
+
+	// the token is refreshed when it is about to expire
+	now = now.Add(57 * time.Minute)
+	token, err = source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", token)
+	assert.Equal(t, 2, requests)
+}
+
+func TestAppTokenSourceEnterprise(t *testing.T) {
+	_, err := newAppTokenSource(ProviderOptions{AppID: 1234})
+	require.ErrorContains(t, err, "installation id and private key path are required")
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "app.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}), 0o600))
+
+	source, err := newAppTokenSource(ProviderOptions{
+		Hostname:          "github.example.com",
+		AppID:             1234,
+		InstallationID:    42,
+		AppPrivateKeyPath: keyPath,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.example.com/api/v3", source.baseURL)
+	assert.NotZero(t, source.httpClient.Timeout)
+}
+
+func TestAppTransport(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	var requests int
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"token":      fmt.Sprintf("token-%d", requests),
+			"expires_at": now.Add(time.Hour),
+		})
+	}))
+	defer tokenServer.Close()
+
+	var authorization []string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+	}))
+	defer apiServer.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	source := &appTokenSource{
+		appID:          1234,
+		installationID: 42,
+		key:            key,
+		baseURL:        tokenServer.URL,
+		httpClient:     tokenServer.Client(),
+		now:            func() time.Time { return now },
+	}
+	client := &http.Client{Transport: source.transport(nil)}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(apiServer.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// the token expires during the bootstrap
+	now = now.Add(time.Hour)
+	resp, err := client.Get(apiServer.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{"Bearer token-1", "Bearer token-1", "Bearer token-2"}, authorization)
+}
@@ -6,6 +6,7 @@ package provider
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/fluxcd/go-git-providers/gitea"
 	"github.com/fluxcd/go-git-providers/github"
@@ -18,7 +19,7 @@ import (
 
 var (
 	// providers is a map of provider names to factory functions.
-	// It is populated by calls to register.
+	// It is populated by calls to register and RegisterProvider.
 	providers providerMap
 )
 
@@ -28,6 +29,7 @@ func init() {
 	providers.register(env.ProviderGithub, githubProviderFunc)
 	providers.register(env.ProviderGitea, giteaProviderFunc)
 	providers.register(env.ProviderGitlab, gitlabProviderFunc)
+	providers.register(env.ProviderAzureDevOps, azureDevOpsProviderFunc)
 }
 
 // ProviderOptions contains the options for the provider
@@ -38,6 +40,12 @@ type ProviderOptions struct {
 	Username           string
 	TokenType          string
 	DestructiveActions bool
+	// AppID is the ID of the GitHub App to authenticate as instead of using Token.
+	AppID int64
+	// InstallationID is the ID of the GitHub App installation.
+	InstallationID int64
+	// AppPrivateKeyPath is the path to the PEM encoded private key of the GitHub App.
+	AppPrivateKeyPath string
 }
 
 // GitProvider is a provider for git repositories
@@ -56,20 +64,52 @@ func (g *GitProvider) Build(opts ProviderOptions) (gitprovider.Client, error) {
 	return nil, fmt.Errorf("provider %s not supported", opts.Provider)
 }
 
+// RegisterProvider registers an additional provider under the given name.
+// A provider registered with an existing name replaces the previous one.
+func RegisterProvider(name string, factory FactoryFunc) {
+	providers.register(name, factory)
+}
+
+// List returns the names of all registered providers in alphabetical order.
+func List() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // providerMap is a map of provider names to factory functions
-type providerMap map[string]factoryFunc
+type providerMap map[string]FactoryFunc
 
-// factoryFunc is a factory function that creates a new gitprovider.Client
-type factoryFunc func(opts ProviderOptions) (gitprovider.Client, error)
+// FactoryFunc is a factory function that creates a new gitprovider.Client
+type FactoryFunc func(opts ProviderOptions) (gitprovider.Client, error)
 
 // register registers a new provider
-func (m providerMap) register(name string, provider factoryFunc) {
+func (m providerMap) register(name string, provider FactoryFunc) {
 	m[name] = provider
 }
 
 // githubProviderFunc returns a new gitprovider.Client for github
+// If a GitHub App is configured, its installation token is used instead of the configured token.
+// The installation token is refreshed before it expires.
 func githubProviderFunc(opts ProviderOptions) (gitprovider.Client, error) {
 	o := makeProviderOpts(opts)
+	if opts.AppID != 0 {
+		source, err := githubAppTokenSource(opts)
+		if err != nil {
+			return nil, err
+		}
+		o = []gitprovider.ClientOption{
+			gitprovider.WithPreChainTransportHook(source.transport),
+			gitprovider.WithDestructiveAPICalls(opts.DestructiveActions),
+		}
+		if opts.Hostname != "" {
+			o = append(o, gitprovider.WithDomain(opts.Hostname))
+		}
+	}
+
 	client, err := github.NewClient(o...)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+)
+
+// sourceControllerName is the name of the Flux source-controller Deployment.
+const sourceControllerName = "source-controller"
+
+// WithFluxSourceControllerImagePullPolicy sets the imagePullPolicy of the Flux source-controller,
+// e.g. to Always in environments where image tags are mutable.
+func WithFluxSourceControllerImagePullPolicy(policy corev1.PullPolicy) Option {
+	return func(o *options) {
+		o.fluxSourceControllerPullPolicy = policy
+	}
+}
+
+// withFluxSourceControllerImagePullPolicy sets the imagePullPolicy of the source-controller Deployment.
+func withFluxSourceControllerImagePullPolicy(policy corev1.PullPolicy) fluxOption {
+	return func(o *fluxOptions) {
+		o.sourceControllerPullPolicy = policy
+	}
+}
+
+// sourceControllerPullPolicyPatch returns a JSON6902 patch setting the imagePullPolicy
+// of the source-controller container, or nil if no policy is set.
+func (f *fluxInstall) sourceControllerPullPolicyPatch() *kustypes.Patch {
+	if f.sourceControllerPullPolicy == "" {
+		return nil
+	}
+
+	return &kustypes.Patch{
+		Patch: fmt.Sprintf(`[{"op": "add", "path": "/spec/template/spec/containers/0/imagePullPolicy", "value": %q}]`, f.sourceControllerPullPolicy),
+		Target: &kustypes.Selector{
+			ResId: resid.ResId{
+				Gvk:  resid.Gvk{Group: "apps", Kind: "Deployment"},
+				Name: sourceControllerName,
+			},
+		},
+	}
+}
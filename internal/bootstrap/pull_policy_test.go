@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	cfd "github.com/open-component-model/ocm-controller/pkg/configdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var fluxControllers = []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: source-controller
+  namespace: flux-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: ghcr.io/fluxcd/source-controller:v1.1.0
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: kustomize-controller
+  namespace: flux-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: ghcr.io/fluxcd/kustomize-controller:v1.1.0
+`)
+
+func TestFluxSourceControllerImagePullPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   corev1.PullPolicy
+		expected map[string]string
+	}{
+		{
+			name:   "source-controller only",
+			policy: corev1.PullAlways,
+			expected: map[string]string{
+				"source-controller":    "Always",
+				"kustomize-controller": "",
+			},
+		},
+		{
+			name: "not set",
+			expected: map[string]string{
+				"source-controller":    "",
+				"kustomize-controller": "",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &Bootstrap{}
+			WithFluxSourceControllerImagePullPolicy(tc.policy)(&b.options)
+			opts, fopts := b.newFluxOptions(t.TempDir(), nil)
+			for _, o := range fopts {
+				o(opts)
+			}
+
+			f := &fluxInstall{fluxOptions: opts}
+			kfile, kus, err := f.generateKustomization(bytes.NewReader(fluxControllers))
+			require.NoError(t, err)
+			res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+			require.NoError(t, err)
+
+			objects, err := kubeutils.YamlToUnstructructured(res)
+			require.NoError(t, err)
+
+			policies := make(map[string]string)
+			for _, obj := range objects {
+				containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+				require.NoError(t, err)
+				require.Len(t, containers, 1)
+				policy, _, err := unstructured.NestedString(containers[0].(map[string]any), "imagePullPolicy")
+				require.NoError(t, err)
+				policies[obj.GetName()] = policy
+			}
+			assert.Equal(t, tc.expected, policies)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/mpas/internal/printer"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/utils"
+)
+
+const (
+	// bootstrapBranchPrefix is the prefix of the branches the bootstrap commit is proposed on.
+	bootstrapBranchPrefix = "mpas/bootstrap/"
+	// bootstrapPullRequestTitle is the title of the pull request proposing the bootstrap commit.
+	bootstrapPullRequestTitle = "Bootstrap MPAS components"
+)
+
+// CreateInitialPullRequest proposes the component manifests of the bootstrap as a pull request
+// instead of pushing them to the default branch, which may be protected. The manifests, the CI
+// config and the CODEOWNERS file are committed to a new mpas/bootstrap/<timestamp> branch.
+// It returns the URL of the pull request.
+func (b *Bootstrap) CreateInitialPullRequest(ctx context.Context) (string, error) {
+	if b.repository == nil {
+		defer b.releaseBootstrapLockOnExit(ctx)
+		if err := b.inSpinner(fmt.Sprintf("Preparing Management repository %s",
+			printer.BoldBlue(b.repositoryName)), func() error {
+			return b.prepareManagementRepository(ctx)
+		}); err != nil {
+			return "", fmt.Errorf("failed to prepare management repository: %w", err)
+		}
+	}
+
+	octx := om.DefaultContext()
+	if _, err := utils.Configure(octx, ""); err != nil {
+		return "", fmt.Errorf("failed to configure ocm context: %w", err)
+	}
+	octx.LoggingContext().SetDefaultLevel(1)
+
+	ociRepo, err := b.makeOCIRepositoryWithFallback(ctx, octx)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch bootstrap component: %w", err)
+	}
+	defer ociRepo.Close()
+
+	var refs map[string]compdesc.ComponentReference
+	if b.enforceLock {
+		refs, err = b.readLockedReferences(ctx, ociRepo)
+	} else {
+		refs, err = b.fetchBootstrapComponentReferences(ociRepo)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch bootstrap component references: %w", err)
+	}
+
+	files := make([]gitprovider.CommitFile, 0, len(refs))
+	for _, comp := range getOrderedKeys(refs) {
+		data, err := b.generateComponentManifest(ctx, ociRepo, comp, refs[comp])
+		if err != nil {
+			return "", fmt.Errorf("failed to generate %s manifests: %w", comp, err)
+		}
+
+		path, err := b.componentManifestPath(comp)
+		if err != nil {
+			return "", err
+		}
+
+		content := string(data)
+		files = append(files, gitprovider.CommitFile{Path: &path, Content: &content})
+	}
+
+	configFiles, err := b.managementRepositoryConfigFiles()
+	if err != nil {
+		return "", err
+	}
+	files = append(files, configFiles...)
+
+	branch := bootstrapBranchPrefix + time.Now().UTC().Format("20060102150405")
+
+	return b.proposeFiles(ctx, branch, refs, files)
+}
+
+// proposeFiles commits the files to a new branch created from the head of the default branch
+// and opens a pull request into the default branch.
+func (b *Bootstrap) proposeFiles(ctx context.Context, branch string, refs map[string]compdesc.ComponentReference, files []gitprovider.CommitFile) (string, error) {
+	commits, err := b.repository.Commits().ListPage(ctx, b.defaultBranch, 1, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to get head of branch %s: %w", b.defaultBranch, err)
+	}
+	if len(commits) == 0 {
+		return "", fmt.Errorf("branch %s has no commits", b.defaultBranch)
+	}
+
+	if err := b.repository.Branches().Create(ctx, branch, commits[0].Get().Sha); err != nil {
+		return "", fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+
+	commitMsg := bootstrapPullRequestTitle
+	if b.commitMessageAppendix != "" {
+		commitMsg = commitMsg + "\n\n" + b.commitMessageAppendix
+	}
+	if _, err := b.repository.Commits().Create(ctx, branch, commitMsg, files); err != nil {
+		return "", fmt.Errorf("failed to commit manifests to branch %s: %w", branch, err)
+	}
+
+	pr, err := b.repository.PullRequests().Create(ctx, bootstrapPullRequestTitle, branch, b.defaultBranch, pullRequestDescription(refs))
+	if err != nil {
+		return "", fmt.Errorf("failed to create pull request: %w", err)
+	}
+
+	return pr.Get().WebURL, nil
+}
+
+// pullRequestDescription lists the proposed component versions.
+func pullRequestDescription(refs map[string]compdesc.ComponentReference) string {
+	var sb strings.Builder
+	sb.WriteString("This pull request adds the manifests of the following components:\n\n")
+	for _, comp := range getOrderedKeys(refs) {
+		fmt.Fprintf(&sb, "- %s %s\n", comp, refs[comp].Version)
+	}
+	return sb.String()
+}
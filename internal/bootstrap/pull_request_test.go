@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposeFiles(t *testing.T) {
+	commitClient := &mockCommitClient{commit: &mockCommit{sha: "abc123"}}
+	branchClient := &mockBranchClient{}
+	prClient := &mockPullRequestClient{url: "https://github.com/mpas/management/pull/1"}
+
+	b := &Bootstrap{
+		repository: &mockGitRepository{
+			commitClient:      commitClient,
+			branchClient:      branchClient,
+			pullRequestClient: prClient,
+		},
+		options: options{defaultBranch: "main", commitMessageAppendix: "[skip ci]"},
+	}
+
+	refs := map[string]compdesc.ComponentReference{
+		"ocm-controller": {ElementMeta: compdesc.ElementMeta{Name: "ocm-controller", Version: "v0.14.0"}},
+		"flux":           {ElementMeta: compdesc.ElementMeta{Name: "flux", Version: "v2.1.0"}},
+	}
+	path, content := "clusters/flux-system/gotk-components.yaml", "kind: Namespace\n"
+	files := []gitprovider.CommitFile{{Path: &path, Content: &content}}
+
+	url, err := b.proposeFiles(context.Background(), "mpas/bootstrap/20230601100000", refs, files)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/mpas/management/pull/1", url)
+
+	assert.Equal(t, map[string]string{"mpas/bootstrap/20230601100000": "abc123"}, branchClient.created)
+	require.Len(t, commitClient.calledWidth, 1)
+	assert.Equal(t, []any{"mpas/bootstrap/20230601100000", "Bootstrap MPAS components\n\n[skip ci]", files}, commitClient.calledWidth[0])
+
+	require.Len(t, prClient.calledWidth, 1)
+	assert.Equal(t, []any{
+		"Bootstrap MPAS components",
+		"mpas/bootstrap/20230601100000",
+		"main",
+		"This pull request adds the manifests of the following components:\n\n- flux v2.1.0\n- ocm-controller v0.14.0\n",
+	}, prClient.calledWidth[0])
+}
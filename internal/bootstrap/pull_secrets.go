@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// componentOption is a function that sets an option on the component install
+type componentOption func(*componentOptions)
+
+// withImagePullSecrets sets the image pull secrets added to all Deployments of the component.
+func withImagePullSecrets(secretNames []string) componentOption {
+	return func(o *componentOptions) {
+		o.imagePullSecrets = secretNames
+	}
+}
+
+// WithImagePullSecrets sets the image pull secrets added to the Deployments of the installed components.
+func WithImagePullSecrets(secretNames []string) Option {
+	return func(o *options) {
+		o.imagePullSecrets = secretNames
+	}
+}
+
+// jsonPatchOperation is a JSON6902 patch operation.
+type jsonPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+}
+
+// patchImagePullSecrets appends the given image pull secrets to all Deployments using JSON6902 patches.
+// Secrets already referenced by a Deployment are not added again.
+func patchImagePullSecrets(content []byte, secretNames []string) ([]byte, error) {
+	if len(secretNames) == 0 {
+		return content, nil
+	}
+
+	objects, err := kubeutils.YamlToUnstructructured(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert yaml to unstructured: %w", err)
+	}
+
+	for i, obj := range objects {
+		if obj.GetKind() != "Deployment" {
+			continue
+		}
+
+		ops, err := imagePullSecretsPatch(obj, secretNames)
+		if err != nil {
+			return nil, err
+		}
+		if len(ops) == 0 {
+			continue
+		}
+
+		patched, err := applyJSONPatch(obj, ops)
+		if err != nil {
+			return nil, fmt.Errorf("failed to patch deployment %s: %w", obj.GetName(), err)
+		}
+		objects[i] = patched
+	}
+
+	return kubeutils.UnstructuredToYaml(objects)
+}
+
+// imagePullSecretsPatch returns the JSON6902 operations appending the missing secrets to the Deployment.
+func imagePullSecretsPatch(obj *unstructured.Unstructured, secretNames []string) ([]jsonPatchOperation, error) {
+	const path = "/spec/template/spec/imagePullSecrets"
+
+	existing, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "imagePullSecrets")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image pull secrets of deployment %s: %w", obj.GetName(), err)
+	}
+
+	var ops []jsonPatchOperation
+	if !found {
+		ops = append(ops, jsonPatchOperation{Op: "add", Path: path, Value: []any{}})
+	}
+
+	referenced := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		if ref, ok := e.(map[string]any); ok {
+			if name, ok := ref["name"].(string); ok {
+				referenced[name] = true
+			}
+		}
+	}
+
+	for _, name := range secretNames {
+		if referenced[name] {
+			continue
+		}
+		referenced[name] = true
+		ops = append(ops, jsonPatchOperation{Op: "add", Path: path + "/-", Value: map[string]string{"name": name}})
+	}
+
+	return ops, nil
+}
+
+func applyJSONPatch(obj *unstructured.Unstructured, ops []jsonPatchOperation) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := jsonpatch.DecodePatch(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := patch.Apply(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &unstructured.Unstructured{}
+	if err := result.UnmarshalJSON(patched); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
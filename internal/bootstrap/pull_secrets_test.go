@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var deploymentWithPullSecret = []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: git-controller
+  namespace: ocm-system
+spec:
+  template:
+    spec:
+      imagePullSecrets:
+      - name: existing
+      containers:
+      - name: manager
+        image: ghcr.io/user/git-controller:v1.0.0
+`)
+
+func TestPatchImagePullSecrets(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  []byte
+		secrets  []string
+		expected []corev1.LocalObjectReference
+	}{
+		{
+			name:     "deployment without image pull secrets",
+			content:  kustomizedDeployment,
+			secrets:  []string{"registry-a", "registry-b"},
+			expected: []corev1.LocalObjectReference{{Name: "registry-a"}, {Name: "registry-b"}},
+		},
+		{
+			name:     "secrets are appended",
+			content:  deploymentWithPullSecret,
+			secrets:  []string{"existing", "registry-a"},
+			expected: []corev1.LocalObjectReference{{Name: "existing"}, {Name: "registry-a"}},
+		},
+		{
+			name:    "no secrets",
+			content: kustomizedDeployment,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &componentOptions{}
+			withImagePullSecrets(tc.secrets)(c)
+
+			data, err := patchImagePullSecrets(tc.content, c.imagePullSecrets)
+			require.NoError(t, err)
+
+			deployment := &appsv1.Deployment{}
+			require.NoError(t, yaml.Unmarshal(data, deployment))
+			assert.Equal(t, tc.expected, deployment.Spec.Template.Spec.ImagePullSecrets)
+			assert.Equal(t, "ghcr.io/user/git-controller:v1.0.0", deployment.Spec.Template.Spec.Containers[0].Image)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+
+	notificationv1 "github.com/fluxcd/notification-controller/api/v1"
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/open-component-model/mpas/internal/env"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// receiversDir is the directory below the target path holding the flux Receivers.
+	receiversDir = "receivers"
+	// receiversFileName is the name of the file holding the flux Receivers.
+	receiversFileName = "receivers.yaml"
+	// receiverTokenKey is the key of the webhook token in the Receiver secret.
+	receiverTokenKey = "token"
+)
+
+// FluxReceiver is a flux notification Receiver triggering the reconciliation of
+// the given resources when its webhook is called.
+type FluxReceiver struct {
+	// Name is the name of the Receiver.
+	Name string `json:"name"`
+	// Type is the type of the webhook sender, e.g. github or generic.
+	Type string `json:"type"`
+	// Secret is the token used to generate the webhook path and to validate the payloads.
+	Secret string `json:"secret"`
+	// Resources are the objects to reconcile when the webhook is called.
+	Resources []notificationv1.CrossNamespaceObjectReference `json:"resources"`
+}
+
+// WithFluxReceivers adds flux notification Receivers for webhook-driven reconciliation.
+// The webhook paths are reported when the bootstrap completes.
+func WithFluxReceivers(receivers ...FluxReceiver) Option {
+	return func(o *options) {
+		o.fluxReceivers = append(o.fluxReceivers, receivers...)
+	}
+}
+
+// withFluxReceiver adds a Receiver with the given name, type, token and resources to the sync manifests.
+func withFluxReceiver(name, receiverType, secret string, resources []notificationv1.CrossNamespaceObjectReference) fluxOption {
+	return func(o *fluxOptions) {
+		o.receivers = append(o.receivers, FluxReceiver{
+			Name:      name,
+			Type:      receiverType,
+			Secret:    secret,
+			Resources: resources,
+		})
+	}
+}
+
+// receiverSecretName returns the name of the secret holding the token of the Receiver.
+func receiverSecretName(name string) string {
+	return name + "-token"
+}
+
+// receiverWebhookPath returns the path the notification-controller serves the webhook of the Receiver under.
+// It is derived the same way as by the notification-controller.
+func receiverWebhookPath(name, namespace, token string) string {
+	digest := sha256.Sum256([]byte(token + name + namespace))
+	return fmt.Sprintf("/hook/%x", digest)
+}
+
+// reconcileReceivers creates the token secrets of the Receivers in the cluster and commits the
+// Receivers to the management repository. The tokens are not committed.
+func (f *fluxInstall) reconcileReceivers(ctx context.Context) error {
+	if len(f.receivers) == 0 {
+		return nil
+	}
+
+	receivers, err := f.generateReceivers()
+	if err != nil {
+		return err
+	}
+
+	receiversPath := path.Join(f.targetPath, receiversDir, receiversFileName)
+	if f.dryRun {
+		printDryRunPreview(f.printer, receiversPath, receivers)
+		return nil
+	}
+
+	if f.clusterOnly {
+		f.fluxLogger().Warningf("skipping %d receivers, they require the management repository", len(f.receivers))
+		return nil
+	}
+
+	for _, r := range f.receivers {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: receiverSecretName(r.Name), Namespace: f.namespace}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, f.kubeClient, secret, func() error {
+			setManagedByLabel(secret)
+			secret.Data = map[string][]byte{receiverTokenKey: []byte(r.Secret)}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to reconcile secret of receiver %s: %w", r.Name, err)
+		}
+	}
+
+	return f.commitAndPush(ctx, fmt.Sprintf("Add Flux %s receivers", f.version), map[string]io.Reader{
+		receiversPath: bytes.NewReader(receivers),
+	})
+}
+
+// generateReceivers returns the Receiver manifests.
+func (f *fluxInstall) generateReceivers() ([]byte, error) {
+	var buf bytes.Buffer
+	for _, r := range f.receivers {
+		receiver := notificationv1.Receiver{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: notificationv1.GroupVersion.String(),
+				Kind:       notificationv1.ReceiverKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      r.Name,
+				Namespace: f.namespace,
+			},
+			Spec: notificationv1.ReceiverSpec{
+				Type:      r.Type,
+				Resources: r.Resources,
+				SecretRef: meta.LocalObjectReference{
+					Name: receiverSecretName(r.Name),
+				},
+			},
+		}
+
+		data, err := yaml.Marshal(receiver)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal Receiver %s: %w", r.Name, err)
+		}
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// reportReceivers logs the webhook paths of the flux Receivers.
+func (b *Bootstrap) reportReceivers(ctx context.Context) {
+	ns := b.componentNamespace(env.FluxName, env.DefaultFluxNamespace)
+	for _, r := range b.fluxReceivers {
+		path := receiverWebhookPath(r.Name, ns, r.Secret)
+		b.log().InfoContext(ctx, fmt.Sprintf("Flux receiver %s is served at %s", r.Name, path),
+			slog.String("receiver", r.Name),
+			slog.String("path", path))
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	notificationv1 "github.com/fluxcd/notification-controller/api/v1"
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGenerateReceivers(t *testing.T) {
+	resources := []notificationv1.CrossNamespaceObjectReference{
+		{Kind: kustomizev1.KustomizationKind, Name: "flux-system"},
+	}
+
+	opts := &fluxOptions{namespace: "flux-system"}
+	withFluxReceiver("github-receiver", notificationv1.GitHubReceiver, "s3cr3t", resources)(opts)
+	f := &fluxInstall{fluxOptions: opts}
+
+	data, err := f.generateReceivers()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s3cr3t")
+
+	receiver := &notificationv1.Receiver{}
+	require.NoError(t, yaml.UnmarshalStrict(data, receiver))
+	assert.Equal(t, notificationv1.GroupVersion.String(), receiver.APIVersion)
+	assert.Equal(t, notificationv1.ReceiverKind, receiver.Kind)
+	assert.Equal(t, "github-receiver", receiver.Name)
+	assert.Equal(t, "flux-system", receiver.Namespace)
+	assert.Equal(t, notificationv1.ReceiverSpec{
+		Type:      notificationv1.GitHubReceiver,
+		Resources: resources,
+		SecretRef: meta.LocalObjectReference{Name: "github-receiver-token"},
+	}, receiver.Spec)
+}
+
+func TestReconcileReceiversDryRun(t *testing.T) {
+	out := &bytes.Buffer{}
+	p, err := printer.Newprinter(out)
+	require.NoError(t, err)
+
+	opts := &fluxOptions{
+		targetPath: ".",
+		namespace:  "flux-system",
+		dryRun:     true,
+		printer:    p,
+	}
+	f := &fluxInstall{fluxOptions: opts}
+	require.NoError(t, f.reconcileReceivers(context.Background()))
+	assert.Empty(t, out.String())
+
+	withFluxReceiver("generic", notificationv1.GenericReceiver, "token", nil)(opts)
+	require.NoError(t, f.reconcileReceivers(context.Background()))
+	assert.Contains(t, out.String(), "--- [dry-run] receivers/receivers.yaml ---")
+	assert.Contains(t, out.String(), "kind: Receiver")
+}
+
+func TestReportReceivers(t *testing.T) {
+	out := &bytes.Buffer{}
+	p, err := printer.Newprinter(out)
+	require.NoError(t, err)
+
+	b := &Bootstrap{options: options{printer: p}}
+	WithFluxReceivers(FluxReceiver{Name: "generic", Type: notificationv1.GenericReceiver, Secret: "token"})(&b.options)
+	b.reportReceivers(context.Background())
+
+	path := receiverWebhookPath("generic", "flux-system", "token")
+	assert.Regexp(t, "^/hook/[0-9a-f]{64}$", path)
+	assert.Contains(t, out.String(), path)
+	assert.NotEqual(t, path, receiverWebhookPath("generic", "flux-system", "other"))
+}
@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/open-component-model/mpas/internal/ocm"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	credentials "github.com/oras-project/oras-credentials-go"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// WithFallbackRegistries sets the registries to use, in order, if the primary registry is unreachable.
+func WithFallbackRegistries(registries []string) Option {
+	return func(o *options) {
+		o.fallbackRegistries = registries
+	}
+}
+
+// probeRegistry checks that the bootstrap component can be listed in the given registry.
+// It is a variable to be able to stub it in tests.
+var probeRegistry = defaultProbeRegistry
+
+func defaultProbeRegistry(ctx context.Context, url, dockerConfigPath string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo, err := ocm.MakeRepositoryWithDockerConfig(om.DefaultContext(), url, dockerConfigPath)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	return listBootstrapComponentVersions(repo)
+}
+
+// makeOCIRepositoryWithFallback returns a repository for the first reachable registry, trying the
+// primary registry first and then the fallback registries. The chosen registry is cached so
+// subsequent calls do not probe again.
+func (b *Bootstrap) makeOCIRepositoryWithFallback(ctx context.Context, octx om.Context) (om.Repository, error) {
+	registry, err := b.selectRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ocm.MakeRepositoryWithDockerConfig(octx, registry, b.dockerConfigPath)
+}
+
+// selectRegistry returns the first reachable registry and caches it. It is safe for concurrent use,
+// e.g. by the parallel pre-flight checks, which then wait for a single probe.
+func (b *Bootstrap) selectRegistry(ctx context.Context) (string, error) {
+	b.registryMu.Lock()
+	defer b.registryMu.Unlock()
+
+	if b.selectedRegistry != "" {
+		return b.selectedRegistry, nil
+	}
+
+	var errs []error
+	for _, registry := range append([]string{b.registry}, b.fallbackRegistries...) {
+		if err := probeRegistry(ctx, registry, b.dockerConfigPath); err != nil {
+			errs = append(errs, fmt.Errorf("registry %s: %w", registry, err))
+			continue
+		}
+
+		if registry != b.registry {
+			b.log().WarnContext(ctx, fmt.Sprintf("Registry %s is unreachable, using fallback registry %s", b.registry, registry),
+				slog.String("registry", registry))
+		}
+		b.selectedRegistry = registry
+		return registry, nil
+	}
+
+	return "", fmt.Errorf("no reachable registry: %w", errors.Join(errs...))
+}
+
+// activeRegistry returns the registry chosen by makeOCIRepositoryWithFallback or the primary registry.
+func (b *Bootstrap) activeRegistry() string {
+	b.registryMu.Lock()
+	defer b.registryMu.Unlock()
+
+	if b.selectedRegistry != "" {
+		return b.selectedRegistry
+	}
+
+	return b.registry
+}
+
+// CheckRegistryConnectivity pings the OCI distribution API of the configured registry.
+// A 401 response is accepted as the registry is reachable but requires authentication
+// for the base endpoint; the credentials of the docker config are sent if available.
+func (b *Bootstrap) CheckRegistryConnectivity(ctx context.Context) error {
+	u, err := parseRegistryURL(b.registry)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s://%s/v2/", u.Scheme, u.Host)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	cred, err := registryCredentials(ctx, u.Host, b.dockerConfigPath)
+	if err != nil {
+		return err
+	}
+	if cred.Username != "" {
+		req.SetBasicAuth(cred.Username, cred.Password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("registry %s is unreachable: %w", u.Host, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusUnauthorized:
+		return nil
+	default:
+		return fmt.Errorf("registry %s is unreachable: %s returned %s", u.Host, endpoint, resp.Status)
+	}
+}
+
+// registryCredentials returns the credentials of the given registry host from the docker config
+// at dockerConfigPath, or the default docker config if it is empty.
+func registryCredentials(ctx context.Context, host, dockerConfigPath string) (auth.Credential, error) {
+	var (
+		store credentials.Store
+		err   error
+	)
+	if dockerConfigPath != "" {
+		store, err = credentials.NewStore(dockerConfigPath, credentials.StoreOptions{})
+	} else {
+		store, err = credentials.NewStoreFromDocker(credentials.StoreOptions{})
+	}
+	if err != nil {
+		return auth.EmptyCredential, fmt.Errorf("failed to load docker config: %w", err)
+	}
+
+	cred, err := store.Get(ctx, host)
+	if err != nil {
+		return auth.EmptyCredential, fmt.Errorf("failed to get credentials of registry %s: %w", host, err)
+	}
+
+	return cred, nil
+}
+
+// parseRegistryURL parses the registry, defaulting to https if it has no scheme.
+func parseRegistryURL(registry string) (*url.URL, error) {
+	registryURL := registry
+	if !strings.HasPrefix(registryURL, "https://") && !strings.HasPrefix(registryURL, "http://") {
+		registryURL = "https://" + registryURL
+	}
+	u, err := url.Parse(registryURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry %q: %w", registry, err)
+	}
+	return u, nil
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Authentication types reported by GetRegistryCredentials.
+const (
+	// AuthTypeBasic is a username and password from the docker config.
+	AuthTypeBasic = "basic"
+	// AuthTypeIdentityToken is an identity token from the docker config.
+	AuthTypeIdentityToken = "identity-token"
+	// AuthTypeWorkloadIdentity is a projected workload identity token.
+	AuthTypeWorkloadIdentity = "workload-identity"
+	// AuthTypeAnonymous is used if no credentials are found.
+	AuthTypeAnonymous = "anonymous"
+
+	// maskedPassword replaces the password in RegistryCredentials.
+	maskedPassword = "********"
+)
+
+// workloadIdentityTokenFileEnvVars are the environment variables pointing at the projected
+// workload identity token of EKS and AKS.
+var workloadIdentityTokenFileEnvVars = []string{"AWS_WEB_IDENTITY_TOKEN_FILE", "AZURE_FEDERATED_TOKEN_FILE"}
+
+// RegistryCredentials describes the credentials used for the registry. The password is masked.
+type RegistryCredentials struct {
+	// Registry is the host of the registry.
+	Registry string
+	// Username is the username of the docker config or the subject of the workload identity token.
+	Username string
+	// Password is masked if a password or token is used, otherwise it is empty.
+	Password string
+	// AuthType is one of basic, identity-token, workload-identity or anonymous.
+	AuthType string
+}
+
+// GetRegistryCredentials returns the credentials used for the configured registry.
+// The docker config takes precedence over a workload identity token.
+func (b *Bootstrap) GetRegistryCredentials(ctx context.Context) (*RegistryCredentials, error) {
+	u, err := parseRegistryURL(b.registry)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := registryCredentials(ctx, u.Host, b.dockerConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := &RegistryCredentials{Registry: u.Host}
+	switch {
+	case cred.RefreshToken != "":
+		creds.Username = cred.Username
+		creds.Password = maskedPassword
+		creds.AuthType = AuthTypeIdentityToken
+	case cred.Username != "" || cred.Password != "":
+		creds.Username = cred.Username
+		creds.Password = maskedPassword
+		creds.AuthType = AuthTypeBasic
+	default:
+		subject, ok, err := workloadIdentitySubject()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			creds.AuthType = AuthTypeAnonymous
+			return creds, nil
+		}
+		creds.Username = subject
+		creds.Password = maskedPassword
+		creds.AuthType = AuthTypeWorkloadIdentity
+	}
+
+	return creds, nil
+}
+
+// workloadIdentitySubject returns the subject of the projected workload identity token.
+// It returns false if no token file is configured.
+func workloadIdentitySubject() (string, bool, error) {
+	for _, envVar := range workloadIdentityTokenFileEnvVars {
+		path := os.Getenv(envVar)
+		if path == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read workload identity token %s: %w", path, err)
+		}
+
+		// the token is only inspected, it is verified by the identity provider
+		claims := &jwt.RegisteredClaims{}
+		if _, _, err := jwt.NewParser().ParseUnverified(strings.TrimSpace(string(data)), claims); err != nil {
+			return "", false, fmt.Errorf("failed to parse workload identity token %s: %w", path, err)
+		}
+
+		return claims.Subject, true, nil
+	}
+
+	return "", false, nil
+}
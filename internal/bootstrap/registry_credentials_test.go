@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRegistryCredentials(t *testing.T) {
+	dir := t.TempDir()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject: "system:serviceaccount:ocm-system:ocm-controller",
+	}).SignedString([]byte("key"))
+	require.NoError(t, err)
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte(token+"\n"), 0o600))
+
+	auth := base64.StdEncoding.EncodeToString([]byte("mpas:s3cr3t"))
+	dockerConfig := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(dockerConfig, []byte(fmt.Sprintf(`{"auths": {
+  "ghcr.io": {"auth": %q},
+  "registry.example.com": {"identitytoken": "t0k3n"}
+}}`, auth)), 0o600))
+
+	testCases := []struct {
+		name      string
+		registry  string
+		tokenFile string
+		expected  *RegistryCredentials
+	}{
+		{
+			name:     "basic auth",
+			registry: "ghcr.io/open-component-model/mpas",
+			expected: &RegistryCredentials{Registry: "ghcr.io", Username: "mpas", Password: "********", AuthType: AuthTypeBasic},
+		},
+		{
+			name:     "identity token",
+			registry: "https://registry.example.com/mpas",
+			expected: &RegistryCredentials{Registry: "registry.example.com", Password: "********", AuthType: AuthTypeIdentityToken},
+		},
+		{
+			name:      "docker config takes precedence",
+			registry:  "ghcr.io/open-component-model/mpas",
+			tokenFile: tokenFile,
+			expected:  &RegistryCredentials{Registry: "ghcr.io", Username: "mpas", Password: "********", AuthType: AuthTypeBasic},
+		},
+		{
+			name:      "workload identity",
+			registry:  "123456789012.dkr.ecr.eu-west-1.amazonaws.com/mpas",
+			tokenFile: tokenFile,
+			expected: &RegistryCredentials{
+				Registry: "123456789012.dkr.ecr.eu-west-1.amazonaws.com",
+				Username: "system:serviceaccount:ocm-system:ocm-controller",
+				Password: "********",
+				AuthType: AuthTypeWorkloadIdentity,
+			},
+		},
+		{
+			name:     "anonymous",
+			registry: "quay.io/mpas",
+			expected: &RegistryCredentials{Registry: "quay.io", AuthType: AuthTypeAnonymous},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", tc.tokenFile)
+			t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+
+			b := &Bootstrap{options: options{registry: tc.registry, dockerConfigPath: dockerConfig}}
+			creds, err := b.GetRegistryCredentials(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, creds)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/open-component-model/ocm/pkg/contexts/datacontext"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeOCIRepositoryWithFallback(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer secondary.Close()
+
+	var probed []string
+	probeRegistry = func(ctx context.Context, url, _ string) error {
+		probed = append(probed, url)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/v2/", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		}
+		return nil
+	}
+	defer func() {
+		probeRegistry = defaultProbeRegistry
+	}()
+
+	out := &bytes.Buffer{}
+	p, err := printer.Newprinter(out)
+	require.NoError(t, err)
+
+	b := &Bootstrap{options: options{
+		registry:           primary.URL,
+		fallbackRegistries: []string{secondary.URL},
+		printer:            p,
+	}}
+
+	octx := om.New(datacontext.MODE_SHARED)
+	repo, err := b.makeOCIRepositoryWithFallback(context.Background(), octx)
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+	assert.Equal(t, secondary.URL, b.activeRegistry())
+	assert.Equal(t, []string{primary.URL, secondary.URL}, probed)
+	assert.Contains(t, out.String(), "using fallback registry")
+
+	// the selected registry is cached
+	repo, err = b.makeOCIRepositoryWithFallback(context.Background(), octx)
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+	assert.Len(t, probed, 2)
+}
+
+func TestMakeOCIRepositoryWithFallbackUnreachable(t *testing.T) {
+	probeRegistry = func(_ context.Context, _, _ string) error {
+		return fmt.Errorf("connection refused")
+	}
+	defer func() {
+		probeRegistry = defaultProbeRegistry
+	}()
+
+	b := &Bootstrap{options: options{
+		registry:           "ghcr.io/primary",
+		fallbackRegistries: []string{"quay.io/secondary"},
+	}}
+
+	_, err := b.makeOCIRepositoryWithFallback(context.Background(), om.New(datacontext.MODE_SHARED))
+	require.ErrorContains(t, err, "registry ghcr.io/primary: connection refused")
+	require.ErrorContains(t, err, "registry quay.io/secondary: connection refused")
+	assert.Equal(t, "ghcr.io/primary", b.activeRegistry())
+}
+
+func TestSelectRegistryConcurrent(t *testing.T) {
+	var probes atomic.Int32
+	probeRegistry = func(_ context.Context, _, _ string) error {
+		probes.Add(1)
+		return nil
+	}
+	defer func() {
+		probeRegistry = defaultProbeRegistry
+	}()
+
+	b := &Bootstrap{options: options{registry: "ghcr.io/primary"}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			registry, err := b.selectRegistry(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "ghcr.io/primary", registry)
+			assert.Equal(t, "ghcr.io/primary", b.activeRegistry())
+		}()
+	}
+	wg.Wait()
+
+	// the concurrent callers wait for a single probe
+	assert.Equal(t, int32(1), probes.Load())
+}
+
+func TestCheckRegistryConnectivity(t *testing.T) {
+	dockerConfig := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(dockerConfig, []byte(`{"auths": {}}`), 0o600))
+
+	testCases := []struct {
+		name        string
+		status      int
+		expectedErr string
+	}{
+		{
+			name:   "ok",
+			status: http.StatusOK,
+		},
+		{
+			name:   "authentication required",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:        "unavailable",
+			status:      http.StatusServiceUnavailable,
+			expectedErr: "503 Service Unavailable",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var path string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			b := &Bootstrap{options: options{
+				registry:         server.URL + "/mpas",
+				dockerConfigPath: dockerConfig,
+			}}
+
+			err := b.CheckRegistryConnectivity(context.Background())
+			assert.Equal(t, "/v2/", path)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
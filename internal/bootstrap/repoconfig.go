@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/google/go-github/v52/github"
+	"github.com/open-component-model/mpas/internal/env"
+	"k8s.io/utils/ptr"
+)
+
+// codeOwnersFileName is the path of the CODEOWNERS file in the management repository.
+const codeOwnersFileName = ".github/CODEOWNERS"
+
+// BranchProtection configures the protection of the default branch of the management repository.
+type BranchProtection struct {
+	// RequirePullRequest requires changes to the default branch to be merged with a pull request.
+	RequirePullRequest bool `json:"requirePullRequest,omitempty"`
+	// RequiredApprovals is the number of approvals a pull request requires before it can be merged.
+	RequiredApprovals int `json:"requiredApprovals,omitempty"`
+	// RequireCodeOwnerReviews requires the approval of a code owner. It is set if code owners are configured.
+	RequireCodeOwnerReviews bool `json:"-"`
+}
+
+// BranchProtectionSetter can be implemented by the raw client of custom providers
+// to support protecting the default branch of the management repository.
+type BranchProtectionSetter interface {
+	SetBranchProtection(ctx context.Context, owner, repository, branch string, protection BranchProtection) error
+}
+
+// WithCodeOwners commits a CODEOWNERS file making the given users or teams, e.g. @org/platform,
+// the owners of all files of the management repository.
+func WithCodeOwners(owners []string) Option {
+	return func(o *options) {
+		o.codeOwners = owners
+	}
+}
+
+// WithBranchProtection protects the default branch of the management repository once the bootstrap
+// committed all components. Changes then require a pull request with requiredApprovals approvals if requirePR is set.
+func WithBranchProtection(requirePR bool, requiredApprovals int) Option {
+	return func(o *options) {
+		o.branchProtection = &BranchProtection{
+			RequirePullRequest: requirePR,
+			RequiredApprovals:  requiredApprovals,
+		}
+	}
+}
+
+// reconcileCodeOwners commits the CODEOWNERS file to the default branch of repo.
+func (b *Bootstrap) reconcileCodeOwners(ctx context.Context, repo gitprovider.UserRepository, owners []string) error {
+	if len(owners) == 0 {
+		return nil
+	}
+
+	if b.dryRun {
+		printDryRunPreview(b.printer, codeOwnersFileName, []byte(generateCodeOwners(owners)))
+		return nil
+	}
+
+	files := []gitprovider.CommitFile{
+		{
+			Path:    ptr.To(codeOwnersFileName),
+			Content: ptr.To(generateCodeOwners(owners)),
+		},
+	}
+	if _, err := repo.Commits().Create(ctx, b.defaultBranch, "Add CODEOWNERS", files); err != nil {
+		return fmt.Errorf("failed to commit %s: %w", codeOwnersFileName, err)
+	}
+
+	return nil
+}
+
+// managementRepositoryConfigFiles returns the CI config and CODEOWNERS files reconcileManagementRepository
+// commits to the default branch, so that they can be proposed in the bootstrap pull request instead.
+func (b *Bootstrap) managementRepositoryConfigFiles() ([]gitprovider.CommitFile, error) {
+	var files []gitprovider.CommitFile
+	providerID := string(b.providerClient.ProviderID())
+
+	if b.githubActions != nil && providerID == env.ProviderGithub {
+		data, err := generateGitHubActions(*b.githubActions, b.defaultBranch)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, gitprovider.CommitFile{Path: ptr.To(githubActionsFileName), Content: ptr.To(string(data))})
+	}
+
+	if b.gitlabCI != nil && providerID == env.ProviderGitlab {
+		data, err := generateGitLabCI(*b.gitlabCI, b.defaultBranch)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, gitprovider.CommitFile{Path: ptr.To(gitlabCIFileName), Content: ptr.To(string(data))})
+	}
+
+	if len(b.codeOwners) > 0 {
+		files = append(files, gitprovider.CommitFile{Path: ptr.To(codeOwnersFileName), Content: ptr.To(generateCodeOwners(b.codeOwners))})
+	}
+
+	return files, nil
+}
+
+// generateCodeOwners returns a CODEOWNERS file assigning all files to the owners.
+// Owners which are neither a user or team reference nor an email are prefixed with @.
+func generateCodeOwners(owners []string) string {
+	refs := make([]string, 0, len(owners))
+	for _, owner := range owners {
+		if !strings.Contains(owner, "@") {
+			owner = "@" + owner
+		}
+		refs = append(refs, owner)
+	}
+
+	return fmt.Sprintf("* %s\n", strings.Join(refs, " "))
+}
+
+// reconcileBranchProtection protects the default branch of repo.
+// A warning is printed if the provider does not support branch protection.
+func (b *Bootstrap) reconcileBranchProtection(ctx context.Context, repo gitprovider.UserRepository) error {
+	if b.branchProtection == nil {
+		return nil
+	}
+
+	protection := *b.branchProtection
+	protection.RequireCodeOwnerReviews = len(b.codeOwners) > 0
+
+	ref := repo.Repository()
+	owner, name := ref.GetIdentity(), ref.GetRepository()
+
+	var err error
+	switch raw := b.providerClient.Raw().(type) {
+	case BranchProtectionSetter:
+		err = raw.SetBranchProtection(ctx, owner, name, b.defaultBranch, protection)
+	case *github.Client:
+		_, _, err = raw.Repositories.UpdateBranchProtection(ctx, owner, name, b.defaultBranch, newGitHubProtectionRequest(protection))
+	default:
+		b.log().WarnContext(ctx, fmt.Sprintf("provider %s does not support branch protection, skipping", b.providerClient.ProviderID()))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to protect branch %s of management repository %s: %w", b.defaultBranch, ref.String(), err)
+	}
+
+	return nil
+}
+
+// newGitHubProtectionRequest converts the protection into a GitHub branch protection request.
+func newGitHubProtectionRequest(protection BranchProtection) *github.ProtectionRequest {
+	req := &github.ProtectionRequest{}
+	if protection.RequirePullRequest {
+		req.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcementRequest{
+			RequiredApprovingReviewCount: protection.RequiredApprovals,
+			RequireCodeOwnerReviews:      protection.RequireCodeOwnerReviews,
+		}
+	}
+
+	return req
+}
@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/google/go-github/v52/github"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var managementRepositoryRef = gitprovider.OrgRepositoryRef{
+	OrganizationRef: gitprovider.OrganizationRef{Domain: "github.com", Organization: "open-component-model"},
+	RepositoryName:  "mpas-management",
+}
+
+type branchProtectionRecorder struct {
+	owner      string
+	repository string
+	branch     string
+	protection BranchProtection
+}
+
+func (r *branchProtectionRecorder) SetBranchProtection(_ context.Context, owner, repository, branch string, protection BranchProtection) error {
+	r.owner, r.repository, r.branch, r.protection = owner, repository, branch, protection
+	return nil
+}
+
+func TestReconcileCodeOwners(t *testing.T) {
+	mc := &mockCommitClient{commit: &mockCommit{sha: "sha"}}
+	repo := &mockGitRepository{commitClient: mc}
+	b := &Bootstrap{options: options{defaultBranch: "main"}}
+
+	require.NoError(t, b.reconcileCodeOwners(context.Background(), repo, []string{"@open-component-model/platform", "alice", "bob@example.com"}))
+	require.Len(t, mc.calledWidth, 1)
+	assert.Equal(t, "main", mc.calledWidth[0][0])
+	files := mc.calledWidth[0][2].([]gitprovider.CommitFile)
+	require.Len(t, files, 1)
+	assert.Equal(t, ".github/CODEOWNERS", *files[0].Path)
+	assert.Equal(t, "* @open-component-model/platform @alice bob@example.com\n", *files[0].Content)
+
+	mc.calledWidth = nil
+	require.NoError(t, b.reconcileCodeOwners(context.Background(), repo, nil))
+	assert.Empty(t, mc.calledWidth)
+}
+
+func TestReconcileCodeOwnersDryRun(t *testing.T) {
+	out := &bytes.Buffer{}
+	p, err := printer.Newprinter(out)
+	require.NoError(t, err)
+
+	mc := &mockCommitClient{commit: &mockCommit{sha: "sha"}}
+	repo := &mockGitRepository{commitClient: mc}
+	b := &Bootstrap{options: options{defaultBranch: "main", dryRun: true, printer: p}}
+
+	require.NoError(t, b.reconcileCodeOwners(context.Background(), repo, []string{"alice"}))
+	assert.Empty(t, mc.calledWidth)
+	assert.Contains(t, out.String(), "--- [dry-run] .github/CODEOWNERS ---")
+	assert.Contains(t, out.String(), "* @alice")
+}
+
+func TestReconcileBranchProtection(t *testing.T) {
+	t.Run("provider supports branch protection", func(t *testing.T) {
+		recorder := &branchProtectionRecorder{}
+		b := &Bootstrap{
+			providerClient: &mockProviderClient{raw: recorder},
+			options:        options{defaultBranch: "main"},
+		}
+		WithCodeOwners([]string{"alice"})(&b.options)
+		WithBranchProtection(true, 2)(&b.options)
+
+		require.NoError(t, b.reconcileBranchProtection(context.Background(), &mockGitRepository{ref: managementRepositoryRef}))
+		assert.Equal(t, "open-component-model", recorder.owner)
+		assert.Equal(t, "mpas-management", recorder.repository)
+		assert.Equal(t, "main", recorder.branch)
+		assert.Equal(t, BranchProtection{RequirePullRequest: true, RequiredApprovals: 2, RequireCodeOwnerReviews: true}, recorder.protection)
+	})
+
+	t.Run("github", func(t *testing.T) {
+		var (
+			method, path string
+			req          github.ProtectionRequest
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, path = r.Method, r.URL.Path
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			w.Write([]byte(`{}`))
+		}))
+		defer srv.Close()
+
+		client := github.NewClient(nil)
+		baseURL, err := url.Parse(srv.URL + "/")
+		require.NoError(t, err)
+		client.BaseURL = baseURL
+
+		b := &Bootstrap{
+			providerClient: &mockProviderClient{raw: client},
+			options:        options{defaultBranch: "main"},
+		}
+		WithBranchProtection(true, 1)(&b.options)
+
+		require.NoError(t, b.reconcileBranchProtection(context.Background(), &mockGitRepository{ref: managementRepositoryRef}))
+		assert.Equal(t, http.MethodPut, method)
+		assert.Equal(t, "/repos/open-component-model/mpas-management/branches/main/protection", path)
+		require.NotNil(t, req.RequiredPullRequestReviews)
+		assert.Equal(t, 1, req.RequiredPullRequestReviews.RequiredApprovingReviewCount)
+		assert.False(t, req.RequiredPullRequestReviews.RequireCodeOwnerReviews)
+	})
+
+	t.Run("provider does not support branch protection", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		p, err := printer.Newprinter(out)
+		require.NoError(t, err)
+
+		b := &Bootstrap{
+			providerClient: &mockProviderClient{providerID: "custom"},
+			options:        options{defaultBranch: "main", printer: p},
+		}
+		WithBranchProtection(true, 1)(&b.options)
+
+		require.NoError(t, b.reconcileBranchProtection(context.Background(), &mockGitRepository{ref: managementRepositoryRef}))
+		assert.Contains(t, out.String(), "provider custom does not support branch protection")
+	})
+
+	t.Run("option not set", func(t *testing.T) {
+		b := &Bootstrap{providerClient: &mockProviderClient{raw: &branchProtectionRecorder{}}}
+		require.NoError(t, b.reconcileBranchProtection(context.Background(), &mockGitRepository{ref: managementRepositoryRef}))
+	})
+}
+
+func TestManagementRepositoryConfigFiles(t *testing.T) {
+	b := &Bootstrap{
+		providerClient: &mockProviderClient{providerID: "github"},
+		options: options{
+			defaultBranch: "main",
+			githubActions: &GHActionsSpec{Image: "ghcr.io/open-component-model/mpas:v0.1.0"},
+			gitlabCI:      &GitLabCISpec{Image: "ghcr.io/open-component-model/mpas:v0.1.0"},
+			codeOwners:    []string{"alice"},
+		},
+	}
+
+	files, err := b.managementRepositoryConfigFiles()
+	require.NoError(t, err)
+	// the GitLab CI config is only proposed for GitLab repositories
+	require.Len(t, files, 2)
+	assert.Equal(t, ".github/workflows/mpas-reconcile.yaml", *files[0].Path)
+	assert.Contains(t, *files[0].Content, "mpas reconcile")
+	assert.Equal(t, ".github/CODEOWNERS", *files[1].Path)
+	assert.Equal(t, "* @alice\n", *files[1].Content)
+
+	b.githubActions, b.codeOwners = nil, nil
+	files, err = b.managementRepositoryConfigFiles()
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
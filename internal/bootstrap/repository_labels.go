@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/google/go-github/v52/github"
+	"github.com/xanzy/go-gitlab"
+)
+
+// RepositoryTopicsSetter can be implemented by the raw client of custom providers
+// to support labelling the management repository.
+type RepositoryTopicsSetter interface {
+	SetRepositoryTopics(ctx context.Context, owner, repository string, topics []string) error
+}
+
+// SetManagementRepositoryLabels sets the given labels as topics of the management repository.
+// Labels are converted to topics of the form key-value, or key if the value is empty.
+// A warning is printed if the provider does not support repository topics.
+func (b *Bootstrap) SetManagementRepositoryLabels(ctx context.Context, labels map[string]string) error {
+	if b.repository == nil {
+		return fmt.Errorf("management repository is not set")
+	}
+
+	ref := b.repository.Repository()
+	owner, name := ref.GetIdentity(), ref.GetRepository()
+	topics := labelsToTopics(labels)
+
+	var err error
+	switch raw := b.providerClient.Raw().(type) {
+	case RepositoryTopicsSetter:
+		err = raw.SetRepositoryTopics(ctx, owner, name, topics)
+	case *github.Client:
+		_, _, err = raw.Repositories.ReplaceAllTopics(ctx, owner, name, topics)
+	case *gitlab.Client:
+		_, _, err = raw.Projects.EditProject(fmt.Sprintf("%s/%s", owner, name), &gitlab.EditProjectOptions{
+			Topics: &topics,
+		}, gitlab.WithContext(ctx))
+	case *gitea.Client:
+		_, err = raw.SetRepoTopics(owner, name, topics)
+	default:
+		b.log().WarnContext(ctx, fmt.Sprintf("provider %s does not support repository labels, skipping", b.providerClient.ProviderID()))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set labels of management repository %s: %w", ref.String(), err)
+	}
+
+	return nil
+}
+
+// labelsToTopics converts the given labels into sorted repository topics.
+func labelsToTopics(labels map[string]string) []string {
+	topics := make([]string, 0, len(labels))
+	for key, value := range labels {
+		topic := key
+		if value != "" {
+			topic = fmt.Sprintf("%s-%s", key, value)
+		}
+		topics = append(topics, strings.ToLower(topic))
+	}
+	sort.Strings(topics)
+
+	return topics
+}
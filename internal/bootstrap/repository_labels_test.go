@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type topicsRecorder struct {
+	owner      string
+	repository string
+	topics     []string
+}
+
+func (r *topicsRecorder) SetRepositoryTopics(_ context.Context, owner, repository string, topics []string) error {
+	r.owner, r.repository, r.topics = owner, repository, topics
+	return nil
+}
+
+func TestSetManagementRepositoryLabels(t *testing.T) {
+	ref := gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: "github.com", Organization: "open-component-model"},
+		RepositoryName:  "mpas-management",
+	}
+
+	t.Run("provider supports topics", func(t *testing.T) {
+		recorder := &topicsRecorder{}
+		b := &Bootstrap{
+			providerClient: &mockProviderClient{raw: recorder},
+			repository:     &mockGitRepository{ref: ref},
+		}
+
+		require.NoError(t, b.SetManagementRepositoryLabels(context.Background(), map[string]string{
+			"team": "Platform",
+			"mpas": "",
+			"env":  "prod",
+		}))
+		assert.Equal(t, "open-component-model", recorder.owner)
+		assert.Equal(t, "mpas-management", recorder.repository)
+		assert.Equal(t, []string{"env-prod", "mpas", "team-platform"}, recorder.topics)
+	})
+
+	t.Run("provider does not support topics", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		p, err := printer.Newprinter(out)
+		require.NoError(t, err)
+
+		b := &Bootstrap{
+			providerClient: &mockProviderClient{providerID: "custom"},
+			repository:     &mockGitRepository{ref: ref},
+			options:        options{printer: p},
+		}
+
+		require.NoError(t, b.SetManagementRepositoryLabels(context.Background(), map[string]string{"team": "platform"}))
+		assert.Contains(t, out.String(), "provider custom does not support repository labels")
+	})
+}
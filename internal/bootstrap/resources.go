@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/yaml"
+)
+
+// fluxManagerContainerName is the name of the container of the Flux controller Deployments.
+const fluxManagerContainerName = "manager"
+
+// WithFluxResourceLimits sets the resource limits of the Flux controllers, keyed by controller name,
+// e.g. source-controller. Controllers without limits are installed as-is.
+func WithFluxResourceLimits(limits map[string]corev1.ResourceList) Option {
+	return func(o *options) {
+		if o.fluxResourceLimits == nil {
+			o.fluxResourceLimits = make(map[string]corev1.ResourceList)
+		}
+		for controller, l := range limits {
+			o.fluxResourceLimits[controller] = l
+		}
+	}
+}
+
+// WithFluxResourceRequests sets the resource requests of the Flux controllers, keyed by controller name,
+// e.g. source-controller. Controllers without requests are installed as-is.
+func WithFluxResourceRequests(requests map[string]corev1.ResourceList) Option {
+	return func(o *options) {
+		if o.fluxResourceRequests == nil {
+			o.fluxResourceRequests = make(map[string]corev1.ResourceList)
+		}
+		for controller, r := range requests {
+			o.fluxResourceRequests[controller] = r
+		}
+	}
+}
+
+// withFluxResources sets the resource limits and requests of the Flux controllers.
+func withFluxResources(limits, requests map[string]corev1.ResourceList) fluxOption {
+	return func(o *fluxOptions) {
+		o.resourceLimits = limits
+		o.resourceRequests = requests
+	}
+}
+
+// resourcePatches returns a strategic merge patch per controller setting the resources
+// of its manager container.
+func (f *fluxInstall) resourcePatches() ([]kustypes.Patch, error) {
+	controllers := make(map[string]struct{}, len(f.resourceLimits)+len(f.resourceRequests))
+	for controller := range f.resourceLimits {
+		controllers[controller] = struct{}{}
+	}
+	for controller := range f.resourceRequests {
+		controllers[controller] = struct{}{}
+	}
+	names := make([]string, 0, len(controllers))
+	for controller := range controllers {
+		names = append(names, controller)
+	}
+	sort.Strings(names)
+
+	patches := make([]kustypes.Patch, 0, len(names))
+	for _, controller := range names {
+		resources := corev1.ResourceRequirements{
+			Limits:   f.resourceLimits[controller],
+			Requests: f.resourceRequests[controller],
+		}
+		if len(resources.Limits) == 0 && len(resources.Requests) == 0 {
+			continue
+		}
+
+		patch, err := yaml.Marshal(map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name": controller,
+			},
+			"spec": map[string]any{
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []map[string]any{
+							{
+								"name":      fluxManagerContainerName,
+								"resources": resources,
+							},
+						},
+					},
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal resources patch for %s: %w", controller, err)
+		}
+
+		patches = append(patches, kustypes.Patch{
+			Patch: string(patch),
+			Target: &kustypes.Selector{
+				ResId: resid.ResId{
+					Gvk:  resid.Gvk{Group: "apps", Kind: "Deployment"},
+					Name: controller,
+				},
+			},
+		})
+	}
+
+	return patches, nil
+}
@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	cfd "github.com/open-component-model/ocm-controller/pkg/configdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestFluxResourceLimits(t *testing.T) {
+	b := &Bootstrap{}
+	WithFluxResourceLimits(map[string]corev1.ResourceList{
+		"source-controller": {
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("256Mi"),
+		},
+	})(&b.options)
+	WithFluxResourceRequests(map[string]corev1.ResourceList{
+		"source-controller": {
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+	})(&b.options)
+
+	opts, fopts := b.newFluxOptions(t.TempDir(), nil)
+	for _, o := range fopts {
+		o(opts)
+	}
+
+	f := &fluxInstall{fluxOptions: opts}
+	kfile, kus, err := f.generateKustomization(bytes.NewReader(fluxControllers))
+	require.NoError(t, err)
+	res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+	require.NoError(t, err)
+
+	objects, err := kubeutils.YamlToUnstructructured(res)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+
+	for _, obj := range objects {
+		containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		require.NoError(t, err)
+		require.Len(t, containers, 1)
+		container := containers[0].(map[string]any)
+		assert.Equal(t, fluxManagerContainerName, container["name"])
+
+		resources, found, err := unstructured.NestedMap(container, "resources")
+		require.NoError(t, err)
+		if obj.GetName() != "source-controller" {
+			assert.False(t, found, "unexpected resources on %s", obj.GetName())
+			continue
+		}
+		assert.Equal(t, map[string]any{
+			"limits": map[string]any{
+				"cpu":    "500m",
+				"memory": "256Mi",
+			},
+			"requests": map[string]any{
+				"memory": "64Mi",
+			},
+		}, resources)
+	}
+}
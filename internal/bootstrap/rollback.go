@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/open-component-model/mpas/internal/env"
+)
+
+// Rollback restores the manifest of the given component, e.g. flux, to its content before
+// its last steps changes. The restored manifest is committed on top of the management repository
+// history and pushed, and the cluster is synced with it if a kubernetes client is configured.
+func (b *Bootstrap) Rollback(ctx context.Context, component string, steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("at least one step must be rolled back, got %d", steps)
+	}
+
+	if b.repository == nil {
+		return fmt.Errorf("management repository is not set")
+	}
+
+	manifestPath, err := b.componentManifestPath(component)
+	if err != nil {
+		return err
+	}
+
+	dir, err := mkdirTempDir("rollback")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	repo, auth, err := b.cloneManagementRepository(ctx, dir)
+	if err != nil {
+		return err
+	}
+
+	commit, err := findPreviousManifestCommit(repo, manifestPath, steps)
+	if err != nil {
+		return err
+	}
+
+	sha, err := restoreManifest(repo, dir, manifestPath, commit)
+	if err != nil {
+		return err
+	}
+
+	if err := repo.PushContext(ctx, &gogit.PushOptions{Auth: auth}); err != nil {
+		return fmt.Errorf("failed to push rollback: %w", err)
+	}
+
+	if b.kubeclient != nil {
+		if err := b.inSpinner("Reconciling component manifests", func() error {
+			return b.syncManagementRepository(ctx, sha)
+		}); err != nil {
+			return err
+		}
+	}
+
+	b.logCompleted(ctx, fmt.Sprintf("Rolled back %s to %s", component, commit.Hash.String()),
+		slog.String("component", component),
+		slog.String("commit", commit.Hash.String()))
+	return nil
+}
+
+// componentManifestPath returns the path of the manifest of the given component in the management repository.
+func (b *Bootstrap) componentManifestPath(component string) (string, error) {
+	ns := b.inventoryNamespace(component)
+	if ns == "" {
+		return "", fmt.Errorf("unknown component %q", component)
+	}
+
+	name := component + ".yaml"
+	if component == env.FluxName {
+		name = "gotk-components.yaml"
+	}
+
+	return path.Join(b.targetPath, ns, name), nil
+}
+
+// findPreviousManifestCommit returns the commit the file at manifestPath had its content from before the last
+// steps commits changing it, following the history of HEAD.
+func findPreviousManifestCommit(repo *gogit.Repository, manifestPath string, steps int) (*object.Commit, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	iter, err := repo.Log(&gogit.LogOptions{From: head.Hash(), FileName: &manifestPath})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history of %s: %w", manifestPath, err)
+	}
+	defer iter.Close()
+
+	var commits []*object.Commit
+	if err := iter.ForEach(func(c *object.Commit) error {
+		commits = append(commits, c)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get history of %s: %w", manifestPath, err)
+	}
+
+	if len(commits) <= steps {
+		return nil, fmt.Errorf("%s has been changed %d times, cannot roll back %d steps", manifestPath, len(commits), steps)
+	}
+
+	return commits[steps], nil
+}
+
+// restoreManifest overwrites the file at manifestPath with its content in the given commit and commits it.
+// It returns the hash of the new commit.
+func restoreManifest(repo *gogit.Repository, dir, manifestPath string, commit *object.Commit) (string, error) {
+	file, err := commit.File(manifestPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s in commit %s: %w", manifestPath, commit.Hash, err)
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s in commit %s: %w", manifestPath, commit.Hash, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, manifestPath), []byte(content), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", manifestPath, err)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if _, err := w.Add(manifestPath); err != nil {
+		return "", fmt.Errorf("failed to add %s: %w", manifestPath, err)
+	}
+
+	hash, err := w.Commit(fmt.Sprintf("Roll back %s to %s", manifestPath, commit.Hash), &gogit.CommitOptions{
+		Author: &object.Signature{Name: managedByValue, When: time.Now()},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to commit rollback: %w", err)
+	}
+
+	return hash.String(), nil
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newManifestHistory returns a bare repository whose main branch has a commit for each of the
+// given files, mapping paths to their content.
+func newManifestHistory(t *testing.T, commits []map[string]string) string {
+	dir := t.TempDir()
+	repo, err := gogit.PlainInit(dir, false)
+	require.NoError(t, err)
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+
+	for _, files := range commits {
+		for name, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o700))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+			_, err := w.Add(name)
+			require.NoError(t, err)
+		}
+		_, err := w.Commit("Update manifests", &gogit.CommitOptions{
+			Author: &object.Signature{Name: "test", When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	bare := t.TempDir()
+	bareRepo, err := gogit.PlainInit(bare, true)
+	require.NoError(t, err)
+	require.NoError(t, bareRepo.Storer.SetReference(
+		plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main"))))
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "bare", URLs: []string{bare}})
+	require.NoError(t, err)
+	require.NoError(t, repo.Push(&gogit.PushOptions{
+		RemoteName: "bare",
+		RefSpecs:   []config.RefSpec{config.RefSpec(head.Name().String() + ":refs/heads/main")},
+	}))
+	return bare
+}
+
+// headFile returns the content of the file at path on the main branch of the bare repository.
+func headFile(t *testing.T, bare, path string) string {
+	repo, err := gogit.PlainOpen(bare)
+	require.NoError(t, err)
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName("main"), true)
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(ref.Hash())
+	require.NoError(t, err)
+	file, err := commit.File(path)
+	require.NoError(t, err)
+	content, err := file.Contents()
+	require.NoError(t, err)
+	return content
+}
+
+func TestRollback(t *testing.T) {
+	const (
+		fluxManifest = "clusters/flux-system/gotk-components.yaml"
+		ocmManifest  = "clusters/ocm-system/ocm-controller.yaml"
+	)
+
+	testCases := []struct {
+		name        string
+		component   string
+		steps       int
+		path        string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:      "previous version",
+			component: "flux",
+			steps:     1,
+			path:      fluxManifest,
+			expected:  "flux v2.1.0",
+		},
+		{
+			name:      "two versions back",
+			component: "flux",
+			steps:     2,
+			path:      fluxManifest,
+			expected:  "flux v2.0.0",
+		},
+		{
+			// the commits changing only other manifests are not counted
+			name:      "other component",
+			component: "ocm-controller",
+			steps:     1,
+			path:      ocmManifest,
+			expected:  "ocm-controller v0.14.0",
+		},
+		{
+			name:        "not enough history",
+			component:   "ocm-controller",
+			steps:       2,
+			expectedErr: "has been changed 2 times, cannot roll back 2 steps",
+		},
+		{
+			name:        "unknown component",
+			component:   "podinfo",
+			steps:       1,
+			expectedErr: `unknown component "podinfo"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bare := newManifestHistory(t, []map[string]string{
+				{fluxManifest: "flux v2.0.0", ocmManifest: "ocm-controller v0.14.0"},
+				{fluxManifest: "flux v2.1.0"},
+				{ocmManifest: "ocm-controller v0.15.0"},
+				{fluxManifest: "flux v2.2.0"},
+			})
+
+			p, err := printer.Newprinter(&bytes.Buffer{})
+			require.NoError(t, err)
+			b := &Bootstrap{
+				repository: &mockGitRepository{},
+				url:        bare,
+				options: options{
+					defaultBranch: "main",
+					targetPath:    "./clusters",
+					printer:       p,
+				},
+			}
+
+			err = b.Rollback(context.Background(), tc.component, tc.steps)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, headFile(t, bare, tc.path))
+		})
+	}
+}
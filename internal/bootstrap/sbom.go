@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+)
+
+// sbomResourceType is the type of resources holding a software bill of materials.
+const sbomResourceType = "sbom"
+
+// ExtractSBOM writes the SBOMs of the given component version as newline-delimited JSON to w.
+// Every resource of type sbom is decompressed and written as one line.
+func (b *Bootstrap) ExtractSBOM(ctx context.Context, component, version string, w io.Writer) error {
+	ociRepo, err := b.makeOCIRepositoryWithFallback(ctx, om.DefaultContext())
+	if err != nil {
+		return fmt.Errorf("failed to create OCM repository: %w", err)
+	}
+	defer ociRepo.Close()
+
+	return extractSBOM(ociRepo, component, version, b.maxResourceSize, w)
+}
+
+// ExportSBOM writes the SBOMs of all installed components as newline-delimited JSON to w.
+// The installed component versions are read from the lock file.
+func (b *Bootstrap) ExportSBOM(ctx context.Context, w io.Writer) error {
+	store := b.lockStore()
+	if store == nil {
+		return fmt.Errorf("no lock file store is configured")
+	}
+
+	lock, err := store.Read(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(lock.Components) == 0 {
+		return fmt.Errorf("lock file %s not found", lockFileName)
+	}
+
+	ociRepo, err := b.makeOCIRepositoryWithFallback(ctx, om.DefaultContext())
+	if err != nil {
+		return fmt.Errorf("failed to create OCM repository: %w", err)
+	}
+	defer ociRepo.Close()
+
+	for _, entry := range lock.Components {
+		if err := extractSBOM(ociRepo, entry.ComponentName, entry.Version, b.maxResourceSize, w); err != nil {
+			return fmt.Errorf("failed to export SBOM of component %s: %w", entry.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// extractSBOM writes the sbom resources of the component version as compacted JSON lines to w.
+func extractSBOM(repo om.Repository, component, version string, maxSize int64, w io.Writer) (err error) {
+	cv, err := getComponentVersion(repo, component, version)
+	if err != nil {
+		return fmt.Errorf("failed to get component version %s:%s: %w", component, version, err)
+	}
+	defer func() {
+		err = errors.Join(err, cv.Close())
+	}()
+
+	for _, resource := range cv.GetResources() {
+		if resource.Meta().GetType() != sbomResourceType {
+			continue
+		}
+
+		if err := writeSBOM(resource, maxSize, w); err != nil {
+			return fmt.Errorf("failed to write SBOM %s: %w", resource.Meta().GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// writeSBOM writes the content of the resource as a single line of JSON to w.
+func writeSBOM(resource om.ResourceAccess, maxSize int64, w io.Writer) (err error) {
+	reader, err := getResourceContent(resource, maxSize)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, reader.Close())
+	}()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	buf.WriteByte('\n')
+
+	_, err = buf.WriteTo(w)
+	return err
+}
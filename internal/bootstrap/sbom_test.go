@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/open-component-model/ocm-controller/pkg/fakes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSBOMData = []byte(`{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "components": [
+    {"type": "application", "name": "git-controller", "version": "v1.0.0"}
+  ]
+}`)
+
+func TestExtractSBOM(t *testing.T) {
+	repo := newKustomizeTestRepository("git-controller")
+	cv := repo.cv[0].cva["v1.0.0"]
+	cv.Resources = append(cv.Resources, &fakes.Resource{
+		Name:    "git-controller-sbom",
+		Version: "v1.0.0",
+		Data:    testSBOMData,
+		Kind:    "localBlob",
+		Type:    sbomResourceType,
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, extractSBOM(repo, "git-controller", ">=v1.0.0", 0, &buf))
+	assert.Equal(t, `{"bomFormat":"CycloneDX","specVersion":"1.4","components":[{"type":"application","name":"git-controller","version":"v1.0.0"}]}`+"\n", buf.String())
+
+	buf.Reset()
+	require.Error(t, extractSBOM(repo, "git-controller", ">=v1.0.0", 16, &buf))
+
+	buf.Reset()
+	require.NoError(t, extractSBOM(newKustomizeTestRepository("git-controller"), "git-controller", ">=v1.0.0", 0, &buf))
+	assert.Empty(t, buf.String())
+}
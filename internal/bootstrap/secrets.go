@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// managedByLabel is the label marking the secrets created during bootstrap.
+	managedByLabel = "app.kubernetes.io/managed-by"
+	// managedByValue is the value of the managedByLabel for secrets created during bootstrap.
+	managedByValue = "mpas"
+	// encryptedSecretExt is the file extension of exported secrets.
+	encryptedSecretExt = ".yaml.age"
+)
+
+// ExportSecrets writes all secrets managed by mpas to outDir, encrypted with age.
+// The key at encryptionKeyPath is an age identity file; the secrets are encrypted to its recipients.
+func (b *Bootstrap) ExportSecrets(ctx context.Context, outDir string, encryptionKeyPath string) error {
+	identities, err := readAgeIdentities(encryptionKeyPath)
+	if err != nil {
+		return err
+	}
+
+	var recipients []age.Recipient
+	for _, identity := range identities {
+		x25519, ok := identity.(*age.X25519Identity)
+		if !ok {
+			return fmt.Errorf("unsupported age identity %T in %s", identity, encryptionKeyPath)
+		}
+		recipients = append(recipients, x25519.Recipient())
+	}
+
+	var secrets corev1.SecretList
+	if err := b.kubeclient.List(ctx, &secrets, client.MatchingLabels{managedByLabel: managedByValue}); err != nil {
+		return fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	if err := os.MkdirAll(outDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", outDir, err)
+	}
+
+	for i := range secrets.Items {
+		if err := exportSecret(&secrets.Items[i], outDir, recipients); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setManagedByLabel labels the object as created during bootstrap.
+func setManagedByLabel(obj metav1.Object) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[managedByLabel] = managedByValue
+	obj.SetLabels(labels)
+}
+
+// ImportSecrets decrypts the secrets exported by ExportSecrets from inDir and creates or updates them in the cluster.
+func (b *Bootstrap) ImportSecrets(ctx context.Context, inDir string, encryptionKeyPath string) error {
+	identities, err := readAgeIdentities(encryptionKeyPath)
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(inDir)
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %w", inDir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), encryptedSecretExt) {
+			continue
+		}
+
+		secret, err := importSecret(filepath.Join(inDir, entry.Name()), identities)
+		if err != nil {
+			return err
+		}
+
+		existing := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secret.Name, Namespace: secret.Namespace}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, b.kubeclient, existing, func() error {
+			existing.Labels = secret.Labels
+			existing.Annotations = secret.Annotations
+			existing.Type = secret.Type
+			existing.Data = secret.Data
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to import secret %s: %w", client.ObjectKeyFromObject(secret), err)
+		}
+	}
+
+	return nil
+}
+
+func exportSecret(secret *corev1.Secret, outDir string, recipients []age.Recipient) error {
+	exported := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secret.Name,
+			Namespace:   secret.Namespace,
+			Labels:      secret.Labels,
+			Annotations: secret.Annotations,
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+
+	data, err := yaml.Marshal(exported)
+	if err != nil {
+		return fmt.Errorf("failed to marshal secret %s: %w", client.ObjectKeyFromObject(secret), err)
+	}
+
+	out := &bytes.Buffer{}
+	w, err := age.Encrypt(out, recipients...)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt secret %s: %w", client.ObjectKeyFromObject(secret), err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to encrypt secret %s: %w", client.ObjectKeyFromObject(secret), err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to encrypt secret %s: %w", client.ObjectKeyFromObject(secret), err)
+	}
+
+	path := filepath.Join(outDir, fmt.Sprintf("%s_%s%s", secret.Namespace, secret.Name, encryptedSecretExt))
+	if err := os.WriteFile(path, out.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write secret %s: %w", client.ObjectKeyFromObject(secret), err)
+	}
+
+	return nil
+}
+
+func importSecret(path string, identities []age.Identity) (*corev1.Secret, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	r, err := age.Decrypt(f, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", path, err)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", path, err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := yaml.Unmarshal(data, secret); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal secret from %s: %w", path, err)
+	}
+
+	return secret, nil
+}
+
+func readAgeIdentities(path string) ([]age.Identity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open encryption key: %w", err)
+	}
+	defer f.Close()
+
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse encryption key: %w", err)
+	}
+
+	return identities, nil
+}
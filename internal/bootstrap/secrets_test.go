@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestExportImportSecrets(t *testing.T) {
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "key.txt")
+	require.NoError(t, os.WriteFile(keyPath, []byte(identity.String()+"\n"), 0o600))
+
+	managed := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "flux-system",
+			Namespace: "flux-system",
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": "mpas"},
+		},
+		Data: map[string][]byte{"password": []byte("super-secret")},
+	}
+	unmanaged := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("other-secret")},
+	}
+
+	b := &Bootstrap{options: options{
+		kubeclient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(managed, unmanaged).Build(),
+	}}
+
+	outDir := filepath.Join(t.TempDir(), "secrets")
+	require.NoError(t, b.ExportSecrets(context.Background(), outDir, keyPath))
+
+	entries, err := os.ReadDir(outDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "only secrets managed by mpas must be exported")
+	assert.Equal(t, "flux-system_flux-system.yaml.age", entries[0].Name())
+
+	content, err := os.ReadFile(filepath.Join(outDir, entries[0].Name()))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "super-secret")
+	assert.NotContains(t, string(content), "c3VwZXItc2VjcmV0")
+
+	// import into an empty cluster
+	b.kubeclient = fake.NewClientBuilder().WithScheme(scheme).Build()
+	require.NoError(t, b.ImportSecrets(context.Background(), outDir, keyPath))
+
+	imported := &corev1.Secret{}
+	require.NoError(t, b.kubeclient.Get(context.Background(), client.ObjectKeyFromObject(managed), imported))
+	assert.Equal(t, managed.Data, imported.Data)
+	assert.Equal(t, managed.Labels, imported.Labels)
+}
+
+func TestImportSecretsWrongKey(t *testing.T) {
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+
+	writeKey := func() string {
+		identity, err := age.GenerateX25519Identity()
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "key.txt")
+		require.NoError(t, os.WriteFile(path, []byte(identity.String()), 0o600))
+		return path
+	}
+
+	b := &Bootstrap{options: options{
+		kubeclient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "registry",
+				Namespace: "ocm-system",
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "mpas"},
+			},
+		}).Build(),
+	}}
+
+	outDir := t.TempDir()
+	require.NoError(t, b.ExportSecrets(context.Background(), outDir, writeKey()))
+	require.ErrorContains(t, b.ImportSecrets(context.Background(), outDir, writeKey()), "failed to decrypt")
+}
+
+func TestExportBootstrappedSourceSecret(t *testing.T) {
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	keyPath, _ := newSSHKey(t, "secret")
+	knownHostsPath := filepath.Join(t.TempDir(), "known_hosts")
+	require.NoError(t, os.WriteFile(knownHostsPath, []byte("github.com ssh-ed25519 AAAA\n"), 0o600))
+
+	f := &fluxInstall{fluxOptions: &fluxOptions{
+		kubeClient:     kubeClient,
+		transport:      "ssh",
+		namespace:      "flux-system",
+		sshURL:         "ssh://git@github.com/mpas/management.git",
+		sshKeyPath:     keyPath,
+		sshPassphrase:  "secret",
+		knownHostsPath: knownHostsPath,
+	}}
+	opts, err := f.sourceSecretOptions()
+	require.NoError(t, err)
+	require.NoError(t, f.reconcileSourceSecret(context.Background(), opts))
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	agePath := filepath.Join(t.TempDir(), "key.txt")
+	require.NoError(t, os.WriteFile(agePath, []byte(identity.String()+"\n"), 0o600))
+
+	b := &Bootstrap{options: options{kubeclient: kubeClient}}
+	outDir := filepath.Join(t.TempDir(), "secrets")
+	require.NoError(t, b.ExportSecrets(context.Background(), outDir, agePath))
+
+	entries, err := os.ReadDir(outDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "flux-system_flux-system.yaml.age", entries[0].Name())
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+)
+
+// WithFluxServiceAccountAnnotations adds the annotations to the ServiceAccount of the given Flux
+// controller, e.g. to bind it to a cloud workload identity. It can be set for several controllers.
+func WithFluxServiceAccountAnnotations(controllerName string, annotations map[string]string) Option {
+	return func(o *options) {
+		if o.fluxServiceAccountAnnotations == nil {
+			o.fluxServiceAccountAnnotations = make(map[string]map[string]string)
+		}
+		o.fluxServiceAccountAnnotations[controllerName] = mergeAnnotations(o.fluxServiceAccountAnnotations[controllerName], annotations)
+	}
+}
+
+// withFluxServiceAccountAnnotations adds the annotations to the ServiceAccount of the given Flux controller.
+func withFluxServiceAccountAnnotations(controllerName string, annotations map[string]string) fluxOption {
+	return func(o *fluxOptions) {
+		if o.serviceAccountAnnotations == nil {
+			o.serviceAccountAnnotations = make(map[string]map[string]string)
+		}
+		o.serviceAccountAnnotations[controllerName] = mergeAnnotations(o.serviceAccountAnnotations[controllerName], annotations)
+	}
+}
+
+// mergeAnnotations returns a copy of existing with the given annotations added.
+func mergeAnnotations(existing, annotations map[string]string) map[string]string {
+	merged := make(map[string]string, len(existing)+len(annotations))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	return merged
+}
+
+// serviceAccountAnnotationPatches returns a JSON6902 patch per controller adding the annotations
+// to its ServiceAccount. The Flux ServiceAccounts have no annotations, so the annotations are added
+// as a whole.
+func (f *fluxInstall) serviceAccountAnnotationPatches() ([]kustypes.Patch, error) {
+	controllers := make([]string, 0, len(f.serviceAccountAnnotations))
+	for controller := range f.serviceAccountAnnotations {
+		controllers = append(controllers, controller)
+	}
+	sort.Strings(controllers)
+
+	patches := make([]kustypes.Patch, 0, len(controllers))
+	for _, controller := range controllers {
+		annotations := f.serviceAccountAnnotations[controller]
+		if len(annotations) == 0 {
+			continue
+		}
+
+		patch, err := json.Marshal([]map[string]any{
+			{
+				"op":    "add",
+				"path":  "/metadata/annotations",
+				"value": annotations,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal service account patch for %s: %w", controller, err)
+		}
+
+		patches = append(patches, kustypes.Patch{
+			Patch: string(patch),
+			Target: &kustypes.Selector{
+				ResId: resid.ResId{
+					Gvk:  resid.Gvk{Kind: "ServiceAccount"},
+					Name: controller,
+				},
+			},
+		})
+	}
+
+	return patches, nil
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	cfd "github.com/open-component-model/ocm-controller/pkg/configdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var kustomizeControllerServiceAccount = []byte(`apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: kustomize-controller
+  namespace: flux-system
+---
+`)
+
+func TestFluxServiceAccountAnnotations(t *testing.T) {
+	b := &Bootstrap{}
+	WithFluxServiceAccountAnnotations("source-controller", map[string]string{
+		"iam.gke.io/gcp-service-account": "flux@my-project.iam.gserviceaccount.com",
+	})(&b.options)
+	WithFluxServiceAccountAnnotations("source-controller", map[string]string{
+		"example.com/team": "platform",
+	})(&b.options)
+	opts, fopts := b.newFluxOptions(t.TempDir(), nil)
+	for _, o := range fopts {
+		o(opts)
+	}
+
+	f := &fluxInstall{fluxOptions: opts}
+	manifests := append(append([]byte{}, sourceControllerManifests...), kustomizeControllerServiceAccount...)
+	kfile, kus, err := f.generateKustomization(bytes.NewReader(append(manifests, fluxControllers...)))
+	require.NoError(t, err)
+	res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+	require.NoError(t, err)
+
+	objects, err := kubeutils.YamlToUnstructructured(res)
+	require.NoError(t, err)
+
+	annotations := make(map[string]map[string]string)
+	for _, obj := range objects {
+		if obj.GetKind() == "ServiceAccount" {
+			annotations[obj.GetName()] = obj.GetAnnotations()
+		}
+	}
+	assert.Equal(t, map[string]map[string]string{
+		"source-controller": {
+			"iam.gke.io/gcp-service-account": "flux@my-project.iam.gserviceaccount.com",
+			"example.com/team":               "platform",
+		},
+		"kustomize-controller": nil,
+	}, annotations)
+}
+
+func TestFluxServiceAccountAnnotationsWithOIDC(t *testing.T) {
+	b := &Bootstrap{}
+	WithFluxServiceAccountAnnotations("source-controller", map[string]string{"example.com/team": "platform"})(&b.options)
+	WithFluxOIDCProvider(OIDCProviderGCP, "flux@my-project.iam.gserviceaccount.com")(&b.options)
+	opts, fopts := b.newFluxOptions(t.TempDir(), nil)
+	for _, o := range fopts {
+		o(opts)
+	}
+
+	f := &fluxInstall{fluxOptions: opts}
+	kfile, kus, err := f.generateKustomization(bytes.NewReader(append(append([]byte{}, sourceControllerManifests...), fluxControllers...)))
+	require.NoError(t, err)
+	res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+	require.NoError(t, err)
+
+	objects, err := kubeutils.YamlToUnstructructured(res)
+	require.NoError(t, err)
+	for _, obj := range objects {
+		if obj.GetKind() == "ServiceAccount" {
+			assert.Equal(t, map[string]string{
+				"example.com/team":               "platform",
+				"iam.gke.io/gcp-service-account": "flux@my-project.iam.gserviceaccount.com",
+			}, obj.GetAnnotations())
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// WithSigningKey GPG-signs the commits of the Flux component manifests with the first key
+// of the armored private key ring at path. The passphrase decrypts the key if it is encrypted.
+func WithSigningKey(path, passphrase string) Option {
+	return func(o *options) {
+		o.signingKeyPath = path
+		o.signingKeyPassphrase = passphrase
+	}
+}
+
+// withSigningKey sets the key used to sign the commits of the flux component manifests.
+func withSigningKey(path, passphrase string) fluxOption {
+	return func(o *fluxOptions) {
+		o.signingKeyPath = path
+		o.signingKeyPassphrase = passphrase
+	}
+}
+
+// loadSigningEntity reads the first entity of the armored key ring at path and decrypts
+// its private keys with passphrase.
+func loadSigningEntity(path, passphrase string) (*openpgp.Entity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open signing key %s: %w", path, err)
+	}
+	defer f.Close()
+
+	entities, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key %s: %w", path, err)
+	}
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("signing key %s contains no keys", path)
+	}
+
+	entity := entities[0]
+	if entity.PrivateKey == nil {
+		return nil, fmt.Errorf("signing key %s contains no private key", path)
+	}
+	if entity.PrivateKey.Encrypted {
+		if err := entity.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+			return nil, fmt.Errorf("failed to decrypt signing key %s: %w", path, err)
+		}
+	}
+	for _, subkey := range entity.Subkeys {
+		if subkey.PrivateKey != nil && subkey.PrivateKey.Encrypted {
+			if err := subkey.PrivateKey.Decrypt([]byte(passphrase)); err != nil {
+				return nil, fmt.Errorf("failed to decrypt signing subkey of %s: %w", path, err)
+			}
+		}
+	}
+
+	return entity, nil
+}
@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/gogit"
+	"github.com/fluxcd/pkg/git/repository"
+	gogitv5 "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSigningKey generates an ephemeral key, writes its passphrase protected private key ring
+// to a file and returns the path and the armored public key.
+func newSigningKey(t *testing.T, passphrase string) (string, string) {
+	entity, err := openpgp.NewEntity("mpas", "test", "mpas@example.com", nil)
+	require.NoError(t, err)
+
+	var public bytes.Buffer
+	w, err := armor.Encode(&public, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	require.NoError(t, entity.EncryptPrivateKeys([]byte(passphrase), nil))
+	var private bytes.Buffer
+	w, err = armor.Encode(&private, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivateWithoutSigning(w, nil))
+	require.NoError(t, w.Close())
+
+	path := filepath.Join(t.TempDir(), "signing.asc")
+	require.NoError(t, os.WriteFile(path, private.Bytes(), 0o600))
+	return path, public.String()
+}
+
+// newBareRepository creates a bare repository with the given number of commits on main, its HEAD.
+func newBareRepository(t *testing.T, commits int) string {
+	repo := newHistoryFixture(t, commits)
+	head, err := repo.Head()
+	require.NoError(t, err)
+
+	bare := t.TempDir()
+	bareRepo, err := gogitv5.PlainInit(bare, true)
+	require.NoError(t, err)
+	require.NoError(t, bareRepo.Storer.SetReference(
+		plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main"))))
+	_, err = repo.CreateRemote(&config.RemoteConfig{Name: "bare", URLs: []string{bare}})
+	require.NoError(t, err)
+	require.NoError(t, repo.Push(&gogitv5.PushOptions{
+		RemoteName: "bare",
+		RefSpecs:   []config.RefSpec{config.RefSpec(head.Name().String() + ":refs/heads/main")},
+	}))
+	return bare
+}
+
+func TestLoadSigningEntity(t *testing.T) {
+	path, _ := newSigningKey(t, "secret")
+
+	entity, err := loadSigningEntity(path, "secret")
+	require.NoError(t, err)
+	assert.False(t, entity.PrivateKey.Encrypted)
+
+	_, err = loadSigningEntity(path, "wrong")
+	assert.ErrorContains(t, err, "failed to decrypt signing key")
+
+	_, err = loadSigningEntity(filepath.Join(t.TempDir(), "missing.asc"), "")
+	assert.ErrorContains(t, err, "failed to open signing key")
+}
+
+func TestCommitAndPushComponentsSigned(t *testing.T) {
+	keyPath, publicKey := newSigningKey(t, "secret")
+	bare := newBareRepository(t, 1)
+
+	gitClient, err := gogit.NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP}, gogit.WithDiskStorage())
+	require.NoError(t, err)
+	_, err = gitClient.Clone(context.Background(), bare, repository.CloneOptions{
+		CheckoutStrategy: repository.CheckoutStrategy{Branch: "main"},
+	})
+	require.NoError(t, err)
+
+	f := &fluxInstall{
+		version: "v2.0.0",
+		fluxOptions: &fluxOptions{
+			gitClient:            gitClient,
+			branch:               "main",
+			signingKeyPath:       keyPath,
+			signingKeyPassphrase: "secret",
+		},
+	}
+	require.NoError(t, f.commitAndPushComponents(context.Background(), "flux-system/gotk-components.yaml", "content"))
+
+	repo, err := gogitv5.PlainOpen(bare)
+	require.NoError(t, err)
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName("main"), true)
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(ref.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, "Add Flux v2.0.0 component manifests", commit.Message)
+	assert.NotEmpty(t, commit.PGPSignature)
+
+	signer, err := commit.Verify(publicKey)
+	require.NoError(t, err)
+	assert.Contains(t, signer.Identities, "mpas (test) <mpas@example.com>")
+}
+
+func TestCommitAndPushComponentsUnsigned(t *testing.T) {
+	bare := newBareRepository(t, 1)
+
+	gitClient, err := gogit.NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP}, gogit.WithDiskStorage())
+	require.NoError(t, err)
+	_, err = gitClient.Clone(context.Background(), bare, repository.CloneOptions{
+		CheckoutStrategy: repository.CheckoutStrategy{Branch: "main"},
+	})
+	require.NoError(t, err)
+
+	f := &fluxInstall{version: "v2.0.0", fluxOptions: &fluxOptions{gitClient: gitClient, branch: "main"}}
+	require.NoError(t, f.commitAndPushComponents(context.Background(), "flux-system/gotk-components.yaml", "content"))
+
+	repo, err := gogitv5.PlainOpen(bare)
+	require.NoError(t, err)
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName("main"), true)
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(ref.Hash())
+	require.NoError(t, err)
+	assert.Empty(t, commit.PGPSignature)
+}
+
+func TestCommitAndPushComponentsAuthor(t *testing.T) {
+	testCases := []struct {
+		name      string
+		author    [2]string
+		wantName  string
+		wantEmail string
+	}{
+		{
+			name:      "custom author",
+			author:    [2]string{"Platform Bot", "platform@example.com"},
+			wantName:  "Platform Bot",
+			wantEmail: "platform@example.com",
+		},
+		{
+			name:      "default author",
+			wantName:  "mpas-bootstrap",
+			wantEmail: "mpas@noreply",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bare := newBareRepository(t, 1)
+
+			gitClient, err := gogit.NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP}, gogit.WithDiskStorage())
+			require.NoError(t, err)
+			_, err = gitClient.Clone(context.Background(), bare, repository.CloneOptions{
+				CheckoutStrategy: repository.CheckoutStrategy{Branch: "main"},
+			})
+			require.NoError(t, err)
+
+			opts := &fluxOptions{gitClient: gitClient, branch: "main"}
+			withCommitAuthor(tc.author[0], tc.author[1])(opts)
+			f := &fluxInstall{version: "v2.0.0", fluxOptions: opts}
+			require.NoError(t, f.commitAndPushComponents(context.Background(), "flux-system/gotk-components.yaml", "content"))
+
+			repo, err := gogitv5.PlainOpen(bare)
+			require.NoError(t, err)
+			ref, err := repo.Reference(plumbing.NewBranchReferenceName("main"), true)
+			require.NoError(t, err)
+			iter, err := repo.Log(&gogitv5.LogOptions{From: ref.Hash()})
+			require.NoError(t, err)
+			commit, err := iter.Next()
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantName, commit.Author.Name)
+			assert.Equal(t, tc.wantEmail, commit.Author.Email)
+		})
+	}
+}
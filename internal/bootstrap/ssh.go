@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/fluxcd/flux2/v2/pkg/manifestgen/sourcesecret"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// sshTransport is the transport type that makes Flux sync the management repository over SSH.
+	sshTransport = "ssh"
+
+	// The keys of the source secret data read by the source-controller for SSH authentication.
+	sshPrivateKeySecretKey = "identity"
+	sshPublicKeySecretKey  = "identity.pub"
+	sshKnownHostsSecretKey = "known_hosts"
+	sshPasswordSecretKey   = "password"
+)
+
+// WithSSHKey makes the Flux GitRepository authenticate with the private key at path instead of the
+// token if the transport type is ssh. The passphrase decrypts the key if it is encrypted.
+func WithSSHKey(path, passphrase string) Option {
+	return func(o *options) {
+		o.sshKeyPath = path
+		o.sshPassphrase = passphrase
+	}
+}
+
+// WithSSHKnownHosts sets the known_hosts file the Flux GitRepository verifies the git server with.
+// If it is not set, the host key of the git server is scanned during the bootstrap.
+func WithSSHKnownHosts(path string) Option {
+	return func(o *options) {
+		o.sshKnownHostsPath = path
+	}
+}
+
+// withSSHKeyPath sets the private key the GitRepository authenticates with.
+func withSSHKeyPath(path string) fluxOption {
+	return func(o *fluxOptions) {
+		o.sshKeyPath = path
+	}
+}
+
+// withSSHPassphrase sets the passphrase of the private key.
+func withSSHPassphrase(passphrase string) fluxOption {
+	return func(o *fluxOptions) {
+		o.sshPassphrase = passphrase
+	}
+}
+
+// withKnownHostsPath sets the known_hosts file of the GitRepository.
+func withKnownHostsPath(path string) fluxOption {
+	return func(o *fluxOptions) {
+		o.knownHostsPath = path
+	}
+}
+
+// sourceSecretOptions returns the options of the secret the GitRepository authenticates with.
+// It contains the SSH key pair if the transport is ssh and the token otherwise.
+func (f *fluxInstall) sourceSecretOptions() (sourcesecret.Options, error) {
+	opts := sourcesecret.Options{
+		Name:         f.namespace,
+		Namespace:    f.namespace,
+		TargetPath:   f.targetPath,
+		ManifestFile: sourcesecret.MakeDefaultOptions().ManifestFile,
+	}
+
+	if f.transport != sshTransport {
+		opts.Username = "git"
+		opts.Password = f.token
+		opts.CAFile = f.caFile
+		return opts, nil
+	}
+
+	if f.sshKeyPath == "" {
+		return sourcesecret.Options{}, fmt.Errorf("an SSH key is required for the ssh transport")
+	}
+
+	keypair, err := sourcesecret.LoadKeyPairFromPath(f.sshKeyPath, f.sshPassphrase)
+	if err != nil {
+		return sourcesecret.Options{}, fmt.Errorf("failed to load SSH key %s: %w", f.sshKeyPath, err)
+	}
+
+	u, err := url.Parse(f.sshURL)
+	if err != nil {
+		return sourcesecret.Options{}, fmt.Errorf("failed to parse SSH URL %s: %w", f.sshURL, err)
+	}
+
+	opts.Keypair = keypair
+	// the source-controller decrypts the private key with the password
+	opts.Password = f.sshPassphrase
+	opts.SSHHostname = u.Host
+
+	return opts, nil
+}
+
+// reconcileSourceSecret applies the source secret to the cluster. If a known_hosts file is set,
+// the secret is applied directly, as the flux bootstrapper always scans the host key of the git server.
+func (f *fluxInstall) reconcileSourceSecret(ctx context.Context, opts sourcesecret.Options) error {
+	if opts.Keypair == nil || f.knownHostsPath == "" {
+		if err := f.fluxBootstrapper.ReconcileSourceSecret(ctx, opts); err != nil {
+			return err
+		}
+		return f.labelSourceSecret(ctx, opts)
+	}
+
+	knownHosts, err := os.ReadFile(f.knownHostsPath)
+	if err != nil {
+		return fmt.Errorf("failed to read known hosts %s: %w", f.knownHostsPath, err)
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Namespace: opts.Namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, f.kubeClient, secret, func() error {
+		setManagedByLabel(secret)
+		secret.Data = sshSecretData(opts, knownHosts)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile source secret %s: %w", opts.Name, err)
+	}
+
+	return nil
+}
+
+// labelSourceSecret labels the source secret applied by the flux bootstrapper as managed by mpas,
+// so that it is exported and restored with the other secrets created during bootstrap.
+func (f *fluxInstall) labelSourceSecret(ctx context.Context, opts sourcesecret.Options) error {
+	secret := &corev1.Secret{}
+	if err := f.kubeClient.Get(ctx, client.ObjectKey{Name: opts.Name, Namespace: opts.Namespace}, secret); err != nil {
+		return fmt.Errorf("failed to get source secret %s: %w", opts.Name, err)
+	}
+
+	patch := client.MergeFrom(secret.DeepCopy())
+	setManagedByLabel(secret)
+	if err := f.kubeClient.Patch(ctx, secret, patch); err != nil {
+		return fmt.Errorf("failed to label source secret %s: %w", opts.Name, err)
+	}
+
+	return nil
+}
+
+// sshSecretData returns the data of the source secret for SSH authentication.
+func sshSecretData(opts sourcesecret.Options, knownHosts []byte) map[string][]byte {
+	data := map[string][]byte{
+		sshPrivateKeySecretKey: opts.Keypair.PrivateKey,
+		sshPublicKeySecretKey:  opts.Keypair.PublicKey,
+		sshKnownHostsSecretKey: knownHosts,
+	}
+	if opts.Password != "" {
+		data[sshPasswordSecretKey] = []byte(opts.Password)
+	}
+
+	return data
+}
@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newSSHKey generates an ephemeral ed25519 key, writes its passphrase protected private key
+// to a file and returns the path and the authorized public key.
+func newSSHKey(t *testing.T, passphrase string) (string, []byte) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(private, "", []byte(passphrase))
+	require.NoError(t, err)
+
+	sshPublic, err := ssh.NewPublicKey(public)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(block), 0o600))
+	return path, ssh.MarshalAuthorizedKey(sshPublic)
+}
+
+func TestSourceSecretOptions(t *testing.T) {
+	keyPath, publicKey := newSSHKey(t, "secret")
+	privateKey, err := os.ReadFile(keyPath)
+	require.NoError(t, err)
+
+	t.Run("https", func(t *testing.T) {
+		f := &fluxInstall{fluxOptions: &fluxOptions{
+			transport: "https",
+			namespace: "flux-system",
+			token:     "token",
+			caFile:    []byte("ca"),
+		}}
+
+		opts, err := f.sourceSecretOptions()
+		require.NoError(t, err)
+		assert.Equal(t, "flux-system", opts.Name)
+		assert.Equal(t, "git", opts.Username)
+		assert.Equal(t, "token", opts.Password)
+		assert.Equal(t, []byte("ca"), opts.CAFile)
+		assert.Nil(t, opts.Keypair)
+	})
+
+	t.Run("ssh", func(t *testing.T) {
+		f := &fluxInstall{fluxOptions: &fluxOptions{
+			transport:     "ssh",
+			namespace:     "flux-system",
+			token:         "token",
+			sshURL:        "ssh://git@github.com/mpas/management.git",
+			sshKeyPath:    keyPath,
+			sshPassphrase: "secret",
+		}}
+
+		opts, err := f.sourceSecretOptions()
+		require.NoError(t, err)
+		assert.Equal(t, "flux-system", opts.Name)
+		assert.Empty(t, opts.Username)
+		assert.Equal(t, "secret", opts.Password)
+		assert.Equal(t, "github.com", opts.SSHHostname)
+		require.NotNil(t, opts.Keypair)
+		assert.Equal(t, privateKey, opts.Keypair.PrivateKey)
+		assert.Equal(t, publicKey, opts.Keypair.PublicKey)
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		f := &fluxInstall{fluxOptions: &fluxOptions{
+			transport:     "ssh",
+			sshURL:        "ssh://git@github.com/mpas/management.git",
+			sshKeyPath:    keyPath,
+			sshPassphrase: "wrong",
+		}}
+
+		_, err := f.sourceSecretOptions()
+		assert.ErrorContains(t, err, "failed to load SSH key")
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		f := &fluxInstall{fluxOptions: &fluxOptions{transport: "ssh"}}
+
+		_, err := f.sourceSecretOptions()
+		assert.EqualError(t, err, "an SSH key is required for the ssh transport")
+	})
+}
+
+func TestReconcileSourceSecretWithKnownHosts(t *testing.T) {
+	keyPath, publicKey := newSSHKey(t, "secret")
+	privateKey, err := os.ReadFile(keyPath)
+	require.NoError(t, err)
+
+	knownHosts := []byte("github.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl\n")
+	knownHostsPath := filepath.Join(t.TempDir(), "known_hosts")
+	require.NoError(t, os.WriteFile(knownHostsPath, knownHosts, 0o600))
+
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	f := &fluxInstall{fluxOptions: &fluxOptions{
+		kubeClient:     kubeClient,
+		transport:      "ssh",
+		namespace:      "flux-system",
+		sshURL:         "ssh://git@github.com/mpas/management.git",
+		sshKeyPath:     keyPath,
+		sshPassphrase:  "secret",
+		knownHostsPath: knownHostsPath,
+	}}
+
+	opts, err := f.sourceSecretOptions()
+	require.NoError(t, err)
+	require.NoError(t, f.reconcileSourceSecret(context.Background(), opts))
+
+	secret := &corev1.Secret{}
+	require.NoError(t, kubeClient.Get(context.Background(), client.ObjectKey{Name: "flux-system", Namespace: "flux-system"}, secret))
+	assert.Equal(t, map[string][]byte{
+		"identity":     privateKey,
+		"identity.pub": publicKey,
+		"known_hosts":  knownHosts,
+		"password":     []byte("secret"),
+	}, secret.Data)
+}
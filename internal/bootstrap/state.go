@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	// bootstrapStateFileName is the name of the file in the management repository
+	// that keeps track of the completed bootstrap phases.
+	bootstrapStateFileName = "mpas-bootstrap-state.json"
+	// phaseComponentInstall is the phase of installing a component.
+	phaseComponentInstall = "component-install"
+)
+
+// WithSkipCompletedPhases enables the resume mode. In resume mode the bootstrap reads the
+// state file from the management repository and skips the phases that have already been completed.
+// It cannot be combined with the cluster-only mode, which has no management repository.
+func WithSkipCompletedPhases(skip bool) Option {
+	return func(o *options) {
+		o.skipCompletedPhases = skip
+	}
+}
+
+// BootstrapState is a completed bootstrap phase.
+type BootstrapState struct {
+	// Phase is the name of the completed phase.
+	Phase string `json:"phase"`
+	// ComponentName is the name of the component the phase was run for.
+	ComponentName string `json:"componentName"`
+	// Version is the version of the component.
+	Version string `json:"version"`
+	// Checksum is the sha256 checksum of the committed manifests.
+	Checksum string `json:"checksum"`
+	// CompletedAt is the time the phase was completed.
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// bootstrapState is the content of the state file.
+type bootstrapState struct {
+	Phases []BootstrapState `json:"phases"`
+}
+
+// completed returns true if the given phase was completed for the given component and version.
+func (s *bootstrapState) completed(phase, componentName, version string) bool {
+	for _, p := range s.Phases {
+		if p.Phase == phase && p.ComponentName == componentName && p.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// completedAt returns the time the given phase was completed for the given component and version.
+// It returns the zero time if the phase was not completed.
+func (s *bootstrapState) completedAt(phase, componentName, version string) time.Time {
+	for _, p := range s.Phases {
+		if p.Phase == phase && p.ComponentName == componentName && p.Version == version {
+			return p.CompletedAt
+		}
+	}
+	return time.Time{}
+}
+
+// with returns a copy of the state containing the given phase.
+// A previously completed phase of the same component is replaced.
+func (s *bootstrapState) with(state BootstrapState) *bootstrapState {
+	phases := make([]BootstrapState, 0, len(s.Phases)+1)
+	for _, p := range s.Phases {
+		if p.Phase == state.Phase && p.ComponentName == state.ComponentName {
+			continue
+		}
+		phases = append(phases, p)
+	}
+
+	return &bootstrapState{Phases: append(phases, state)}
+}
+
+func (s *bootstrapState) marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bootstrap state: %w", err)
+	}
+	return data, nil
+}
+
+func newComponentState(componentName, version string, content []byte) BootstrapState {
+	return BootstrapState{
+		Phase:         phaseComponentInstall,
+		ComponentName: componentName,
+		Version:       version,
+		Checksum:      fmt.Sprintf("%x", sha256.Sum256(content)),
+		CompletedAt:   time.Now().UTC(),
+	}
+}
+
+// readBootstrapState reads the state file from the management repository.
+// An empty state is returned if the state file does not exist.
+func (b *Bootstrap) readBootstrapState(ctx context.Context) (*bootstrapState, error) {
+	files, err := b.repository.Files().Get(ctx, b.targetPath, b.defaultBranch)
+	if err != nil {
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return &bootstrapState{}, nil
+		}
+		return nil, fmt.Errorf("failed to read bootstrap state: %w", err)
+	}
+
+	for _, file := range files {
+		if file.Path == nil || file.Content == nil || filepath.Base(*file.Path) != bootstrapStateFileName {
+			continue
+		}
+
+		state := &bootstrapState{}
+		if err := json.Unmarshal([]byte(*file.Content), state); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal bootstrap state: %w", err)
+		}
+
+		return state, nil
+	}
+
+	return &bootstrapState{}, nil
+}
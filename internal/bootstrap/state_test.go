@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBootstrapStateResume(t *testing.T) {
+	state := &bootstrapState{}
+	mc := &mockCommitClient{
+		commit: &mockCommit{
+			sha: "sha",
+		},
+	}
+
+	newInstall := func(name string) *componentInstall {
+		return &componentInstall{
+			componentName: name,
+			version:       "v1.0.0",
+			componentOptions: &componentOptions{
+				gitRepository: &mockGitRepository{commitClient: mc},
+				dir:           t.TempDir(),
+				branch:        "main",
+				targetPath:    "target",
+				namespace:     "ocm-system",
+				state:         state,
+			},
+			kustomizer: &mockKustomizer{
+				out: kustomizedDeployment,
+			},
+		}
+	}
+
+	// the first component is installed successfully
+	_, err := newInstall("ocm.software/mpas/ocm-controller").install(context.Background(), "ocm-controller-file")
+	require.NoError(t, err)
+
+	require.Len(t, mc.calledWidth, 1)
+	files := mc.calledWidth[0][2].([]gitprovider.CommitFile)
+	require.Len(t, files, 2)
+	assert.Equal(t, "target/"+bootstrapStateFileName, *files[1].Path)
+	stateFile := files[1]
+
+	// the second component fails, its phase must not be recorded
+	mc.err = errors.New("connection reset")
+	_, err = newInstall("ocm.software/mpas/git-controller").install(context.Background(), "git-controller-file")
+	require.Error(t, err)
+	assert.Len(t, state.Phases, 1)
+
+	// re-running the bootstrap reads the committed state
+	b := &Bootstrap{
+		repository: &mockGitRepository{
+			fileClient: &mockFileClient{
+				files: []*gitprovider.CommitFile{&stateFile},
+			},
+		},
+		options: options{
+			targetPath:          "target",
+			defaultBranch:       "main",
+			skipCompletedPhases: true,
+		},
+	}
+
+	resumed, err := b.readBootstrapState(context.Background())
+	require.NoError(t, err)
+	assert.True(t, resumed.completed(phaseComponentInstall, "ocm.software/mpas/ocm-controller", "v1.0.0"))
+	assert.False(t, resumed.completed(phaseComponentInstall, "ocm.software/mpas/ocm-controller", "v1.1.0"))
+	assert.False(t, resumed.completed(phaseComponentInstall, "ocm.software/mpas/git-controller", "v1.0.0"))
+}
+
+func TestBootstrapStateMissingFile(t *testing.T) {
+	b := &Bootstrap{
+		repository: &mockGitRepository{
+			fileClient: &mockFileClient{},
+		},
+	}
+
+	state, err := b.readBootstrapState(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, state.Phases)
+}
+
+func TestValidateSkipCompletedPhasesClusterOnly(t *testing.T) {
+	p, err := printer.Newprinter(io.Discard)
+	require.NoError(t, err)
+
+	opts := &options{
+		repositoryName:   "mpas",
+		restClientGetter: genericclioptions.NewConfigFlags(false),
+		kubeclient:       fake.NewClientBuilder().Build(),
+		printer:          p,
+	}
+	WithSkipCompletedPhases(true)(opts)
+	assert.NoError(t, validateOptions(opts))
+
+	WithClusterOnlyMode(true)(opts)
+	assert.ErrorContains(t, validateOptions(opts), "it cannot be used with cluster only")
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/open-component-model/mpas/internal/env"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncPollInterval is the interval the GitRepository is polled at while waiting for a revision.
+var syncPollInterval = env.DefaultPollInterval
+
+// WaitForManagementRepositorySync waits until the Flux GitRepository of the management repository
+// has fetched expectedRevision. The revision is either a full Flux revision, e.g. main@sha1:<sha>,
+// or a commit sha. An error is returned if the revision is not synced within timeout.
+func (b *Bootstrap) WaitForManagementRepositorySync(ctx context.Context, expectedRevision string, timeout time.Duration) error {
+	namespace := b.componentNamespace(env.FluxName, env.DefaultFluxNamespace)
+	objKey := client.ObjectKey{Name: namespace, Namespace: namespace}
+
+	var revision string
+	err := wait.PollImmediateWithContext(ctx, syncPollInterval, timeout, func(ctx context.Context) (bool, error) {
+		repo := &sourcev1.GitRepository{}
+		if err := b.kubeclient.Get(ctx, objKey, repo); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get GitRepository %s: %w", objKey, err)
+		}
+
+		if repo.Status.Artifact == nil {
+			return false, nil
+		}
+		revision = repo.Status.Artifact.Revision
+
+		return revisionMatches(revision, expectedRevision), nil
+	})
+	if err != nil {
+		if errors.Is(err, wait.ErrWaitTimeout) {
+			return fmt.Errorf("timed out after %s waiting for GitRepository %s to sync revision %s, last synced revision is %q",
+				timeout, objKey, expectedRevision, revision)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// revisionMatches returns true if the Flux artifact revision is the expected revision.
+// A commit sha matches the digest of the revision.
+func revisionMatches(revision, expected string) bool {
+	if revision == expected {
+		return true
+	}
+
+	if !strings.Contains(expected, "@") && !strings.Contains(expected, ":") {
+		return strings.HasSuffix(revision, ":"+expected)
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// syncingClient moves the GitRepository to the synced revision after the given number of reads.
+type syncingClient struct {
+	client.Client
+
+	reads    int
+	syncAt   int
+	revision string
+}
+
+func (c *syncingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := c.Client.Get(ctx, key, obj, opts...); err != nil {
+		return err
+	}
+
+	c.reads++
+	if repo, ok := obj.(*sourcev1.GitRepository); ok && c.reads >= c.syncAt {
+		repo.Status.Artifact = &sourcev1.Artifact{Revision: c.revision}
+	}
+	return nil
+}
+
+func newSyncTestBootstrap(t *testing.T, syncAt int) *Bootstrap {
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+
+	repo := &sourcev1.GitRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: env.DefaultFluxNamespace, Namespace: env.DefaultFluxNamespace},
+		Status: sourcev1.GitRepositoryStatus{
+			Artifact: &sourcev1.Artifact{Revision: "main@sha1:old"},
+		},
+	}
+
+	return &Bootstrap{options: options{
+		kubeclient: &syncingClient{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(repo).Build(),
+			syncAt:   syncAt,
+			revision: "main@sha1:abc123",
+		},
+	}}
+}
+
+func TestWaitForManagementRepositorySync(t *testing.T) {
+	syncPollInterval = time.Millisecond
+	t.Cleanup(func() { syncPollInterval = env.DefaultPollInterval })
+
+	b := newSyncTestBootstrap(t, 3)
+	require.NoError(t, b.WaitForManagementRepositorySync(context.Background(), "main@sha1:abc123", time.Second))
+	assert.Equal(t, 3, b.kubeclient.(*syncingClient).reads)
+
+	// a commit sha matches the revision
+	b = newSyncTestBootstrap(t, 1)
+	require.NoError(t, b.WaitForManagementRepositorySync(context.Background(), "abc123", time.Second))
+}
+
+func TestWaitForManagementRepositorySyncTimeout(t *testing.T) {
+	syncPollInterval = time.Millisecond
+	t.Cleanup(func() { syncPollInterval = env.DefaultPollInterval })
+
+	b := newSyncTestBootstrap(t, 1)
+	err := b.WaitForManagementRepositorySync(context.Background(), "main@sha1:def456", 20*time.Millisecond)
+	require.ErrorContains(t, err, "timed out after 20ms waiting for GitRepository flux-system/flux-system to sync revision main@sha1:def456")
+	require.ErrorContains(t, err, `last synced revision is "main@sha1:abc123"`)
+}
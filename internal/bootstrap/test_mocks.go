@@ -2,9 +2,12 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
 	"github.com/open-component-model/ocm-controller/pkg/fakes"
 	"github.com/open-component-model/ocm/pkg/contexts/ocm"
 )
@@ -27,6 +30,15 @@ func (m *mockRepository) LookupComponent(name string) (ocm.ComponentAccess, erro
 	return nil, fmt.Errorf("%s not found in configured components", name)
 }
 
+func (m *mockRepository) LookupComponentVersion(name, version string) (ocm.ComponentVersionAccess, error) {
+	c, err := m.LookupComponent(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.LookupVersion(version)
+}
+
 var _ ocm.Repository = &mockRepository{}
 
 // ************** Mock Component Access Values and Functions **************
@@ -57,7 +69,12 @@ var _ ocm.ComponentAccess = &mockComponentAccess{}
 type mockGitRepository struct {
 	gitprovider.UserRepository
 
-	commitClient gitprovider.CommitClient
+	commitClient      gitprovider.CommitClient
+	fileClient        gitprovider.FileClient
+	branchClient      gitprovider.BranchClient
+	pullRequestClient gitprovider.PullRequestClient
+	ref               gitprovider.RepositoryRef
+	deleted           bool
 }
 
 var _ gitprovider.UserRepository = &mockGitRepository{}
@@ -66,10 +83,32 @@ func (m *mockGitRepository) Commits() gitprovider.CommitClient {
 	return m.commitClient
 }
 
+func (m *mockGitRepository) Files() gitprovider.FileClient {
+	return m.fileClient
+}
+
+func (m *mockGitRepository) Branches() gitprovider.BranchClient {
+	return m.branchClient
+}
+
+func (m *mockGitRepository) PullRequests() gitprovider.PullRequestClient {
+	return m.pullRequestClient
+}
+
+func (m *mockGitRepository) Repository() gitprovider.RepositoryRef {
+	return m.ref
+}
+
+func (m *mockGitRepository) Delete(ctx context.Context) error {
+	m.deleted = true
+	return nil
+}
+
 type mockCommitClient struct {
 	gitprovider.CommitClient
 
 	commit gitprovider.Commit
+	err    error
 
 	calledWidth [][]any
 }
@@ -78,10 +117,79 @@ var _ gitprovider.CommitClient = &mockCommitClient{}
 
 func (m *mockCommitClient) Create(ctx context.Context, branch string, message string, files []gitprovider.CommitFile) (gitprovider.Commit, error) {
 	m.calledWidth = append(m.calledWidth, []any{branch, message, files})
+	if m.err != nil {
+		return nil, m.err
+	}
 
 	return m.commit, nil
 }
 
+func (m *mockCommitClient) ListPage(ctx context.Context, branch string, perPage int, page int) ([]gitprovider.Commit, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return []gitprovider.Commit{m.commit}, nil
+}
+
+type mockBranchClient struct {
+	gitprovider.BranchClient
+
+	created map[string]string
+}
+
+var _ gitprovider.BranchClient = &mockBranchClient{}
+
+func (m *mockBranchClient) Create(ctx context.Context, branch, sha string) error {
+	if m.created == nil {
+		m.created = make(map[string]string)
+	}
+	m.created[branch] = sha
+	return nil
+}
+
+type mockPullRequestClient struct {
+	gitprovider.PullRequestClient
+
+	url         string
+	calledWidth [][]any
+}
+
+var _ gitprovider.PullRequestClient = &mockPullRequestClient{}
+
+func (m *mockPullRequestClient) Create(ctx context.Context, title, branch, baseBranch, description string) (gitprovider.PullRequest, error) {
+	m.calledWidth = append(m.calledWidth, []any{title, branch, baseBranch, description})
+	return &mockPullRequest{url: m.url}, nil
+}
+
+type mockPullRequest struct {
+	gitprovider.PullRequest
+
+	url string
+}
+
+func (m *mockPullRequest) Get() gitprovider.PullRequestInfo {
+	return gitprovider.PullRequestInfo{WebURL: m.url}
+}
+
+var _ gitprovider.PullRequest = &mockPullRequest{}
+
+type mockFileClient struct {
+	gitprovider.FileClient
+
+	files []*gitprovider.CommitFile
+}
+
+var _ gitprovider.FileClient = &mockFileClient{}
+
+func (m *mockFileClient) Get(ctx context.Context, path, branch string, optFns ...gitprovider.FilesGetOption) ([]*gitprovider.CommitFile, error) {
+	if len(m.files) == 0 {
+		return nil, gitprovider.ErrNotFound
+	}
+
+	return m.files, nil
+}
+
 type mockCommit struct {
 	sha string
 
@@ -96,6 +204,153 @@ func (m *mockCommit) Get() gitprovider.CommitInfo {
 
 var _ gitprovider.Commit = &mockCommit{}
 
+type mockProviderClient struct {
+	providerID      gitprovider.ProviderID
+	hasPermission   bool
+	permissionErr   error
+	raw             interface{}
+	domain          string
+	orgRepositories gitprovider.OrgRepositoriesClient
+
+	gitprovider.Client
+}
+
+func (m *mockProviderClient) Raw() interface{} {
+	return m.raw
+}
+
+func (m *mockProviderClient) ProviderID() gitprovider.ProviderID {
+	return m.providerID
+}
+
+func (m *mockProviderClient) HasTokenPermission(ctx context.Context, permission gitprovider.TokenPermission) (bool, error) {
+	return m.hasPermission, m.permissionErr
+}
+
+func (m *mockProviderClient) SupportedDomain() string {
+	return m.domain
+}
+
+func (m *mockProviderClient) OrgRepositories() gitprovider.OrgRepositoriesClient {
+	return m.orgRepositories
+}
+
+var _ gitprovider.Client = &mockProviderClient{}
+
+// recordingProviderClient records every call to the git provider API, it is used to assert
+// that the git provider is not used at all. The provider ID and domain are not recorded.
+type recordingProviderClient struct {
+	gitprovider.Client
+
+	calls []string
+}
+
+var _ gitprovider.Client = &recordingProviderClient{}
+
+var errUnexpectedProviderCall = errors.New("unexpected call to the git provider")
+
+func (m *recordingProviderClient) SupportedDomain() string {
+	return "github.com"
+}
+
+func (m *recordingProviderClient) ProviderID() gitprovider.ProviderID {
+	return "github"
+}
+
+func (m *recordingProviderClient) Raw() interface{} {
+	m.calls = append(m.calls, "Raw")
+	return nil
+}
+
+func (m *recordingProviderClient) HasTokenPermission(ctx context.Context, permission gitprovider.TokenPermission) (bool, error) {
+	m.calls = append(m.calls, "HasTokenPermission")
+	return false, errUnexpectedProviderCall
+}
+
+func (m *recordingProviderClient) Organizations() gitprovider.OrganizationsClient {
+	m.calls = append(m.calls, "Organizations")
+	return nil
+}
+
+func (m *recordingProviderClient) OrgRepositories() gitprovider.OrgRepositoriesClient {
+	m.calls = append(m.calls, "OrgRepositories")
+	return nil
+}
+
+func (m *recordingProviderClient) UserRepositories() gitprovider.UserRepositoriesClient {
+	m.calls = append(m.calls, "UserRepositories")
+	return nil
+}
+
+// recordingGitClient records every call to the management repository clone, it is used to assert
+// that nothing is cloned, committed or pushed.
+type recordingGitClient struct {
+	repository.Client
+
+	calls []string
+}
+
+var _ repository.Client = &recordingGitClient{}
+
+var errUnexpectedGitCall = errors.New("unexpected call to the git client")
+
+func (m *recordingGitClient) Clone(ctx context.Context, url string, opts repository.CloneOptions) (*git.Commit, error) {
+	m.calls = append(m.calls, "Clone")
+	return nil, errUnexpectedGitCall
+}
+
+func (m *recordingGitClient) Head() (string, error) {
+	m.calls = append(m.calls, "Head")
+	return "", errUnexpectedGitCall
+}
+
+func (m *recordingGitClient) Path() string {
+	m.calls = append(m.calls, "Path")
+	return ""
+}
+
+func (m *recordingGitClient) Commit(info git.Commit, opts ...repository.CommitOption) (string, error) {
+	m.calls = append(m.calls, "Commit")
+	return "", errUnexpectedGitCall
+}
+
+func (m *recordingGitClient) Push(ctx context.Context) error {
+	m.calls = append(m.calls, "Push")
+	return errUnexpectedGitCall
+}
+
+type mockOrgRepositoriesClient struct {
+	gitprovider.OrgRepositoriesClient
+
+	refs []gitprovider.OrgRepositoryRef
+	repo gitprovider.OrgRepository
+	err  error
+}
+
+var _ gitprovider.OrgRepositoriesClient = &mockOrgRepositoriesClient{}
+
+func (m *mockOrgRepositoriesClient) Get(ctx context.Context, ref gitprovider.OrgRepositoryRef) (gitprovider.OrgRepository, error) {
+	m.refs = append(m.refs, ref)
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.repo != nil {
+		return m.repo, nil
+	}
+	return &mockOrgRepository{}, nil
+}
+
+type mockOrgRepository struct {
+	gitprovider.OrgRepository
+
+	deleted bool
+}
+
+func (m *mockOrgRepository) Delete(ctx context.Context) error {
+	m.deleted = true
+	return nil
+}
+
 type mockKustomizer struct {
 	out []byte
 	err error
@@ -121,7 +376,7 @@ spec:
         image: ghcr.io/user/git-controller:v1.0.0
 `)
 
-func (m *mockKustomizer) GenerateKustomizedResourceData(component string) ([]byte, error) {
+func (m *mockKustomizer) GenerateKustomizedResourceData(_ context.Context, component string) ([]byte, error) {
 	return m.out, m.err
 }
 
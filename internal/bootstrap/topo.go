@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+)
+
+// dependsOnLabel is the label of a bootstrap component reference listing the names of
+// the components that must be installed before it.
+const dependsOnLabel = "mpas.ocm.software/depends-on"
+
+// componentDependencies returns the names of the components the given reference depends on.
+func componentDependencies(ref compdesc.ComponentReference) ([]string, error) {
+	var dependsOn []string
+	if _, err := ref.Labels.GetValue(dependsOnLabel, &dependsOn); err != nil {
+		return nil, fmt.Errorf("failed to read label %s of %s: %w", dependsOnLabel, ref.GetName(), err)
+	}
+
+	return dependsOn, nil
+}
+
+// sortComponents returns the names of the given components ordered so that every component
+// comes after the components it depends on. Components without an ordering constraint between
+// them are sorted alphabetically. Dependencies on components that are not part of refs are ignored.
+func sortComponents(refs map[string]compdesc.ComponentReference) ([]string, error) {
+	inDegree := make(map[string]int, len(refs))
+	dependents := make(map[string][]string, len(refs))
+	for name, ref := range refs {
+		dependsOn, err := componentDependencies(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		inDegree[name] = 0
+		for _, dep := range dependsOn {
+			if _, ok := refs[dep]; !ok {
+				continue
+			}
+			inDegree[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	var ready []string
+	for name, degree := range inDegree {
+		if degree == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	sorted := make([]string, 0, len(refs))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, name)
+
+		for _, dependent := range dependents[name] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(sorted) != len(refs) {
+		var cyclic []string
+		for name, degree := range inDegree {
+			if degree > 0 {
+				cyclic = append(cyclic, name)
+			}
+		}
+		sort.Strings(cyclic)
+		return nil, fmt.Errorf("dependency cycle between components: %s", strings.Join(cyclic, ", "))
+	}
+
+	return sorted, nil
+}
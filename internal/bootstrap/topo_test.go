@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	metav1 "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/meta/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SortComponents(t *testing.T) {
+	ref := func(name string, dependsOn ...string) compdesc.ComponentReference {
+		r := compdesc.ComponentReference{
+			ElementMeta: compdesc.ElementMeta{Name: name},
+		}
+		if len(dependsOn) > 0 {
+			value, err := json.Marshal(dependsOn)
+			require.NoError(t, err)
+			r.Labels = metav1.Labels{{Name: dependsOnLabel, Value: value}}
+		}
+		return r
+	}
+
+	testCases := []struct {
+		name        string
+		refs        map[string]compdesc.ComponentReference
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:     "single component",
+			refs:     map[string]compdesc.ComponentReference{"ocm-controller": ref("ocm-controller")},
+			expected: []string{"ocm-controller"},
+		},
+		{
+			name: "no dependencies are sorted alphabetically",
+			refs: map[string]compdesc.ComponentReference{
+				"replication-controller": ref("replication-controller"),
+				"git-controller":         ref("git-controller"),
+				"ocm-controller":         ref("ocm-controller"),
+			},
+			expected: []string{"git-controller", "ocm-controller", "replication-controller"},
+		},
+		{
+			name: "dependency is installed first",
+			refs: map[string]compdesc.ComponentReference{
+				"git-controller": ref("git-controller", "ocm-controller"),
+				"ocm-controller": ref("ocm-controller"),
+			},
+			expected: []string{"ocm-controller", "git-controller"},
+		},
+		{
+			name: "diamond dependencies",
+			refs: map[string]compdesc.ComponentReference{
+				"mpas-project-controller": ref("mpas-project-controller", "git-controller", "replication-controller"),
+				"git-controller":          ref("git-controller", "ocm-controller"),
+				"replication-controller":  ref("replication-controller", "ocm-controller"),
+				"ocm-controller":          ref("ocm-controller"),
+			},
+			expected: []string{"ocm-controller", "git-controller", "replication-controller", "mpas-project-controller"},
+		},
+		{
+			name: "unknown dependencies are ignored",
+			refs: map[string]compdesc.ComponentReference{
+				"ocm-controller": ref("ocm-controller", "flux"),
+			},
+			expected: []string{"ocm-controller"},
+		},
+		{
+			name: "cycle",
+			refs: map[string]compdesc.ComponentReference{
+				"git-controller":         ref("git-controller", "replication-controller"),
+				"replication-controller": ref("replication-controller", "git-controller"),
+				"ocm-controller":         ref("ocm-controller"),
+			},
+			expectedErr: "dependency cycle between components: git-controller, replication-controller",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := sortComponents(tc.refs)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation name of the bootstrap spans.
+const tracerName = "github.com/open-component-model/mpas/internal/bootstrap"
+
+// WithTracerProvider sets the provider used to trace the bootstrap operations.
+// The global provider is used if not set, which is a no-op unless configured otherwise.
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = tracerProvider
+	}
+}
+
+// tracer returns the tracer of the bootstrap.
+func (b *Bootstrap) tracer() trace.Tracer {
+	if b.tracerProvider != nil {
+		return b.tracerProvider.Tracer(tracerName)
+	}
+	return otel.GetTracerProvider().Tracer(tracerName)
+}
+
+// startSpan starts a child span of the span in ctx using the same tracer provider.
+// Without a span in ctx the span is a no-op.
+func startSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// traced runs f in a child span of the span in ctx and records the error of f on the span.
+func traced(ctx context.Context, name string, f func(ctx context.Context) error) error {
+	ctx, span := startSpan(ctx, name)
+	defer span.End()
+
+	return endSpan(span, f(ctx))
+}
+
+// endSpan records err on the span and returns it.
+func endSpan(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordingTracerProvider records the names, parents and errors of the started spans.
+type recordingTracerProvider struct {
+	trace.TracerProvider
+
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+func (p *recordingTracerProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return &recordingTracer{provider: p}
+}
+
+type recordingTracer struct {
+	trace.Tracer
+
+	provider *recordingTracerProvider
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &recordingSpan{
+		Span:     trace.SpanFromContext(context.Background()),
+		provider: t.provider,
+		name:     name,
+	}
+	if parent, ok := trace.SpanFromContext(ctx).(*recordingSpan); ok {
+		span.parent = parent.name
+	}
+
+	t.provider.mu.Lock()
+	t.provider.spans = append(t.provider.spans, span)
+	t.provider.mu.Unlock()
+
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type recordingSpan struct {
+	trace.Span
+
+	provider *recordingTracerProvider
+	name     string
+	parent   string
+	err      error
+	status   codes.Code
+	ended    bool
+}
+
+func (s *recordingSpan) TracerProvider() trace.TracerProvider          { return s.provider }
+func (s *recordingSpan) RecordError(err error, _ ...trace.EventOption) { s.err = err }
+func (s *recordingSpan) SetStatus(code codes.Code, _ string)           { s.status = code }
+func (s *recordingSpan) End(...trace.SpanEndOption)                    { s.ended = true }
+
+func TestTracing(t *testing.T) {
+	tp := &recordingTracerProvider{}
+	b := &Bootstrap{}
+	WithTracerProvider(tp)(&b.options)
+
+	ctx, root := b.tracer().Start(context.Background(), "bootstrap.Run")
+
+	componentName := "ocm.software/mpas/git-controller"
+	kustomizer := NewKustomizer(&kustomizerOptions{
+		dir:           t.TempDir(),
+		repository:    newKustomizeTestRepository(componentName),
+		componentName: componentName,
+		version:       "v1.0.0",
+		host:          "ghcr.io/user",
+	})
+	_, err := kustomizer.GenerateKustomizedResourceData(ctx, componentName)
+	require.NoError(t, err)
+
+	errFailed := errors.New("failed")
+	require.ErrorIs(t, traced(ctx, "reconcileComponents", func(context.Context) error { return errFailed }), errFailed)
+	root.End()
+
+	require.Len(t, tp.spans, 4)
+	assert.Equal(t, "bootstrap.Run", tp.spans[0].name)
+	assert.Equal(t, "Kustomize.GenerateKustomizedResourceData", tp.spans[1].name)
+	assert.Equal(t, "bootstrap.Run", tp.spans[1].parent)
+	assert.Equal(t, "buildKustomization", tp.spans[2].name)
+	assert.Equal(t, "Kustomize.GenerateKustomizedResourceData", tp.spans[2].parent)
+	assert.Equal(t, "reconcileComponents", tp.spans[3].name)
+	assert.ErrorIs(t, tp.spans[3].err, errFailed)
+	assert.Equal(t, codes.Error, tp.spans[3].status)
+	for _, span := range tp.spans {
+		assert.True(t, span.ended, "span %s was not ended", span.name)
+	}
+}
+
+func TestTracingWithoutProvider(t *testing.T) {
+	// without a span in the context, child spans are no-ops
+	ctx, span := startSpan(context.Background(), "buildKustomization")
+	defer span.End()
+	assert.False(t, span.SpanContext().IsValid())
+	assert.False(t, span.IsRecording())
+	require.NoError(t, traced(ctx, "reconcileComponents", func(context.Context) error { return nil }))
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/printer"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sretry "k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ocmGroup is the API group of the ocm-controller custom resources.
+const ocmGroup = "delivery.ocm.software"
+
+// WithDestructiveUninstall sets whether Uninstall also deletes the management repository.
+// It defaults to false.
+func WithDestructiveUninstall(destructive bool) Option {
+	return func(o *options) {
+		o.destructiveUninstall = destructive
+	}
+}
+
+// uninstallStep is a single step of the uninstall.
+type uninstallStep struct {
+	msg string
+	fn  func(ctx context.Context) error
+}
+
+// Uninstall removes the installed components from the cluster.
+// The Flux sync objects are removed first so that Flux does not reinstall anything,
+// then the ocm-controller and its CRDs, and finally the Flux namespace.
+// The management repository is only deleted if WithDestructiveUninstall is set.
+func (b *Bootstrap) Uninstall(ctx context.Context) error {
+	b.log().InfoContext(ctx, "Running mpas uninstall ...", slog.String("command", "mpas uninstall"))
+
+	fluxNamespace := b.componentNamespace(env.FluxName, env.DefaultFluxNamespace)
+
+	steps := []uninstallStep{
+		{
+			msg: "Removing Flux sync objects",
+			fn:  b.deleteFluxSyncObjects,
+		},
+		{
+			msg: fmt.Sprintf("Removing %s", printer.BoldBlue(env.OcmControllerName)),
+			fn:  b.deleteOCMController,
+		},
+		{
+			msg: fmt.Sprintf("Removing %s custom resource definitions", printer.BoldBlue(env.OcmControllerName)),
+			fn:  b.deleteOCMCRDs,
+		},
+		{
+			msg: fmt.Sprintf("Removing namespace %s", printer.BoldBlue(fluxNamespace)),
+			fn: func(ctx context.Context) error {
+				return b.deleteObject(ctx, &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{Name: fluxNamespace},
+				})
+			},
+		},
+	}
+
+	if b.destructiveUninstall {
+		// the repository is resolved before anything is removed from the cluster,
+		// otherwise a missing repository would leave a half uninstalled cluster behind
+		if err := b.lookupManagementRepository(ctx); err != nil {
+			return fmt.Errorf("failed to uninstall: %w", err)
+		}
+		steps = append(steps, uninstallStep{
+			msg: fmt.Sprintf("Deleting management repository %s", printer.BoldBlue(b.repositoryName)),
+			fn:  b.DeleteManagementRepository,
+		})
+	}
+
+	for _, step := range steps {
+		if err := b.inSpinner(step.msg, func() error {
+			return step.fn(ctx)
+		}); err != nil {
+			return fmt.Errorf("failed to uninstall: %w", err)
+		}
+	}
+
+	b.logCompleted(ctx, "Uninstall completed successfully!")
+
+	return nil
+}
+
+// lookupManagementRepository sets the management repository if it is not set yet.
+// Unlike reconcileRepository it never creates the repository.
+func (b *Bootstrap) lookupManagementRepository(ctx context.Context) error {
+	if b.repository != nil {
+		return nil
+	}
+
+	if b.providerClient == nil {
+		return fmt.Errorf("management repository is not set")
+	}
+
+	subOrgs, repoName := splitSubOrganizationsFromRepositoryName(b.repositoryName)
+	// Azure DevOps repositories always belong to a project
+	if b.personal && string(b.providerClient.ProviderID()) != env.ProviderAzureDevOps {
+		repoRef := newUserRepositoryRef(newUserRef(b.providerClient.SupportedDomain(), b.owner), repoName)
+		repo, err := b.providerClient.UserRepositories().Get(ctx, repoRef)
+		if err != nil {
+			return fmt.Errorf("failed to get management repository %q: %w", repoRef.String(), err)
+		}
+		b.repository = repo
+		return nil
+	}
+
+	orgRef, err := b.getOrganization(ctx, subOrgs)
+	if err != nil {
+		return fmt.Errorf("failed to get management repository %q: %w", b.repositoryName, err)
+	}
+	repoRef := newOrgRepositoryRef(*orgRef, repoName)
+	repo, err := b.providerClient.OrgRepositories().Get(ctx, repoRef)
+	if err != nil {
+		return fmt.Errorf("failed to get management repository %q: %w", repoRef.String(), err)
+	}
+	b.repository = repo
+
+	return nil
+}
+
+func (b *Bootstrap) deleteFluxSyncObjects(ctx context.Context) error {
+	namespace := b.componentNamespace(env.FluxName, env.DefaultFluxNamespace)
+	objectMeta := metav1.ObjectMeta{
+		Name:      namespace,
+		Namespace: namespace,
+	}
+
+	if err := b.deleteObject(ctx, &kustomizev1.Kustomization{ObjectMeta: objectMeta}); err != nil {
+		return err
+	}
+
+	return b.deleteObject(ctx, &sourcev1.GitRepository{ObjectMeta: objectMeta})
+}
+
+func (b *Bootstrap) deleteOCMController(ctx context.Context) error {
+	return b.deleteObject(ctx, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      env.OcmControllerName,
+			Namespace: b.componentNamespace(env.OcmControllerName, env.DefaultOCMNamespace),
+		},
+	})
+}
+
+func (b *Bootstrap) deleteOCMCRDs(ctx context.Context) error {
+	var crds apiextensionsv1.CustomResourceDefinitionList
+	if err := b.kubeclient.List(ctx, &crds); err != nil {
+		return fmt.Errorf("failed to list custom resource definitions: %w", err)
+	}
+
+	for i := range crds.Items {
+		if crds.Items[i].Spec.Group != ocmGroup {
+			continue
+		}
+
+		if err := b.deleteObject(ctx, &crds.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteObject deletes the given object, retrying on transient errors.
+// Objects that do not exist are ignored.
+func (b *Bootstrap) deleteObject(ctx context.Context, obj client.Object) error {
+	err := k8sretry.OnError(k8sretry.DefaultBackoff, isTransientError, func() error {
+		return b.kubeclient.Delete(ctx, obj)
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %T %s: %w", obj, client.ObjectKeyFromObject(obj), err)
+	}
+
+	return nil
+}
+
+func isTransientError(err error) bool {
+	return apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsInternalError(err)
+}
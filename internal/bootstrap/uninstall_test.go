@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	kustomizev1 "github.com/fluxcd/kustomize-controller/api/v1"
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// deleteRecorder records the order in which objects are deleted.
+type deleteRecorder struct {
+	client.Client
+
+	deleted []string
+}
+
+func (d *deleteRecorder) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	d.deleted = append(d.deleted, fmt.Sprintf("%T %s", obj, obj.GetName()))
+	return d.Client.Delete(ctx, obj, opts...)
+}
+
+func TestUninstall(t *testing.T) {
+	testCases := []struct {
+		name        string
+		destructive bool
+	}{
+		{
+			name: "keeps the management repository by default",
+		},
+		{
+			name:        "deletes the management repository when destructive",
+			destructive: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme, err := kubeutils.NewScheme()
+			require.NoError(t, err)
+
+			kubeClient := &deleteRecorder{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+					&kustomizev1.Kustomization{ObjectMeta: metav1.ObjectMeta{Name: "flux-system", Namespace: "flux-system"}},
+					&sourcev1.GitRepository{ObjectMeta: metav1.ObjectMeta{Name: "flux-system", Namespace: "flux-system"}},
+					&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "ocm-controller", Namespace: "ocm-system"}},
+					&apiextensionsv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{Name: "componentversions.delivery.ocm.software"},
+						Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: "delivery.ocm.software"},
+					},
+					&apiextensionsv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{Name: "certificates.cert-manager.io"},
+						Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: "cert-manager.io"},
+					},
+					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "flux-system"}},
+				).Build(),
+			}
+
+			p, err := printer.Newprinter(&bytes.Buffer{})
+			require.NoError(t, err)
+
+			repo := &mockGitRepository{}
+			b := &Bootstrap{
+				repository: repo,
+				options: options{
+					kubeclient:           kubeClient,
+					printer:              p,
+					destructiveUninstall: tc.destructive,
+				},
+			}
+
+			require.NoError(t, b.Uninstall(context.Background()))
+
+			assert.Equal(t, []string{
+				"*v1.Kustomization flux-system",
+				"*v1.GitRepository flux-system",
+				"*v1.Deployment ocm-controller",
+				"*v1.CustomResourceDefinition componentversions.delivery.ocm.software",
+				"*v1.Namespace flux-system",
+			}, kubeClient.deleted)
+			assert.Equal(t, tc.destructive, repo.deleted)
+
+			var crds apiextensionsv1.CustomResourceDefinitionList
+			require.NoError(t, kubeClient.List(context.Background(), &crds))
+			require.Len(t, crds.Items, 1)
+			assert.Equal(t, "certificates.cert-manager.io", crds.Items[0].Name)
+		})
+	}
+}
+
+func TestUninstallComponentNamespaces(t *testing.T) {
+	scheme, err := kubeutils.NewScheme()
+	require.NoError(t, err)
+
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&kustomizev1.Kustomization{ObjectMeta: metav1.ObjectMeta{Name: "gitops", Namespace: "gitops"}},
+		&sourcev1.GitRepository{ObjectMeta: metav1.ObjectMeta{Name: "gitops", Namespace: "gitops"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "ocm-controller", Namespace: "ocm"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "gitops"}},
+	).Build()
+
+	p, err := printer.Newprinter(&bytes.Buffer{})
+	require.NoError(t, err)
+
+	b := &Bootstrap{options: options{
+		kubeclient:          kubeClient,
+		printer:             p,
+		componentNamespaces: map[string]string{"flux": "gitops", "ocm-controller": "ocm"},
+	}}
+	require.NoError(t, b.Uninstall(context.Background()))
+
+	var kustomizations kustomizev1.KustomizationList
+	require.NoError(t, kubeClient.List(context.Background(), &kustomizations))
+	assert.Empty(t, kustomizations.Items)
+	var repositories sourcev1.GitRepositoryList
+	require.NoError(t, kubeClient.List(context.Background(), &repositories))
+	assert.Empty(t, repositories.Items)
+	var deployments appsv1.DeploymentList
+	require.NoError(t, kubeClient.List(context.Background(), &deployments))
+	assert.Empty(t, deployments.Items)
+	var namespaces corev1.NamespaceList
+	require.NoError(t, kubeClient.List(context.Background(), &namespaces))
+	assert.Empty(t, namespaces.Items)
+}
+
+func TestUninstallResolvesManagementRepository(t *testing.T) {
+	testCases := []struct {
+		name      string
+		getErr    error
+		expectErr string
+	}{
+		{
+			name: "looks up the management repository before deleting it",
+		},
+		{
+			name:      "fails before removing anything if the management repository does not exist",
+			getErr:    gitprovider.ErrNotFound,
+			expectErr: "failed to get management repository",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme, err := kubeutils.NewScheme()
+			require.NoError(t, err)
+
+			kubeClient := &deleteRecorder{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "flux-system"}},
+				).Build(),
+			}
+
+			p, err := printer.Newprinter(&bytes.Buffer{})
+			require.NoError(t, err)
+
+			repo := &mockOrgRepository{}
+			repositories := &mockOrgRepositoriesClient{repo: repo, err: tc.getErr}
+			b := &Bootstrap{
+				providerClient: &mockProviderClient{
+					providerID:      "gitea",
+					domain:          "gitea.example.com",
+					orgRepositories: repositories,
+				},
+				options: options{
+					kubeclient:           kubeClient,
+					printer:              p,
+					owner:                "ocm",
+					repositoryName:       "mpas",
+					destructiveUninstall: true,
+				},
+			}
+
+			err = b.Uninstall(context.Background())
+			require.Len(t, repositories.refs, 1)
+			assert.Equal(t, "mpas", repositories.refs[0].RepositoryName)
+			if tc.expectErr != "" {
+				assert.ErrorContains(t, err, tc.expectErr)
+				assert.Empty(t, kubeClient.deleted)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, repo.deleted)
+			assert.Contains(t, kubeClient.deleted, "*v1.Namespace flux-system")
+		})
+	}
+}
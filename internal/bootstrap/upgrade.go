@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/printer"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/utils"
+)
+
+// WithForce allows UpgradeToVersion to install a version that is not newer than the installed one.
+func WithForce(force bool) Option {
+	return func(o *options) {
+		o.force = force
+	}
+}
+
+// WithAllowDowngrade allows Upgrade to install a version that is older than the installed one.
+func WithAllowDowngrade(allow bool) Option {
+	return func(o *options) {
+		o.allowDowngrade = allow
+	}
+}
+
+// ErrDowngrade is returned by Upgrade if the target version is older than the installed version.
+var ErrDowngrade = errors.New("downgrade is not allowed")
+
+// UpgradeToVersion installs the given version of the bootstrap component, e.g. flux.
+// The installed version is read from the lock file. The target version must be newer
+// unless WithForce is set. The lock file is updated with the target version.
+func (b *Bootstrap) UpgradeToVersion(ctx context.Context, component, targetVersion string) error {
+	return b.upgrade(ctx, component, targetVersion, func(installed string, target *semver.Version) (bool, error) {
+		if err := checkUpgrade(installed, target, b.force); err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+}
+
+// Upgrade upgrades the installed bootstrap component, e.g. flux, to the given version and waits
+// for the upgraded component to be healthy. The installed version is read from the lock file.
+// Nothing is done if the target version is already installed. ErrDowngrade is returned if the
+// target version is older than the installed one, unless WithAllowDowngrade is set.
+func (b *Bootstrap) Upgrade(ctx context.Context, component, targetVersion string) error {
+	return b.upgrade(ctx, component, targetVersion, func(installed string, target *semver.Version) (bool, error) {
+		return checkVersionChange(installed, target, b.allowDowngrade)
+	})
+}
+
+// upgrade installs the target version of the component if check, called with the installed
+// version, returns true, and updates the lock file.
+func (b *Bootstrap) upgrade(ctx context.Context, component, targetVersion string, check func(string, *semver.Version) (bool, error)) error {
+	target, err := semver.NewVersion(targetVersion)
+	if err != nil {
+		return fmt.Errorf("invalid target version %q: %w", targetVersion, err)
+	}
+
+	if !b.clusterOnly && b.repository == nil {
+		defer b.releaseBootstrapLockOnExit(ctx)
+		if err := b.inSpinner(fmt.Sprintf("Preparing Management repository %s",
+			printer.BoldBlue(b.repositoryName)), func() error {
+			return b.reconcileManagementRepository(ctx)
+		}); err != nil {
+			return fmt.Errorf("failed to prepare management repository: %w", err)
+		}
+	}
+
+	store := b.lockStore()
+	if store == nil {
+		return fmt.Errorf("no lock file store is configured")
+	}
+
+	lock, err := store.Read(ctx)
+	if err != nil {
+		return err
+	}
+
+	entry, err := lock.entry(component)
+	if err != nil {
+		return err
+	}
+
+	install, err := check(entry.Version, target)
+	if err != nil {
+		return fmt.Errorf("cannot upgrade %s: %w", component, err)
+	}
+	if !install {
+		b.log().InfoContext(ctx, fmt.Sprintf("%s %s is already installed", component, entry.Version))
+		return nil
+	}
+
+	octx := om.DefaultContext()
+	if _, err := utils.Configure(octx, ""); err != nil {
+		return fmt.Errorf("failed to configure ocm context: %w", err)
+	}
+	octx.LoggingContext().SetDefaultLevel(1)
+
+	ociRepo, err := b.makeOCIRepositoryWithFallback(ctx, octx)
+	if err != nil {
+		return fmt.Errorf("failed to create repository: %w", err)
+	}
+	defer ociRepo.Close()
+
+	ref := compdesc.ComponentReference{
+		ElementMeta: compdesc.ElementMeta{
+			Name:    component,
+			Version: targetVersion,
+		},
+		ComponentName: entry.ComponentName,
+	}
+
+	var sha string
+	if err := b.inSpinner(fmt.Sprintf("Upgrading %s from %s to %s",
+		printer.BoldBlue(component),
+		printer.BoldBlue(entry.Version),
+		printer.BoldBlue(targetVersion)), b.trackProgress(ProgressPhaseComponentInstall, component, func() error {
+		sha, err = b.installComponentVersion(ctx, ociRepo, ref)
+		return err
+	})); err != nil {
+		return fmt.Errorf("failed to upgrade %s: %w", component, err)
+	}
+
+	if sha != "" {
+		if err := b.inSpinner("Reconciling component manifests", func() error {
+			return b.syncManagementRepository(ctx, sha)
+		}); err != nil {
+			return err
+		}
+	}
+
+	entry.Version = targetVersion
+	entry.Digest = ""
+	if err := b.inSpinner("Writing lock file", func() error {
+		return store.Write(ctx, lock)
+	}); err != nil {
+		return fmt.Errorf("failed to write lock file: %w", err)
+	}
+
+	b.logCompleted(ctx, fmt.Sprintf("Upgraded %s to %s", component, targetVersion))
+	return nil
+}
+
+// installComponentVersion installs the referenced bootstrap component and returns the sha
+// of the commit to sync, which is empty if the component is applied directly.
+func (b *Bootstrap) installComponentVersion(ctx context.Context, ociRepo om.Repository, ref compdesc.ComponentReference) (string, error) {
+	switch ref.GetName() {
+	case env.FluxName:
+		return "", b.installFlux(ctx, ociRepo, ref)
+	case env.CertManagerName:
+		return b.installCertManager(ctx, ociRepo, ref)
+	default:
+		return b.generateControllerManifest(ctx, ociRepo, ref.GetName(), ref, make(map[string][]string))
+	}
+}
+
+// checkUpgrade returns an error if target is not newer than the installed version, unless forced.
+func checkUpgrade(installed string, target *semver.Version, force bool) error {
+	current, err := semver.NewVersion(installed)
+	if err != nil {
+		return fmt.Errorf("invalid installed version %q: %w", installed, err)
+	}
+
+	if target.GreaterThan(current) || force {
+		return nil
+	}
+
+	if target.Equal(current) {
+		return fmt.Errorf("version %s is already installed", target.Original())
+	}
+
+	return fmt.Errorf("version %s is a downgrade from the installed version %s, use force to downgrade", target.Original(), current.Original())
+}
+
+// checkVersionChange returns true if target differs from the installed version.
+// It returns ErrDowngrade if target is older, unless allowDowngrade is set.
+func checkVersionChange(installed string, target *semver.Version, allowDowngrade bool) (bool, error) {
+	current, err := semver.NewVersion(installed)
+	if err != nil {
+		return false, fmt.Errorf("invalid installed version %q: %w", installed, err)
+	}
+
+	switch {
+	case target.Equal(current):
+		return false, nil
+	case target.LessThan(current) && !allowDowngrade:
+		return false, fmt.Errorf("%w: version %s is older than the installed version %s", ErrDowngrade, target.Original(), current.Original())
+	default:
+		return true, nil
+	}
+}
@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckUpgrade(t *testing.T) {
+	testCases := []struct {
+		name        string
+		installed   string
+		target      string
+		force       bool
+		expectedErr string
+	}{
+		{
+			name:      "newer patch version",
+			installed: "v2.0.0",
+			target:    "v2.0.1",
+		},
+		{
+			name:      "newer minor version without prefix",
+			installed: "v2.0.0",
+			target:    "2.1.0",
+		},
+		{
+			name:      "release after pre-release",
+			installed: "v2.1.0-rc.1",
+			target:    "v2.1.0",
+		},
+		{
+			name:        "same version",
+			installed:   "v2.0.0",
+			target:      "v2.0.0",
+			expectedErr: "version v2.0.0 is already installed",
+		},
+		{
+			name:        "downgrade",
+			installed:   "v2.1.0",
+			target:      "v2.0.0",
+			expectedErr: "version v2.0.0 is a downgrade from the installed version v2.1.0, use force to downgrade",
+		},
+		{
+			name:      "forced downgrade",
+			installed: "v2.1.0",
+			target:    "v2.0.0",
+			force:     true,
+		},
+		{
+			name:        "invalid installed version",
+			installed:   "latest",
+			target:      "v2.0.0",
+			expectedErr: `invalid installed version "latest"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkUpgrade(tc.installed, semver.MustParse(tc.target), tc.force)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestUpgradeToVersionValidation(t *testing.T) {
+	store := NewConfigMapLockStore(fake.NewClientBuilder().Build(), "mpas-lock", "mpas-system")
+	require.NoError(t, store.Write(context.Background(), &LockFile{Components: []LockEntry{
+		{Name: "flux", ComponentName: "ocm.software/mpas/flux", Version: "v2.1.0"},
+	}}))
+
+	b := &Bootstrap{options: options{
+		clusterOnly:            true,
+		kubeclient:             store.kubeclient,
+		lockConfigMapName:      "mpas-lock",
+		lockConfigMapNamespace: "mpas-system",
+	}}
+
+	err := b.UpgradeToVersion(context.Background(), "flux", "latest")
+	assert.ErrorContains(t, err, `invalid target version "latest"`)
+
+	err = b.UpgradeToVersion(context.Background(), "ocm-controller", "v1.0.0")
+	assert.ErrorContains(t, err, "component ocm-controller is not pinned in the lock file")
+
+	err = b.UpgradeToVersion(context.Background(), "flux", "v2.0.0")
+	assert.ErrorContains(t, err, "cannot upgrade flux: version v2.0.0 is a downgrade")
+}
+
+func TestCheckVersionChange(t *testing.T) {
+	testCases := []struct {
+		name            string
+		installed       string
+		target          string
+		allowDowngrade  bool
+		expectedInstall bool
+		expectedErr     error
+	}{
+		{
+			name:            "minor version upgrade",
+			installed:       "v2.0.0",
+			target:          "v2.1.0",
+			expectedInstall: true,
+		},
+		{
+			name:      "same version",
+			installed: "v2.1.0",
+			target:    "2.1.0",
+		},
+		{
+			name:        "downgrade",
+			installed:   "v2.1.0",
+			target:      "v2.0.0",
+			expectedErr: ErrDowngrade,
+		},
+		{
+			name:            "allowed downgrade",
+			installed:       "v2.1.0",
+			target:          "v2.0.0",
+			allowDowngrade:  true,
+			expectedInstall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			install, err := checkVersionChange(tc.installed, semver.MustParse(tc.target), tc.allowDowngrade)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedInstall, install)
+		})
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	probeRegistry = func(_ context.Context, _, _ string) error {
+		return fmt.Errorf("connection refused")
+	}
+	defer func() {
+		probeRegistry = defaultProbeRegistry
+	}()
+
+	lock := &LockFile{Components: []LockEntry{
+		{Name: "flux", ComponentName: "ocm.software/mpas/flux", Version: "v2.1.0"},
+	}}
+	store := NewConfigMapLockStore(fake.NewClientBuilder().Build(), "mpas-lock", "mpas-system")
+	require.NoError(t, store.Write(context.Background(), lock))
+
+	out := &bytes.Buffer{}
+	p, err := printer.Newprinter(out)
+	require.NoError(t, err)
+
+	b := &Bootstrap{options: options{
+		clusterOnly:            true,
+		kubeclient:             store.kubeclient,
+		lockConfigMapName:      "mpas-lock",
+		lockConfigMapNamespace: "mpas-system",
+		printer:                p,
+	}}
+
+	// the installed version is not reinstalled
+	require.NoError(t, b.Upgrade(context.Background(), "flux", "v2.1.0"))
+	assert.Contains(t, out.String(), "flux v2.1.0 is already installed")
+
+	err = b.Upgrade(context.Background(), "flux", "v2.0.0")
+	assert.ErrorIs(t, err, ErrDowngrade)
+
+	// a newer version is fetched from the registry
+	err = b.Upgrade(context.Background(), "flux", "v2.2.0")
+	assert.ErrorContains(t, err, "failed to create repository")
+
+	got, err := store.Read(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, lock, got)
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SecretRef references a secret that must exist before the bootstrap runs.
+type SecretRef struct {
+	// Name is the name of the secret.
+	Name string `json:"name"`
+	// Namespace is the namespace of the secret.
+	Namespace string `json:"namespace"`
+	// Keys are the keys the secret must contain.
+	Keys []string `json:"keys,omitempty"`
+}
+
+// String returns the namespaced name of the secret.
+func (r SecretRef) String() string {
+	return fmt.Sprintf("%s/%s", r.Namespace, r.Name)
+}
+
+// SecretValidationError is a required secret that is missing or incomplete.
+type SecretValidationError struct {
+	// Secret is the reference of the invalid secret.
+	Secret SecretRef
+	// Err is the reason the secret is invalid.
+	Err error
+}
+
+// Error implements the error interface.
+func (e SecretValidationError) Error() string {
+	return fmt.Sprintf("secret %s: %s", e.Secret, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e SecretValidationError) Unwrap() error {
+	return e.Err
+}
+
+// WithRequiredSecrets sets the secrets the pre-flight checks require to exist in the cluster.
+func WithRequiredSecrets(secrets []SecretRef) Option {
+	return func(o *options) {
+		o.requiredSecrets = append(o.requiredSecrets, secrets...)
+	}
+}
+
+// ValidateSecrets checks that each required secret exists in its namespace and contains the expected keys.
+// All secrets are checked and their failures are returned together.
+func (b *Bootstrap) ValidateSecrets(ctx context.Context, required []SecretRef) []SecretValidationError {
+	var errs []SecretValidationError
+	for _, ref := range required {
+		if err := validateSecret(ctx, b.kubeclient, ref); err != nil {
+			errs = append(errs, SecretValidationError{Secret: ref, Err: err})
+		}
+	}
+
+	return errs
+}
+
+func validateSecret(ctx context.Context, kubeClient client.Client, ref SecretRef) error {
+	secret := &corev1.Secret{}
+	if err := kubeClient.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: ref.Namespace}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("not found")
+		}
+		return fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	var missing []string
+	for _, key := range ref.Keys {
+		if _, ok := secret.Data[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing keys %v", missing)
+	}
+
+	return nil
+}
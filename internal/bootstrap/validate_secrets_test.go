@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateSecrets(t *testing.T) {
+	kubeClient := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-credentials", Namespace: "ocm-system"},
+			Data:       map[string][]byte{"username": []byte("user"), "password": []byte("pass")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "pull-secret", Namespace: "ocm-system"},
+			Data:       map[string][]byte{"username": []byte("user")},
+		},
+	).Build()
+	b := &Bootstrap{options: options{kubeclient: kubeClient}}
+
+	required := []SecretRef{
+		{Name: "registry-credentials", Namespace: "ocm-system", Keys: []string{"username", "password"}},
+		{Name: "pull-secret", Namespace: "ocm-system", Keys: []string{".dockerconfigjson", "username"}},
+		{Name: "registry-credentials", Namespace: "flux-system"},
+	}
+
+	errs := b.ValidateSecrets(context.Background(), required)
+	require.Len(t, errs, 2)
+	assert.Equal(t, required[1], errs[0].Secret)
+	assert.EqualError(t, errs[0], "secret ocm-system/pull-secret: missing keys [.dockerconfigjson]")
+	assert.Equal(t, required[2], errs[1].Secret)
+	assert.EqualError(t, errs[1], "secret flux-system/registry-credentials: not found")
+
+	assert.Empty(t, b.ValidateSecrets(context.Background(), required[:1]))
+}
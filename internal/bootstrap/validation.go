@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// validationFieldManager is the field manager of the server-side dry-run applies.
+const validationFieldManager = "mpas"
+
+// WithPreApplyValidation validates the Flux manifests with a server-side dry-run apply
+// before they are committed to the management repository.
+func WithPreApplyValidation(enabled bool) Option {
+	return func(o *options) {
+		o.preApplyValidation = enabled
+	}
+}
+
+// withPreApplyValidation validates the flux manifests with a server-side dry-run before they are committed.
+func withPreApplyValidation(enabled bool) fluxOption {
+	return func(o *fluxOptions) {
+		o.preApplyValidation = enabled
+	}
+}
+
+// validateManifests applies each object of the manifests with a server-side dry-run and returns
+// the validation errors of all objects. Objects whose kind or namespace is created by the manifests
+// themselves cannot be validated before the manifests are applied and are skipped.
+func validateManifests(ctx context.Context, kubeClient client.Client, manifests []byte) error {
+	objects, err := kubeutils.YamlToUnstructructured(manifests)
+	if err != nil {
+		return fmt.Errorf("failed to parse manifests: %w", err)
+	}
+
+	var errs []error
+	for _, obj := range objects {
+		err := kubeClient.Patch(ctx, obj, client.Apply, client.FieldOwner(validationFieldManager), client.ForceOwnership, client.DryRunAll)
+		if err == nil || meta.IsNoMatchError(err) || isNamespaceNotFound(err) {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("%s %s: %w", obj.GetKind(), client.ObjectKeyFromObject(obj), err))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("manifest validation failed: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// isNamespaceNotFound returns true if err reports that the namespace of the object does not exist.
+func isNamespaceNotFound(err error) bool {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || !apierrors.IsNotFound(err) {
+		return false
+	}
+
+	details := status.Status().Details
+	return details != nil && details.Kind == "namespaces"
+}
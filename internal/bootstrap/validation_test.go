@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/gogit"
+	gogitv5 "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// validatingClient validates server-side dry-run applies like the API server would:
+// Deployments without a selector are rejected and Alerts are an unknown kind.
+type validatingClient struct {
+	client.Client
+
+	applied []string
+}
+
+func (c *validatingClient) Patch(_ context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	po := &client.PatchOptions{}
+	po.ApplyOptions(opts)
+	if patch != client.Apply || po.FieldManager != "mpas" || len(po.DryRun) != 1 || po.DryRun[0] != "All" {
+		return apierrors.NewBadRequest("expected a server-side dry-run apply")
+	}
+
+	u := obj.(*unstructured.Unstructured)
+	gvk := u.GroupVersionKind()
+	switch gvk.Kind {
+	case "Alert":
+		return &meta.NoKindMatchError{GroupKind: gvk.GroupKind()}
+	case "Deployment":
+		if _, ok, _ := unstructured.NestedMap(u.Object, "spec", "selector"); !ok {
+			return apierrors.NewInvalid(gvk.GroupKind(), u.GetName(), field.ErrorList{
+				field.Required(field.NewPath("spec", "selector"), ""),
+			})
+		}
+	case "ServiceAccount":
+		if u.GetNamespace() == "missing" {
+			return apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "missing")
+		}
+	}
+
+	c.applied = append(c.applied, u.GetName())
+	return nil
+}
+
+var invalidDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: source-controller
+  namespace: flux-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: ghcr.io/fluxcd/source-controller:v1.1.0
+`
+
+var validManifests = `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: source-controller
+  namespace: missing
+---
+apiVersion: example.com/v1
+kind: Alert
+metadata:
+  name: alert
+  namespace: flux-system
+---
+` + string(kustomizedDeployment)
+
+func TestValidateManifests(t *testing.T) {
+	c := &validatingClient{Client: fake.NewClientBuilder().Build()}
+	require.NoError(t, validateManifests(context.Background(), c, []byte(validManifests)))
+	assert.Equal(t, []string{"git-controller"}, c.applied)
+
+	err := validateManifests(context.Background(), c, []byte(validManifests+"---\n"+invalidDeployment))
+	require.ErrorContains(t, err, "manifest validation failed")
+	assert.ErrorContains(t, err, "Deployment flux-system/source-controller")
+	assert.ErrorContains(t, err, "spec.selector: Required value")
+}
+
+func TestReconcileComponentsValidationFailure(t *testing.T) {
+	bare := newBareRepository(t, 1)
+	gitClient, err := gogit.NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP}, gogit.WithDiskStorage())
+	require.NoError(t, err)
+
+	f := &fluxInstall{
+		version: "v2.0.0",
+		fluxOptions: &fluxOptions{
+			gitClient:  gitClient,
+			kubeClient: &validatingClient{Client: fake.NewClientBuilder().Build()},
+			url:        bare,
+			branch:     "main",
+		},
+	}
+	withPreApplyValidation(true)(f.fluxOptions)
+
+	err = f.reconcileComponents(context.Background(), "flux-system/gotk-components.yaml", invalidDeployment)
+	require.ErrorContains(t, err, "manifest validation failed")
+
+	// nothing is committed
+	remote, err := gogitv5.PlainOpen(bare)
+	require.NoError(t, err)
+	assert.Len(t, historyOf(t, remote), 1)
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/attrs/signingattr"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/signing"
+)
+
+// WithVerifySignatures enables the verification of the bootstrap components' signatures
+// with the public key at publicKeyPath before any of their resources is used.
+func WithVerifySignatures(publicKeyPath string) Option {
+	return func(o *options) {
+		o.publicKeyPath = publicKeyPath
+	}
+}
+
+// withVerifySignatures sets the public key used to verify the flux component's signature.
+func withVerifySignatures(publicKey []byte) fluxOption {
+	return func(o *fluxOptions) {
+		o.publicKey = publicKey
+	}
+}
+
+// verifyComponentVersion verifies that at least one signature of the component version can be
+// verified with the given public key and that the digests of all its resources match.
+// Nothing is verified if no public key is given.
+func verifyComponentVersion(cv ocm.ComponentVersionAccess, publicKey []byte) error {
+	if len(publicKey) == 0 {
+		return nil
+	}
+
+	signatures := cv.GetDescriptor().Signatures
+	if len(signatures) == 0 {
+		return fmt.Errorf("component %s:%s is not signed", cv.GetName(), cv.GetVersion())
+	}
+
+	var errs []error
+	for _, signature := range signatures {
+		opts := signing.NewOptions(
+			signing.Resolver(cv.Repository()),
+			signing.PublicKey(signature.Name, publicKey),
+			signing.VerifySignature(signature.Name),
+			signing.VerifyDigests(),
+		)
+		if err := opts.Complete(signingattr.Get(cv.GetContext())); err != nil {
+			return fmt.Errorf("failed to configure signature verification: %w", err)
+		}
+
+		if _, err := signing.Apply(nil, nil, cv, opts); err != nil {
+			errs = append(errs, fmt.Errorf("signature %s: %w", signature.Name, err))
+			continue
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("failed to verify component %s:%s: %w", cv.GetName(), cv.GetVersion(), errors.Join(errs...))
+}
+
+// componentVersionDigest returns the digest of the component version as it is recorded in the
+// component references of the bootstrap component. It is a variable to be able to stub it in tests.
+var componentVersionDigest = defaultComponentVersionDigest
+
+func defaultComponentVersionDigest(cv ocm.ComponentVersionAccess) (string, error) {
+	opts := signing.NewOptions(signing.Resolver(cv.Repository()))
+	if err := opts.Complete(signingattr.Get(cv.GetContext())); err != nil {
+		return "", fmt.Errorf("failed to configure digest calculation: %w", err)
+	}
+
+	digest, err := signing.Apply(nil, nil, cv, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate digest of component %s:%s: %w", cv.GetName(), cv.GetVersion(), err)
+	}
+
+	return digest.Value, nil
+}
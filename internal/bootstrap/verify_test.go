@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/open-component-model/mpas/internal/ocm"
+	"github.com/open-component-model/ocm-controller/pkg/fakes"
+	"github.com/open-component-model/ocm/pkg/common/accessio"
+	"github.com/open-component-model/ocm/pkg/contexts/datacontext"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/attrs/signingattr"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	metav1 "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/meta/v1"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/repositories/comparch"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/signing"
+	ocmrsa "github.com/open-component-model/ocm/pkg/signing/handlers/rsa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyComponentVersion(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	publicKey := encodePublicKey(t, &privateKey.PublicKey)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		sign        bool
+		tamper      bool
+		publicKey   []byte
+		expectedErr string
+	}{
+		{
+			name:      "known-good signature",
+			sign:      true,
+			publicKey: publicKey,
+		},
+		{
+			name:        "tampered manifest",
+			sign:        true,
+			tamper:      true,
+			publicKey:   publicKey,
+			expectedErr: "failed to verify component github.com/ocm/test:v0.1.0",
+		},
+		{
+			name:        "wrong public key",
+			sign:        true,
+			publicKey:   encodePublicKey(t, &otherKey.PublicKey),
+			expectedErr: "failed to verify component github.com/ocm/test:v0.1.0",
+		},
+		{
+			name:        "unsigned component",
+			publicKey:   publicKey,
+			expectedErr: "component github.com/ocm/test:v0.1.0 is not signed",
+		},
+		{
+			name:   "no verification by default",
+			tamper: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ca := buildTestComponentArchive(t, "manifest")
+			defer ca.Close()
+
+			if tc.sign {
+				signTestComponentArchive(t, ca, privateKey)
+			}
+			if tc.tamper {
+				require.NoError(t, ca.SetResourceBlob(&compdesc.ResourceMeta{
+					ElementMeta: compdesc.ElementMeta{Name: "manifest", Version: "v0.1.0"},
+					Relation:    metav1.LocalRelation,
+					Type:        "file",
+				}, accessio.BlobAccessForString("text/plain", "tampered"), "", nil))
+			}
+
+			err := verifyComponentVersion(ca, tc.publicKey)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func buildTestComponentArchive(t *testing.T, content string) *comparch.ComponentArchive {
+	t.Helper()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "manifest.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+
+	ca, err := ocm.New("github.com/ocm/test", "v0.1.0", "ocm", filepath.Join(dir, "archive")).
+		WithContext(om.New(datacontext.MODE_SHARED)).
+		AddFile("manifest", "v0.1.0", file).
+		Build()
+	require.NoError(t, err)
+
+	return ca
+}
+
+func signTestComponentArchive(t *testing.T, ca *comparch.ComponentArchive, privateKey *rsa.PrivateKey) {
+	t.Helper()
+
+	registry := signingattr.Get(ca.GetContext())
+	opts := signing.NewOptions(
+		signing.Sign(registry.GetSigner(ocmrsa.Algorithm), "mpas"),
+		signing.PrivateKey("mpas", privateKey),
+		signing.Resolver(ca.Repository()),
+		signing.Update(),
+		signing.VerifyDigests(),
+	)
+	require.NoError(t, opts.Complete(registry))
+
+	_, err := signing.Apply(nil, nil, ca, opts)
+	require.NoError(t, err)
+}
+
+func encodePublicKey(t *testing.T, key *rsa.PublicKey) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestFetchBootstrapComponentReferencesVerifiesSignature(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	repo := &mockRepository{
+		cv: []*mockComponentAccess{
+			{
+				name:     env.DefaultBootstrapComponent,
+				versions: []string{"v1.0.0"},
+				cva: map[string]*fakes.Component{
+					"v1.0.0": {Name: env.DefaultBootstrapComponent, Version: "v1.0.0"},
+				},
+			},
+		},
+	}
+
+	b := &Bootstrap{publicKey: encodePublicKey(t, &privateKey.PublicKey)}
+	_, err = b.fetchBootstrapComponentReferences(repo)
+	assert.ErrorContains(t, err, "is not signed")
+}
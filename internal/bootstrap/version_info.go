@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+	"text/tabwriter"
+)
+
+const (
+	mpasModule       = "github.com/open-component-model/mpas"
+	fluxModule       = "github.com/fluxcd/flux2/v2"
+	ocmModule        = "github.com/open-component-model/ocm"
+	kubeClientModule = "k8s.io/client-go"
+	unknownVersion   = "unknown"
+)
+
+// readBuildInfo is a variable to be able to stub it in tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+// PrintVersionInfo prints the versions of the MPAS library, its main dependencies
+// and the bootstrap components installed by this run.
+func (b *Bootstrap) PrintVersionInfo(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	bi, _ := readBuildInfo()
+	for _, module := range []string{mpasModule, fluxModule, ocmModule, kubeClientModule} {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\n", module, moduleVersion(bi, module)); err != nil {
+			return err
+		}
+	}
+
+	if b.state != nil {
+		for _, p := range b.state.Phases {
+			if p.Phase != phaseComponentInstall {
+				continue
+			}
+			if _, err := fmt.Fprintf(tw, "%s\t%s\n", p.ComponentName, p.Version); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tw.Flush()
+}
+
+// moduleVersion returns the version of the given module from the build info,
+// taking replace directives into account.
+func moduleVersion(bi *debug.BuildInfo, path string) string {
+	if bi == nil {
+		return unknownVersion
+	}
+
+	if bi.Main.Path == path && bi.Main.Version != "" {
+		return bi.Main.Version
+	}
+
+	for _, dep := range bi.Deps {
+		if dep.Path != path {
+			continue
+		}
+		if dep.Replace != nil {
+			return dep.Replace.Version
+		}
+		return dep.Version
+	}
+
+	return unknownVersion
+}
@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintVersionInfo(t *testing.T) {
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			Main: debug.Module{Path: mpasModule, Version: "v0.1.0"},
+			Deps: []*debug.Module{
+				{Path: fluxModule, Version: "v2.0.0-rc.3"},
+				{Path: kubeClientModule, Version: "v0.28.3", Replace: &debug.Module{Path: kubeClientModule, Version: "v0.26.3"}},
+			},
+		}, true
+	}
+	t.Cleanup(func() { readBuildInfo = debug.ReadBuildInfo })
+
+	b := &Bootstrap{state: &bootstrapState{Phases: []BootstrapState{
+		{Phase: phaseComponentInstall, ComponentName: "ocm.software/ocm-controller", Version: "v0.10.0"},
+	}}}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, b.PrintVersionInfo(out))
+	assert.Regexp(t, `github.com/open-component-model/mpas\s+v0.1.0`, out.String())
+	assert.Regexp(t, `github.com/fluxcd/flux2/v2\s+v2.0.0-rc.3`, out.String())
+	assert.Regexp(t, `github.com/open-component-model/ocm\s+unknown`, out.String())
+	assert.Regexp(t, `k8s.io/client-go\s+v0.26.3`, out.String())
+	assert.Regexp(t, `ocm.software/ocm-controller\s+v0.10.0`, out.String())
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kustypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// vpaFileName is the name of the file holding the VerticalPodAutoscalers of the Flux controllers.
+	vpaFileName = "vertical-pod-autoscalers.yaml"
+
+	// VPAUpdateModeAuto applies recommended resources by evicting pods.
+	VPAUpdateModeAuto = "Auto"
+	// VPAUpdateModeInitial applies recommended resources only to new pods.
+	VPAUpdateModeInitial = "Initial"
+	// VPAUpdateModeOff only computes recommendations.
+	VPAUpdateModeOff = "Off"
+)
+
+// WithFluxVPA adds a VerticalPodAutoscaler with the given update mode for each Flux controller.
+// The update mode is one of Auto, Initial or Off. No VerticalPodAutoscalers are created if it is empty.
+func WithFluxVPA(updateMode string) Option {
+	return func(o *options) {
+		o.fluxVPAUpdateMode = updateMode
+	}
+}
+
+// withFluxVPA adds a VerticalPodAutoscaler for each Flux controller to the kustomization.
+func withFluxVPA(updateMode string) fluxOption {
+	return func(o *fluxOptions) {
+		o.vpaUpdateMode = updateMode
+	}
+}
+
+// validateVPAUpdateMode returns an error if the update mode is not supported.
+func validateVPAUpdateMode(updateMode string) error {
+	switch updateMode {
+	case VPAUpdateModeAuto, VPAUpdateModeInitial, VPAUpdateModeOff:
+		return nil
+	default:
+		return fmt.Errorf("unsupported VPA update mode %q, must be one of %s, %s or %s",
+			updateMode, VPAUpdateModeAuto, VPAUpdateModeInitial, VPAUpdateModeOff)
+	}
+}
+
+// addVerticalPodAutoscalers writes a VerticalPodAutoscaler for each Flux controller to dir
+// and adds them to the resources of the kustomization.
+func (f *fluxInstall) addVerticalPodAutoscalers(kus *kustypes.Kustomization) error {
+	if f.vpaUpdateMode == "" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, component := range f.components {
+		data, err := yaml.Marshal(newVerticalPodAutoscaler(component, f.namespace, f.vpaUpdateMode).Object)
+		if err != nil {
+			return fmt.Errorf("failed to marshal VerticalPodAutoscaler for %s: %w", component, err)
+		}
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+
+	if err := os.WriteFile(filepath.Join(f.dir, vpaFileName), buf.Bytes(), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to write VerticalPodAutoscalers: %w", err)
+	}
+
+	kus.Resources = append(kus.Resources, "./"+vpaFileName)
+	return nil
+}
+
+// newVerticalPodAutoscaler returns a VerticalPodAutoscaler targeting the Deployment of the given Flux controller.
+// It is unstructured, as the VPA API is not part of the Kubernetes API.
+func newVerticalPodAutoscaler(component, namespace, updateMode string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "autoscaling.k8s.io/v1",
+		"kind":       "VerticalPodAutoscaler",
+		"metadata": map[string]any{
+			"name":      component,
+			"namespace": namespace,
+		},
+		"spec": map[string]any{
+			"targetRef": map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"name":       component,
+			},
+			"updatePolicy": map[string]any{
+				"updateMode": updateMode,
+			},
+		},
+	}}
+}
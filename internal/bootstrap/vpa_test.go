@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/kubeutils"
+	"github.com/open-component-model/mpas/internal/printer"
+	cfd "github.com/open-component-model/ocm-controller/pkg/configdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFluxVerticalPodAutoscalers(t *testing.T) {
+	b := &Bootstrap{}
+	WithFluxVPA(VPAUpdateModeInitial)(&b.options)
+	opts, fopts := b.newFluxOptions(t.TempDir(), nil)
+	for _, o := range fopts {
+		o(opts)
+	}
+
+	f := &fluxInstall{
+		fluxOptions: opts,
+		components:  []string{"source-controller", "kustomize-controller", "helm-controller", "notification-controller"},
+	}
+	kfile, kus, err := f.generateKustomization(bytes.NewReader(kustomizedDeployment))
+	require.NoError(t, err)
+	res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+	require.NoError(t, err)
+
+	objects, err := kubeutils.YamlToUnstructructured(res)
+	require.NoError(t, err)
+
+	var vpas []string
+	for _, obj := range objects {
+		if obj.GetKind() != "VerticalPodAutoscaler" {
+			continue
+		}
+		vpas = append(vpas, obj.GetName())
+		assert.Equal(t, "flux-system", obj.GetNamespace())
+		assert.Equal(t, "autoscaling.k8s.io/v1", obj.GetAPIVersion())
+
+		target, _, err := unstructured.NestedString(obj.Object, "spec", "targetRef", "name")
+		require.NoError(t, err)
+		assert.Equal(t, obj.GetName(), target)
+		mode, _, err := unstructured.NestedString(obj.Object, "spec", "updatePolicy", "updateMode")
+		require.NoError(t, err)
+		assert.Equal(t, VPAUpdateModeInitial, mode)
+	}
+	assert.ElementsMatch(t, f.components, vpas)
+}
+
+func TestFluxVerticalPodAutoscalersDisabled(t *testing.T) {
+	f := &fluxInstall{
+		fluxOptions: &fluxOptions{dir: t.TempDir(), namespace: "flux-system"},
+		components:  []string{"source-controller"},
+	}
+	kfile, kus, err := f.generateKustomization(bytes.NewReader(kustomizedDeployment))
+	require.NoError(t, err)
+	res, err := f.generateGOTKComponent(context.Background(), &cfd.ConfigData{}, nil, kus, kfile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(res), "VerticalPodAutoscaler")
+}
+
+func TestValidateFluxVPAUpdateMode(t *testing.T) {
+	p, err := printer.Newprinter(io.Discard)
+	require.NoError(t, err)
+
+	opts := &options{
+		repositoryName:   "mpas",
+		restClientGetter: genericclioptions.NewConfigFlags(false),
+		kubeclient:       fake.NewClientBuilder().Build(),
+		printer:          p,
+	}
+	WithFluxVPA(VPAUpdateModeOff)(opts)
+	assert.NoError(t, validateOptions(opts))
+
+	WithFluxVPA("Recreate")(opts)
+	assert.ErrorContains(t, validateOptions(opts), `unsupported VPA update mode "Recreate"`)
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severities of vulnerabilities, as reported by Trivy.
+const (
+	SeverityUnknown  = "UNKNOWN"
+	SeverityLow      = "LOW"
+	SeverityMedium   = "MEDIUM"
+	SeverityHigh     = "HIGH"
+	SeverityCritical = "CRITICAL"
+
+	// defaultMaxSeverity is the highest severity accepted if WithMaxSeverity is not set.
+	defaultMaxSeverity = SeverityHigh
+)
+
+// severities are the known severities, in ascending order.
+var severities = []string{SeverityUnknown, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// Vulnerability is a known vulnerability of an image.
+type Vulnerability struct {
+	// ID is the identifier of the vulnerability, e.g. CVE-2023-1234.
+	ID string
+	// Severity is one of UNKNOWN, LOW, MEDIUM, HIGH or CRITICAL.
+	Severity string
+	// Package is the affected package of the image.
+	Package string
+}
+
+// ScannerClient scans images for known vulnerabilities.
+//
+// To use Trivy, implement Scan with its Go library: run the image scanner of
+// github.com/aquasecurity/trivy/pkg/scanner for the image reference, e.g. with a remote image
+// artifact and a local cache, and map the DetectedVulnerability results of the report to
+// Vulnerability, using VulnerabilityID, Severity and PkgName.
+type ScannerClient interface {
+	// Scan returns the known vulnerabilities of the image with the given reference.
+	Scan(ctx context.Context, imageRef string) ([]Vulnerability, error)
+}
+
+// NopScannerClient is a ScannerClient that reports no vulnerabilities.
+type NopScannerClient struct{}
+
+var _ ScannerClient = NopScannerClient{}
+
+// Scan returns no vulnerabilities.
+func (NopScannerClient) Scan(context.Context, string) ([]Vulnerability, error) {
+	return nil, nil
+}
+
+// WithVulnerabilityScanEnabled scans the images of the flux component with the given scanner before
+// the manifests are committed. The bootstrap fails if an image has a vulnerability with a severity
+// above the one set with WithMaxSeverity.
+func WithVulnerabilityScanEnabled(scanner ScannerClient) Option {
+	return func(o *options) {
+		o.scanner = scanner
+	}
+}
+
+// WithMaxSeverity sets the highest severity of vulnerabilities accepted by the vulnerability scan.
+// It defaults to HIGH.
+func WithMaxSeverity(severity string) Option {
+	return func(o *options) {
+		o.maxSeverity = severity
+	}
+}
+
+// withVulnerabilityScan scans the images with the scanner if it is set.
+func withVulnerabilityScan(scanner ScannerClient, maxSeverity string) fluxOption {
+	return func(o *fluxOptions) {
+		o.scanner = scanner
+		o.maxSeverity = maxSeverity
+	}
+}
+
+// severityRank returns the position of the severity in severities, or -1 if it is unknown.
+func severityRank(severity string) int {
+	for i, s := range severities {
+		if strings.EqualFold(s, severity) {
+			return i
+		}
+	}
+	return -1
+}
+
+// scanImages scans the given images and returns an error listing the images with vulnerabilities
+// above maxSeverity. Unknown severities of vulnerabilities are treated as UNKNOWN.
+func scanImages(ctx context.Context, scanner ScannerClient, images map[string]nameTag, maxSeverity string) error {
+	if maxSeverity == "" {
+		maxSeverity = defaultMaxSeverity
+	}
+	maxRank := severityRank(maxSeverity)
+	if maxRank < 0 {
+		return fmt.Errorf("unknown severity %q", maxSeverity)
+	}
+
+	names := make([]string, 0, len(images))
+	for name := range images {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var affected []string
+	for _, name := range names {
+		ref := imageRef(images[name])
+		vulnerabilities, err := scanner.Scan(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("failed to scan image %s: %w", ref, err)
+		}
+
+		var ids []string
+		for _, v := range vulnerabilities {
+			if severityRank(v.Severity) > maxRank {
+				ids = append(ids, fmt.Sprintf("%s (%s)", v.ID, strings.ToUpper(v.Severity)))
+			}
+		}
+		if len(ids) > 0 {
+			affected = append(affected, fmt.Sprintf("%s: %s", ref, strings.Join(ids, ", ")))
+		}
+	}
+
+	if len(affected) > 0 {
+		return fmt.Errorf("images have vulnerabilities above severity %s:\n%s", maxSeverity, strings.Join(affected, "\n"))
+	}
+
+	return nil
+}
+
+// imageRef returns the reference of the image, pinned to its digest if known.
+func imageRef(image nameTag) string {
+	ref := image.Name
+	if image.Tag != "" {
+		ref += ":" + image.Tag
+	}
+	if image.Digest != "" {
+		ref += "@" + image.Digest
+	}
+	return ref
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/open-component-model/mpas/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type mockScannerClient struct {
+	vulnerabilities map[string][]Vulnerability
+	err             error
+	scanned         []string
+}
+
+func (m *mockScannerClient) Scan(_ context.Context, imageRef string) ([]Vulnerability, error) {
+	m.scanned = append(m.scanned, imageRef)
+	return m.vulnerabilities[imageRef], m.err
+}
+
+func TestScanImages(t *testing.T) {
+	images := map[string]nameTag{
+		"source-controller":    {Name: "ghcr.io/fluxcd/source-controller", Tag: "v1.1.0", Digest: "sha256:abc"},
+		"kustomize-controller": {Name: "ghcr.io/fluxcd/kustomize-controller", Tag: "v1.1.0"},
+	}
+	vulnerabilities := map[string][]Vulnerability{
+		"ghcr.io/fluxcd/source-controller:v1.1.0@sha256:abc": {
+			{ID: "CVE-2023-0001", Severity: "CRITICAL", Package: "openssl"},
+			{ID: "CVE-2023-0002", Severity: "medium", Package: "zlib"},
+		},
+		"ghcr.io/fluxcd/kustomize-controller:v1.1.0": {
+			{ID: "CVE-2023-0003", Severity: "HIGH", Package: "golang.org/x/net"},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		maxSeverity string
+		expectedErr string
+	}{
+		{
+			name: "default threshold",
+			expectedErr: "images have vulnerabilities above severity HIGH:\n" +
+				"ghcr.io/fluxcd/source-controller:v1.1.0@sha256:abc: CVE-2023-0001 (CRITICAL)",
+		},
+		{
+			name:        "medium threshold",
+			maxSeverity: SeverityMedium,
+			expectedErr: "images have vulnerabilities above severity MEDIUM:\n" +
+				"ghcr.io/fluxcd/kustomize-controller:v1.1.0: CVE-2023-0003 (HIGH)\n" +
+				"ghcr.io/fluxcd/source-controller:v1.1.0@sha256:abc: CVE-2023-0001 (CRITICAL)",
+		},
+		{
+			name:        "critical threshold",
+			maxSeverity: SeverityCritical,
+		},
+		{
+			name:        "unknown threshold",
+			maxSeverity: "SEVERE",
+			expectedErr: `unknown severity "SEVERE"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := &mockScannerClient{vulnerabilities: vulnerabilities}
+			err := scanImages(context.Background(), scanner, images, tc.maxSeverity)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, scanner.scanned, 2)
+		})
+	}
+
+	err := scanImages(context.Background(), &mockScannerClient{err: errors.New("database not found")}, images, "")
+	assert.ErrorContains(t, err, "failed to scan image ghcr.io/fluxcd/kustomize-controller:v1.1.0: database not found")
+
+	assert.NoError(t, scanImages(context.Background(), NopScannerClient{}, images, ""))
+}
+
+func TestValidateMaxSeverity(t *testing.T) {
+	p, err := printer.Newprinter(io.Discard)
+	require.NoError(t, err)
+
+	opts := &options{
+		repositoryName:   "mpas",
+		restClientGetter: genericclioptions.NewConfigFlags(false),
+		kubeclient:       fake.NewClientBuilder().Build(),
+		printer:          p,
+	}
+	WithMaxSeverity("critical")(opts)
+	assert.NoError(t, validateOptions(opts))
+
+	WithMaxSeverity("severe")(opts)
+	assert.ErrorContains(t, validateOptions(opts), `unknown severity "severe"`)
+}
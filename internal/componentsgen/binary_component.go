@@ -68,7 +68,7 @@ func (b *Binary) Get(ctx context.Context, tmpDir string) error {
 }
 
 func (b *Binary) fetchBinary(ctx context.Context) ([]byte, error) {
-	resp, err := getFrom(ctx, b.BinURL)
+	resp, err := getFrom(ctx, nil, b.BinURL)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +91,7 @@ func (b *Binary) fetchBinary(ctx context.Context) ([]byte, error) {
 }
 
 func (b *Binary) fetchHash(ctx context.Context) (string, error) {
-	resp, err := getFrom(ctx, b.HashURL)
+	resp, err := getFrom(ctx, nil, b.HashURL)
 	if err != nil {
 		return "", err
 	}
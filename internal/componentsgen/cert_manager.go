@@ -36,7 +36,7 @@ type CertManager struct {
 // If the version is invalid, an error is returned.
 func (c *CertManager) GenerateManifests(ctx context.Context, tmpDir string) error {
 	if c.Version == "latest" {
-		latest, err := getLatestVersion(ctx, certManagerReleaseAPIURL)
+		latest, err := getLatestVersion(ctx, nil, certManagerReleaseAPIURL)
 		if err != nil {
 			return fmt.Errorf("failed to retrieve latest version for %s: %s", "cert-manager", err)
 		}
@@ -44,12 +44,12 @@ func (c *CertManager) GenerateManifests(ctx context.Context, tmpDir string) erro
 		c.Version = latest
 	}
 
-	if err := validateVersion(ctx, c.Version, certManagerReleaseAPIURL, "cert-manager"); err != nil {
+	if err := validateVersion(ctx, nil, c.Version, certManagerReleaseAPIURL, "cert-manager"); err != nil {
 		return fmt.Errorf("invalid version: %w", err)
 	}
 
 	tmpDir = filepath.Join(tmpDir, "cert-manager")
-	content, err := fetch(ctx, certManagerRepoURL, c.Version, tmpDir, "cert-manager.yaml")
+	content, err := fetch(ctx, nil, certManagerRepoURL, c.Version, tmpDir, "cert-manager.yaml")
 	if err != nil {
 		return fmt.Errorf("install failed: %w", err)
 	}
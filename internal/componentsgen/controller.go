@@ -8,6 +8,7 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"net/http"
 	"path/filepath"
 	"strings"
 
@@ -36,13 +37,16 @@ type Controller struct {
 	ReleaseAPIURL string
 	// Content is the content of the install.yaml file.
 	Content string
+	// HTTPClient is the client used to check the version and download the manifests.
+	// http.DefaultClient is used if it is nil.
+	HTTPClient *http.Client
 }
 
 // GenerateManifests downloads the install.yaml file and writes it to a temporary directory.
 // It validates the version and returns an error if the version does not exist.
 func (o *Controller) GenerateManifests(ctx context.Context, tmpDir string) error {
 	if o.Version == "latest" {
-		latest, err := getLatestVersion(ctx, o.ReleaseAPIURL)
+		latest, err := getLatestVersion(ctx, o.HTTPClient, o.ReleaseAPIURL)
 		if err != nil {
 			return fmt.Errorf("failed to retrieve latest version for %s: %s", o.Name, err)
 		}
@@ -50,12 +54,12 @@ func (o *Controller) GenerateManifests(ctx context.Context, tmpDir string) error
 		o.Version = latest
 	}
 
-	if err := validateVersion(ctx, o.Version, o.ReleaseAPIURL, o.Name); err != nil {
+	if err := validateVersion(ctx, o.HTTPClient, o.Version, o.ReleaseAPIURL, o.Name); err != nil {
 		return err
 	}
 
 	tmpDir = filepath.Join(tmpDir, o.Name)
-	content, err := fetch(ctx, o.ReleaseURL, o.Version, tmpDir, "install.yaml")
+	content, err := fetch(ctx, o.HTTPClient, o.ReleaseURL, o.Version, tmpDir, "install.yaml")
 	if err != nil {
 		return fmt.Errorf("failed to download install.yaml file: %w", err)
 	}
@@ -0,0 +1,79 @@
+//go:build integration
+
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package componentsgen
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/open-component-model/mpas/internal/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenTransport authenticates requests against the GitHub API to avoid its rate limits.
+type tokenTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if req.URL.Host == "api.github.com" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// Test_ControllerGitHubRelease fetches a known public release from GitHub.
+// Run it with: go test -tags integration ./internal/componentsgen/...
+func Test_ControllerGitHubRelease(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		t.Skip("skipping integration test, GITHUB_TOKEN is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	const (
+		name    = env.GitControllerName
+		version = "v0.9.0"
+	)
+	tmpDir := t.TempDir()
+	c := &Controller{
+		Name:          name,
+		Version:       version,
+		ReleaseURL:    "https://github.com/open-component-model/" + name + "/releases",
+		ReleaseAPIURL: "https://api.github.com/repos/open-component-model/" + name + "/releases",
+		HTTPClient:    &http.Client{Transport: &tokenTransport{token: token, base: http.DefaultTransport}},
+	}
+	require.NoError(t, c.GenerateManifests(ctx, tmpDir))
+
+	assert.Equal(t, filepath.Join(name, "install.yaml"), c.GetPath())
+	assert.FileExists(t, filepath.Join(tmpDir, c.GetPath()))
+	assert.Contains(t, c.Content, "kind: Deployment")
+
+	locs, err := c.GenerateLocalizationFromTemplate(localizationTemplateHeader, ocmlocalizationTemplate)
+	require.NoError(t, err)
+	assert.Contains(t, locs, "localization:\n- name: "+name)
+	assert.Contains(t, locs, "resource:\n  name: "+name)
+
+	images, err := c.GenerateImages()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		env.DefaultOCMHost + "/" + name + ":" + version: {name, version},
+	}, images)
+}
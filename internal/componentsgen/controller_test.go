@@ -8,6 +8,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/open-component-model/mpas/internal/env"
@@ -114,3 +115,55 @@ func Test_Controller(t *testing.T) {
 		})
 	}
 }
+
+// recordingTransport sends all requests to target and records their paths.
+type recordingTransport struct {
+	target *url.URL
+	paths  []string
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.paths = append(r.paths, req.URL.Path)
+	req = req.Clone(req.Context())
+	req.URL.Scheme = r.target.Scheme
+	req.URL.Host = r.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func Test_ControllerHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/releases/download/v0.1.0/install.yaml":
+			_, _ = w.Write([]byte(deployment))
+		case "/api/tags/v0.1.0":
+			_, _ = w.Write([]byte(`{"name": "v0.1.0"}`))
+		case "/api/latest":
+			_, _ = w.Write([]byte(`{"tag_name": "v0.1.0"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	transport := &recordingTransport{target: target}
+
+	// the host does not resolve, so the requests only succeed with the custom client
+	c := &Controller{
+		Name:          "git-controller",
+		Version:       "latest",
+		ReleaseAPIURL: "http://github.invalid/api",
+		ReleaseURL:    "http://github.invalid/releases",
+		HTTPClient:    &http.Client{Transport: transport},
+	}
+	require.NoError(t, c.GenerateManifests(context.Background(), t.TempDir()))
+
+	assert.Equal(t, "v0.1.0", c.Version)
+	assert.Equal(t, deployment, c.Content)
+	assert.Equal(t, []string{
+		"/api/latest",
+		"/api/tags/v0.1.0",
+		"/releases/download/v0.1.0/install.yaml",
+	}, transport.paths)
+}
@@ -36,7 +36,7 @@ type ExternalSecrets struct {
 // If the version is invalid, an error is returned.
 func (c *ExternalSecrets) GenerateManifests(ctx context.Context, tmpDir string) error {
 	if c.Version == "latest" {
-		latest, err := getLatestVersion(ctx, externalSecretsReleaseAPIURL)
+		latest, err := getLatestVersion(ctx, nil, externalSecretsReleaseAPIURL)
 		if err != nil {
 			return fmt.Errorf("failed to retrieve latest version for %s: %s", "external-secrets", err)
 		}
@@ -44,12 +44,12 @@ func (c *ExternalSecrets) GenerateManifests(ctx context.Context, tmpDir string)
 		c.Version = latest
 	}
 
-	if err := validateVersion(ctx, c.Version, externalSecretsReleaseAPIURL, "external-secrets"); err != nil {
+	if err := validateVersion(ctx, nil, c.Version, externalSecretsReleaseAPIURL, "external-secrets"); err != nil {
 		return fmt.Errorf("invalid version: %w", err)
 	}
 
 	tmpDir = filepath.Join(tmpDir, "external-secrets")
-	content, err := fetch(ctx, externalSecretsRepoURL, c.Version, tmpDir, "external-secrets.yaml")
+	content, err := fetch(ctx, nil, externalSecretsRepoURL, c.Version, tmpDir, "external-secrets.yaml")
 	if err != nil {
 		return fmt.Errorf("install failed: %w", err)
 	}
@@ -27,7 +27,7 @@ func Test_FluxLastVersion(t *testing.T) {
 	baseURL, err := url.Parse(install.MakeDefaultOptions().BaseURL)
 	require.NoError(t, err)
 	apiURL += baseURL.Path
-	latest, err := getLatestVersion(context.Background(), apiURL)
+	latest, err := getLatestVersion(context.Background(), nil, apiURL)
 	require.NoError(t, err)
 	f := &Flux{
 		Version: latest,
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package componentsgen
+
+import (
+	"bytes"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// networkPoliciesFileName is the name of the file the network policies are written to.
+	networkPoliciesFileName = "network-policies.yaml"
+	// sourceControllerName is the name of the Flux controller fetching from the controllers.
+	sourceControllerName = "source-controller"
+)
+
+var (
+	// controllerIngressPorts are the HTTP and webhook ports the controllers serve on.
+	controllerIngressPorts = []int{8080, 9443}
+	// registryEgressPorts are the ports of the OCI registries the controllers pull from.
+	registryEgressPorts = []int{443}
+	// dnsPort is the port of the cluster DNS, which is required to resolve the registries.
+	dnsPort = 53
+)
+
+// GenerateNetworkPolicies writes network-policies.yaml with the NetworkPolicies of the controller to dir.
+// The ingress policy allows traffic from the Flux source-controller to the ports of the controller,
+// the egress policy allows traffic to OCI registries and the cluster DNS.
+func (o *Controller) GenerateNetworkPolicies(dir string) error {
+	if o.Name == "" {
+		return fmt.Errorf("controller name is empty")
+	}
+
+	podSelector := map[string]any{
+		"matchLabels": map[string]string{
+			"app": o.Name,
+		},
+	}
+
+	ingressPorts := make([]map[string]any, 0, len(controllerIngressPorts))
+	for _, port := range controllerIngressPorts {
+		ingressPorts = append(ingressPorts, map[string]any{"protocol": "TCP", "port": port})
+	}
+
+	egressPorts := make([]map[string]any, 0, len(registryEgressPorts))
+	for _, port := range registryEgressPorts {
+		egressPorts = append(egressPorts, map[string]any{"protocol": "TCP", "port": port})
+	}
+
+	policies := []map[string]any{
+		{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind":       "NetworkPolicy",
+			"metadata": map[string]any{
+				"name": fmt.Sprintf("%s-allow-%s", o.Name, sourceControllerName),
+			},
+			"spec": map[string]any{
+				"podSelector": podSelector,
+				"policyTypes": []string{"Ingress"},
+				"ingress": []map[string]any{
+					{
+						"from": []map[string]any{
+							{
+								// source-controller may run in another namespace, e.g. flux-system
+								"namespaceSelector": map[string]any{},
+								"podSelector": map[string]any{
+									"matchLabels": map[string]string{
+										"app": sourceControllerName,
+									},
+								},
+							},
+						},
+						"ports": ingressPorts,
+					},
+				},
+			},
+		},
+		{
+			"apiVersion": "networking.k8s.io/v1",
+			"kind":       "NetworkPolicy",
+			"metadata": map[string]any{
+				"name": fmt.Sprintf("%s-allow-registry", o.Name),
+			},
+			"spec": map[string]any{
+				"podSelector": podSelector,
+				"policyTypes": []string{"Egress"},
+				"egress": []map[string]any{
+					{
+						"ports": egressPorts,
+					},
+					{
+						"ports": []map[string]any{
+							{"protocol": "UDP", "port": dnsPort},
+							{"protocol": "TCP", "port": dnsPort},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	for _, policy := range policies {
+		content, err := yaml.Marshal(policy)
+		if err != nil {
+			return fmt.Errorf("failed to marshal network policy for %s: %w", o.Name, err)
+		}
+		buf.WriteString("---\n")
+		buf.Write(content)
+	}
+
+	if err := writeFile(dir, networkPoliciesFileName, buf.String()); err != nil {
+		return fmt.Errorf("failed to write network policies: %w", err)
+	}
+
+	return nil
+}
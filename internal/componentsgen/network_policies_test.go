@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package componentsgen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGenerateNetworkPolicies(t *testing.T) {
+	dir := t.TempDir()
+	c := &Controller{Name: "ocm-controller", Version: "v0.1.0"}
+	require.NoError(t, c.GenerateNetworkPolicies(dir))
+
+	content, err := os.ReadFile(filepath.Join(dir, "network-policies.yaml"))
+	require.NoError(t, err)
+
+	var policies []networkingv1.NetworkPolicy
+	for _, doc := range strings.Split(string(content), "---\n") {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		policy := networkingv1.NetworkPolicy{}
+		require.NoError(t, yaml.UnmarshalStrict([]byte(doc), &policy))
+		policies = append(policies, policy)
+	}
+	require.Len(t, policies, 2)
+
+	tcp, udp := corev1.ProtocolTCP, corev1.ProtocolUDP
+	port := func(protocol *corev1.Protocol, p int) networkingv1.NetworkPolicyPort {
+		v := intstr.FromInt(p)
+		return networkingv1.NetworkPolicyPort{Protocol: protocol, Port: &v}
+	}
+
+	ingress := policies[0]
+	assert.Equal(t, "NetworkPolicy", ingress.Kind)
+	assert.Equal(t, "ocm-controller-allow-source-controller", ingress.Name)
+	assert.Equal(t, map[string]string{"app": "ocm-controller"}, ingress.Spec.PodSelector.MatchLabels)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, ingress.Spec.PolicyTypes)
+	require.Len(t, ingress.Spec.Ingress, 1)
+	require.Len(t, ingress.Spec.Ingress[0].From, 1)
+	assert.Equal(t, map[string]string{"app": "source-controller"}, ingress.Spec.Ingress[0].From[0].PodSelector.MatchLabels)
+	assert.NotNil(t, ingress.Spec.Ingress[0].From[0].NamespaceSelector)
+	assert.Equal(t, []networkingv1.NetworkPolicyPort{port(&tcp, 8080), port(&tcp, 9443)}, ingress.Spec.Ingress[0].Ports)
+
+	egress := policies[1]
+	assert.Equal(t, "ocm-controller-allow-registry", egress.Name)
+	assert.Equal(t, map[string]string{"app": "ocm-controller"}, egress.Spec.PodSelector.MatchLabels)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}, egress.Spec.PolicyTypes)
+	require.Len(t, egress.Spec.Egress, 2)
+	assert.Equal(t, []networkingv1.NetworkPolicyPort{port(&tcp, 443)}, egress.Spec.Egress[0].Ports)
+	assert.Equal(t, []networkingv1.NetworkPolicyPort{port(&udp, 53), port(&tcp, 53)}, egress.Spec.Egress[1].Ports)
+}
+
+func TestGenerateNetworkPoliciesWithoutName(t *testing.T) {
+	err := (&Controller{}).GenerateNetworkPolicies(t.TempDir())
+	assert.EqualError(t, err, "controller name is empty")
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package componentsgen
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// metricsPortName is the name of the service port the controllers expose their metrics on.
+	metricsPortName = "metrics"
+	// metricsPath is the path the controllers serve their metrics under.
+	metricsPath = "/metrics"
+)
+
+// GenerateServiceMonitor returns a Prometheus operator ServiceMonitor scraping the metrics port
+// of the controller in the given namespace. The prometheusLabels are added to the ServiceMonitor
+// so it is selected by the Prometheus instance.
+func (o *Controller) GenerateServiceMonitor(namespace string, prometheusLabels map[string]string) ([]byte, error) {
+	if o.Name == "" {
+		return nil, fmt.Errorf("controller name is empty")
+	}
+
+	metadata := map[string]any{
+		"name":      o.Name,
+		"namespace": namespace,
+	}
+	if len(prometheusLabels) > 0 {
+		metadata["labels"] = prometheusLabels
+	}
+
+	serviceMonitor := map[string]any{
+		"apiVersion": "monitoring.coreos.com/v1",
+		"kind":       "ServiceMonitor",
+		"metadata":   metadata,
+		"spec": map[string]any{
+			"namespaceSelector": map[string]any{
+				"matchNames": []string{namespace},
+			},
+			"selector": map[string]any{
+				"matchLabels": map[string]string{
+					"app": o.Name,
+				},
+			},
+			"endpoints": []map[string]any{
+				{
+					"port": metricsPortName,
+					"path": metricsPath,
+				},
+			},
+		},
+	}
+
+	content, err := yaml.Marshal(serviceMonitor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal service monitor for %s: %w", o.Name, err)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package componentsgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateServiceMonitor(t *testing.T) {
+	c := &Controller{Name: "git-controller", Version: "v0.1.0"}
+
+	content, err := c.GenerateServiceMonitor("ocm-system", map[string]string{"release": "prometheus"})
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  labels:
+    release: prometheus
+  name: git-controller
+  namespace: ocm-system
+spec:
+  endpoints:
+  - path: /metrics
+    port: metrics
+  namespaceSelector:
+    matchNames:
+    - ocm-system
+  selector:
+    matchLabels:
+      app: git-controller
+`, string(content))
+
+	content, err = c.GenerateServiceMonitor("ocm-system", nil)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "labels:\n    release")
+
+	_, err = (&Controller{}).GenerateServiceMonitor("ocm-system", nil)
+	assert.EqualError(t, err, "controller name is empty")
+}
@@ -18,13 +18,13 @@ import (
 	securejoin "github.com/cyphar/filepath-securejoin"
 )
 
-func fetch(ctx context.Context, url, version, dir, filename string) ([]byte, error) {
+func fetch(ctx context.Context, client *http.Client, url, version, dir, filename string) ([]byte, error) {
 	ghURL := fmt.Sprintf("%s/latest/download/%s", url, filename)
 	if strings.HasPrefix(version, "v") {
 		ghURL = fmt.Sprintf("%s/download/%s/%s", url, version, filename)
 	}
 
-	resp, err := getFrom(ctx, ghURL)
+	resp, err := getFrom(ctx, client, ghURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from url: %w", err)
 	}
@@ -47,20 +47,24 @@ func fetch(ctx context.Context, url, version, dir, filename string) ([]byte, err
 	return content, nil
 }
 
-func getFrom(ctx context.Context, ghURL string) (*http.Response, error) {
+// getFrom sends a GET request to ghURL with the client, or http.DefaultClient if it is nil.
+func getFrom(ctx context.Context, client *http.Client, ghURL string) (*http.Response, error) {
 	req, err := http.NewRequest(http.MethodGet, ghURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request for %s, error: %w", ghURL, err)
 	}
 
-	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to download manifests.tar.gz from %s, error: %w", ghURL, err)
 	}
 	return resp, nil
 }
 
-func validateVersion(ctx context.Context, version, url, name string) error {
+func validateVersion(ctx context.Context, client *http.Client, version, url, name string) error {
 	ver := version
 	if ver == "" {
 		return fmt.Errorf("version is empty")
@@ -71,7 +75,7 @@ func validateVersion(ctx context.Context, version, url, name string) error {
 	}
 
 	ghURL := fmt.Sprintf(url+"/tags/%s", ver)
-	resp, err := getFrom(ctx, ghURL)
+	resp, err := getFrom(ctx, client, ghURL)
 	if err != nil {
 		return err
 	}
@@ -90,9 +94,9 @@ func validateVersion(ctx context.Context, version, url, name string) error {
 	}
 }
 
-func getLatestVersion(ctx context.Context, releaseAPIURL string) (string, error) {
+func getLatestVersion(ctx context.Context, client *http.Client, releaseAPIURL string) (string, error) {
 	ghURL := fmt.Sprintf("%s/latest", releaseAPIURL)
-	resp, err := getFrom(ctx, ghURL)
+	resp, err := getFrom(ctx, client, ghURL)
 	if err != nil {
 		return "", err
 	}
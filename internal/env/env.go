@@ -78,6 +78,8 @@ const (
 	ProviderGitea = "gitea"
 	// ProviderGitlab is the gitlab provider.
 	ProviderGitlab = "gitlab"
+	// ProviderAzureDevOps is the Azure DevOps Repos provider.
+	ProviderAzureDevOps = "azuredevops"
 )
 
 const (
@@ -7,12 +7,15 @@ package fs
 import (
 	"archive/tar"
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
 )
 
 // CreateArchive creates a tar gzip archive from the given source directory.
@@ -101,3 +104,60 @@ func tarGzip(src string, writers ...io.Writer) error {
 		return f.Close()
 	})
 }
+
+// ExtractArchive extracts the tar gzip archive read from r into the dst directory.
+// Entries are kept within dst, anything that is not a file or directory is ignored.
+func ExtractArchive(r io.Reader, dst string) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read gzip archive: %w", err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %w", err)
+		}
+
+		target, err := securejoin.SecureJoin(dst, header.Name)
+		if err != nil {
+			return fmt.Errorf("invalid path %q in archive: %w", header.Name, err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return fmt.Errorf("failed to create directory %q: %w", target, err)
+			}
+		case tar.TypeReg:
+			if err := extractFile(tr, target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func extractFile(r io.Reader, target string) (err error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %q: %w", filepath.Dir(target), err)
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create file %q: %w", target, err)
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return fmt.Errorf("failed to extract file %q: %w", target, err)
+	}
+
+	return nil
+}
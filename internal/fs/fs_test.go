@@ -5,11 +5,15 @@
 package fs
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"os"
 	"path"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_CreateArchive(t *testing.T) {
@@ -68,6 +72,50 @@ func Test_CreateArchive(t *testing.T) {
 	}
 }
 
+func Test_ExtractArchive(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(path.Join(src, "blobs", "sha256"), 0o755))
+	require.NoError(t, os.WriteFile(path.Join(src, "index.json"), []byte("{}"), 0o600))
+	require.NoError(t, os.WriteFile(path.Join(src, "blobs", "sha256", "abc"), []byte("blob"), 0o600))
+
+	archivePath, err := CreateArchive(src, "extract-test.tar.gz")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.Remove(archivePath) })
+
+	f, err := os.Open(archivePath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	dst := t.TempDir()
+	require.NoError(t, ExtractArchive(f, dst))
+
+	data, err := os.ReadFile(path.Join(dst, "index.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(data))
+	data, err = os.ReadFile(path.Join(dst, "blobs", "sha256", "abc"))
+	require.NoError(t, err)
+	assert.Equal(t, "blob", string(data))
+}
+
+func Test_ExtractArchiveKeepsEntriesInDestination(t *testing.T) {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	content := []byte("escaped")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../../escaped", Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+	_, err := tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+
+	parent := t.TempDir()
+	dst := path.Join(parent, "dst")
+	require.NoError(t, ExtractArchive(&buf, dst))
+
+	assert.FileExists(t, path.Join(dst, "escaped"))
+	assert.NoFileExists(t, path.Join(parent, "escaped"))
+}
+
 func createDir(tmpDir, dir string) error {
 	err := os.Mkdir(path.Join(tmpDir, dir), 0o755)
 	if err != nil {
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package kubeutils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// crdPollInterval and crdTimeout configure the wait for CRDs to be established.
+	crdPollInterval = 2 * time.Second
+	crdTimeout      = time.Minute
+)
+
+// SeparateCRDs splits the multi-document YAML manifests into the CustomResourceDefinitions
+// and the remaining objects. Either is empty if the manifests contain no such objects.
+func SeparateCRDs(manifests []byte) (crds, rest []byte, err error) {
+	objs, err := YamlToUnstructructured(manifests)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+
+	isCRD := func(obj *unstructured.Unstructured) bool {
+		return obj.GetKind() == "CustomResourceDefinition"
+	}
+
+	if crdObjs := FilterUnstructured(objs, isCRD); len(crdObjs) > 0 {
+		if crds, err = UnstructuredToYaml(crdObjs); err != nil {
+			return nil, nil, fmt.Errorf("failed to write CRDs: %w", err)
+		}
+	}
+
+	restObjs := FilterUnstructured(objs, func(obj *unstructured.Unstructured) bool { return !isCRD(obj) })
+	if len(restObjs) > 0 {
+		if rest, err = UnstructuredToYaml(restObjs); err != nil {
+			return nil, nil, fmt.Errorf("failed to write manifests: %w", err)
+		}
+	}
+
+	return crds, rest, nil
+}
+
+// WaitForCRDEstablished waits until the CustomResourceDefinitions with the given names are established.
+func WaitForCRDEstablished(ctx context.Context, kubeClient client.Client, names []string) error {
+	for _, name := range names {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := wait.PollImmediateWithContext(ctx, crdPollInterval, crdTimeout, func(ctx context.Context) (bool, error) {
+			if err := kubeClient.Get(ctx, client.ObjectKey{Name: name}, crd); err != nil {
+				return false, err
+			}
+			return isEstablished(crd), nil
+		}); err != nil {
+			return fmt.Errorf("failed to wait for CustomResourceDefinition %s to be established: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// isEstablished returns true if the Established condition of the CRD is true.
+func isEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, c := range crd.Status.Conditions {
+		if c.Type == apiextensionsv1.Established {
+			return c.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ocm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/open-component-model/ocm/pkg/common/accessio"
+	"github.com/open-component-model/ocm/pkg/common/accessobj"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/attrs/compatattr"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc"
+	metav1 "github.com/open-component-model/ocm/pkg/contexts/ocm/compdesc/meta/v1"
+	"github.com/open-component-model/ocm/pkg/contexts/ocm/repositories/comparch"
+)
+
+// ComponentArchiveBuilder builds a component archive using a fluent API.
+// Errors are collected while adding resources and returned by Build.
+//
+//	ca, err := New(name, version, provider, outDir).
+//		AddFile("manifests", "v1.0.0", "install.yaml").
+//		AddOCIImage("controller", "v1.0.0", "ghcr.io/org/controller:v1.0.0").
+//		AddHelmChart("chart", "v1.0.0", "ghcr.io/org/charts/controller:v1.0.0").
+//		Build()
+type ComponentArchiveBuilder struct {
+	octx       ocm.Context
+	name       string
+	version    string
+	provider   string
+	outDir     string
+	skipDigest bool
+	maxSize    int64
+	mimeTypes  []string
+	files      []*addFileOpts
+	images     []*addImageOpts
+	charts     []*addHelmChartOpts
+	err        error
+}
+
+// New returns a new ComponentArchiveBuilder for the given component.
+// The archive is created in outDir using the directory format.
+func New(name, version, provider, outDir string) *ComponentArchiveBuilder {
+	return &ComponentArchiveBuilder{
+		octx:     ocm.DefaultContext(),
+		name:     name,
+		version:  version,
+		provider: provider,
+		outDir:   outDir,
+	}
+}
+
+// WithContext sets the ocm context used to build the archive.
+func (b *ComponentArchiveBuilder) WithContext(octx ocm.Context) *ComponentArchiveBuilder {
+	b.octx = octx
+	return b
+}
+
+// SkipDigest configures whether the digest calculation of external resources is skipped.
+func (b *ComponentArchiveBuilder) SkipDigest(skip bool) *ComponentArchiveBuilder {
+	b.skipDigest = skip
+	return b
+}
+
+// MaxResourceSize limits the size in bytes of the file resources.
+// It defaults to DefaultMaxResourceSize.
+func (b *ComponentArchiveBuilder) MaxResourceSize(size int64) *ComponentArchiveBuilder {
+	b.maxSize = size
+	return b
+}
+
+// AllowedMIMETypes limits the MIME types of the file resources, e.g. application/yaml or text/*.
+// Any type is allowed by default.
+func (b *ComponentArchiveBuilder) AllowedMIMETypes(types ...string) *ComponentArchiveBuilder {
+	b.mimeTypes = types
+	return b
+}
+
+// AddFile adds a local file resource to the archive.
+func (b *ComponentArchiveBuilder) AddFile(name, version, path string) *ComponentArchiveBuilder {
+	if name == "" || path == "" {
+		b.err = errors.Join(b.err, fmt.Errorf("file resource name and path must be set"))
+		return b
+	}
+
+	b.files = append(b.files, &addFileOpts{
+		name:    name,
+		version: version,
+		path:    path,
+	})
+	return b
+}
+
+// AddOCIImage adds an external oci image resource to the archive.
+func (b *ComponentArchiveBuilder) AddOCIImage(name, version, image string) *ComponentArchiveBuilder {
+	if name == "" || image == "" {
+		b.err = errors.Join(b.err, fmt.Errorf("image resource name and image must be set"))
+		return b
+	}
+
+	b.images = append(b.images, &addImageOpts{
+		name:    name,
+		version: version,
+		image:   image,
+	})
+	return b
+}
+
+// AddHelmChart adds an external helm chart resource, stored as oci artifact, to the archive.
+func (b *ComponentArchiveBuilder) AddHelmChart(name, version, chart string) *ComponentArchiveBuilder {
+	if name == "" || chart == "" {
+		b.err = errors.Join(b.err, fmt.Errorf("helm chart resource name and chart must be set"))
+		return b
+	}
+
+	b.charts = append(b.charts, &addHelmChartOpts{
+		name:    name,
+		version: version,
+		chart:   chart,
+	})
+	return b
+}
+
+// Build creates the component archive and adds all configured resources.
+// The caller is responsible for closing the returned archive, which persists it to disk.
+func (b *ComponentArchiveBuilder) Build() (*comparch.ComponentArchive, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if err := os.MkdirAll(b.outDir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create output directory %s: %w", b.outDir, err)
+	}
+
+	ca, err := comparch.Create(b.octx, accessobj.ACC_CREATE, b.outDir, os.ModePerm, accessio.FormatDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create component archive: %w", err)
+	}
+
+	if err := b.build(ca); err != nil {
+		return nil, errors.Join(err, ca.Close())
+	}
+
+	return ca, nil
+}
+
+func (b *ComponentArchiveBuilder) build(ca *comparch.ComponentArchive) error {
+	desc := ca.GetDescriptor()
+	desc.Name = b.name
+	desc.Version = b.version
+	desc.Provider.Name = metav1.ProviderName(b.provider)
+	if !compatattr.Get(b.octx) {
+		desc.CreationTime = metav1.NewTimestampP()
+	}
+
+	for _, o := range b.files {
+		o.maxSize = b.maxSize
+		o.allowedMIMETypes = b.mimeTypes
+		if err := fileHandler(ca, b.octx, o); err != nil {
+			return fmt.Errorf("failed to add file %s: %w", o.name, err)
+		}
+	}
+
+	for _, o := range b.images {
+		o.skipDigest = b.skipDigest
+		if err := imageHandler(ca, o); err != nil {
+			return err
+		}
+	}
+
+	for _, o := range b.charts {
+		o.skipDigest = b.skipDigest
+		if err := helmChartHandler(ca, o); err != nil {
+			return err
+		}
+	}
+
+	if err := compdesc.Validate(desc); err != nil {
+		return fmt.Errorf("invalid component descriptor: %w", err)
+	}
+
+	return nil
+}
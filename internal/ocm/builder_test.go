@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ocm
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/open-component-model/ocm/pkg/contexts/datacontext"
+	om "github.com/open-component-model/ocm/pkg/contexts/ocm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ComponentArchiveBuilder(t *testing.T) {
+	testCases := []struct {
+		name          string
+		build         func(b *ComponentArchiveBuilder, file string) *ComponentArchiveBuilder
+		expectedTypes map[string]string
+		expectedErr   string
+	}{
+		{
+			name: "file",
+			build: func(b *ComponentArchiveBuilder, file string) *ComponentArchiveBuilder {
+				return b.AddFile("my-file", "v0.1.0", file)
+			},
+			expectedTypes: map[string]string{"my-file": "file"},
+		},
+		{
+			name: "oci image",
+			build: func(b *ComponentArchiveBuilder, _ string) *ComponentArchiveBuilder {
+				return b.AddOCIImage("my-image", "v0.1.0", "ghcr.io/my-registry/my-image:v0.1.0")
+			},
+			expectedTypes: map[string]string{"my-image": "ociImage"},
+		},
+		{
+			name: "helm chart",
+			build: func(b *ComponentArchiveBuilder, _ string) *ComponentArchiveBuilder {
+				return b.AddHelmChart("my-chart", "v0.1.0", "ghcr.io/my-registry/charts/my-chart:v0.1.0")
+			},
+			expectedTypes: map[string]string{"my-chart": "helmChart"},
+		},
+		{
+			name: "all resources combined",
+			build: func(b *ComponentArchiveBuilder, file string) *ComponentArchiveBuilder {
+				return b.AddFile("my-file", "v0.1.0", file).
+					AddOCIImage("my-image", "v0.1.0", "ghcr.io/my-registry/my-image:v0.1.0").
+					AddHelmChart("my-chart", "v0.1.0", "ghcr.io/my-registry/charts/my-chart:v0.1.0")
+			},
+			expectedTypes: map[string]string{
+				"my-file":  "file",
+				"my-image": "ociImage",
+				"my-chart": "helmChart",
+			},
+		},
+		{
+			name: "file exceeds max resource size",
+			build: func(b *ComponentArchiveBuilder, file string) *ComponentArchiveBuilder {
+				return b.AddFile("my-file", "v0.1.0", file).MaxResourceSize(5)
+			},
+			expectedErr: "is 11 bytes, which exceeds the maximum resource size of 5 bytes",
+		},
+		{
+			name: "allowed mime type",
+			build: func(b *ComponentArchiveBuilder, file string) *ComponentArchiveBuilder {
+				return b.AddFile("my-file", "v0.1.0", file).AllowedMIMETypes("text/*")
+			},
+			expectedTypes: map[string]string{"my-file": "file"},
+		},
+		{
+			name: "disallowed mime type",
+			build: func(b *ComponentArchiveBuilder, file string) *ComponentArchiveBuilder {
+				return b.AddFile("my-file", "v0.1.0", file).AllowedMIMETypes("application/gzip")
+			},
+			expectedErr: "MIME type text/plain is not allowed",
+		},
+		{
+			name: "missing file path",
+			build: func(b *ComponentArchiveBuilder, _ string) *ComponentArchiveBuilder {
+				return b.AddFile("my-file", "v0.1.0", "")
+			},
+			expectedErr: "file resource name and path must be set",
+		},
+		{
+			name: "errors are collected",
+			build: func(b *ComponentArchiveBuilder, _ string) *ComponentArchiveBuilder {
+				return b.AddOCIImage("", "v0.1.0", "ghcr.io/my-registry/my-image:v0.1.0").
+					AddHelmChart("my-chart", "v0.1.0", "")
+			},
+			expectedErr: "helm chart resource name and chart must be set",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpdir := t.TempDir()
+			file, err := writeFile(tmpdir, []byte("hello world"))
+			require.NoError(t, err)
+
+			b := New("github.com/ocm/test", "v0.1.0", "ocm", filepath.Join(tmpdir, "archive")).
+				WithContext(om.New(datacontext.MODE_SHARED)).
+				SkipDigest(true)
+
+			ca, err := tc.build(b, file).Build()
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			defer ca.Close()
+
+			desc := ca.GetDescriptor()
+			assert.Equal(t, "github.com/ocm/test", desc.Name)
+			assert.Equal(t, "v0.1.0", desc.Version)
+			assert.Equal(t, "ocm", string(desc.Provider.Name))
+			require.Len(t, desc.Resources, len(tc.expectedTypes))
+			for _, r := range desc.Resources {
+				assert.Equal(t, tc.expectedTypes[r.Name], r.Type)
+			}
+		})
+	}
+}
@@ -115,6 +115,8 @@ type ResourceOptions struct {
 	image         string
 	componentName string
 	skipDigest    bool
+	maxSize       int64
+	mimeTypes     []string
 }
 
 // ResourceOption is a function that configures a resource options.
@@ -176,6 +178,22 @@ func WithSkipVerify(skip bool) ResourceOption {
 	}
 }
 
+// WithResourceMaxSize limits the size in bytes of a file resource.
+// It defaults to DefaultMaxResourceSize.
+func WithResourceMaxSize(size int64) ResourceOption {
+	return func(o *ResourceOptions) {
+		o.maxSize = size
+	}
+}
+
+// WithResourceAllowedMIMETypes limits the MIME types of a file resource, e.g. application/yaml or text/*.
+// Any type is allowed by default.
+func WithResourceAllowedMIMETypes(types ...string) ResourceOption {
+	return func(o *ResourceOptions) {
+		o.mimeTypes = types
+	}
+}
+
 // AddResource adds a resource to a component archive.
 // It accepts options for configuring the resource.
 // The resource type can be one of the following:
@@ -205,9 +223,11 @@ func (c *Component) AddResource(opts ...ResourceOption) (rerr error) {
 			return fmt.Errorf("resource path must be set")
 		}
 		o := &addFileOpts{
-			name:    resOpt.name,
-			path:    resOpt.path,
-			version: resOpt.version,
+			name:             resOpt.name,
+			path:             resOpt.path,
+			version:          resOpt.version,
+			maxSize:          resOpt.maxSize,
+			allowedMIMETypes: resOpt.mimeTypes,
 		}
 		if err := fileHandler(cv, c.Context, o); err != nil {
 			return err
@@ -6,6 +6,9 @@ package ocm
 
 import (
 	"fmt"
+	"mime"
+	"os"
+	"strings"
 
 	"github.com/gabriel-vasile/mimetype"
 	"github.com/mandelsoft/vfs/pkg/osfs"
@@ -22,14 +25,25 @@ import (
 
 // from https://github.com/phoban01/gitops-component-cli/blob/main/pkg/component/handlers.go
 
+// DefaultMaxResourceSize is the maximum size in bytes of a file resource if no limit is set.
+const DefaultMaxResourceSize = 100 * 1024 * 1024
+
 type addFileOpts struct {
 	name     string
 	version  string
 	path     string
 	fileType string
+	// maxSize is the maximum size of the file in bytes, DefaultMaxResourceSize if not positive
+	maxSize int64
+	// allowedMIMETypes are the MIME types the file may have, any type is allowed if empty
+	allowedMIMETypes []string
 }
 
 func fileHandler(cv ocm.ComponentVersionAccess, octx ocm.Context, opts *addFileOpts) error {
+	if err := checkResourceSize(opts.path, opts.maxSize); err != nil {
+		return err
+	}
+
 	tmpcache.Set(octx, &tmpcache.Attribute{Path: "/tmp"})
 
 	mtype, err := mimetype.DetectFile(opts.path)
@@ -37,6 +51,10 @@ func fileHandler(cv ocm.ComponentVersionAccess, octx ocm.Context, opts *addFileO
 		return err
 	}
 
+	if err := checkMIMEType(mtype.String(), opts.allowedMIMETypes); err != nil {
+		return fmt.Errorf("file %s: %w", opts.path, err)
+	}
+
 	ftype := file.TYPE
 	if opts.fileType != "" {
 		ftype = opts.fileType
@@ -61,6 +79,48 @@ func fileHandler(cv ocm.ComponentVersionAccess, octx ocm.Context, opts *addFileO
 	return nil
 }
 
+// checkResourceSize returns an error if the file at path is larger than maxSize bytes.
+// DefaultMaxResourceSize is used if maxSize is not positive.
+func checkResourceSize(path string, maxSize int64) error {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxResourceSize
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat file %s: %w", path, err)
+	}
+
+	if fi.Size() > maxSize {
+		return fmt.Errorf("file %s is %d bytes, which exceeds the maximum resource size of %d bytes", path, fi.Size(), maxSize)
+	}
+
+	return nil
+}
+
+// checkMIMEType returns an error if the detected MIME type is not allowed.
+// An allowed type ending with / or /* allows all subtypes, e.g. text/ allows text/plain.
+// Parameters like charset are ignored. All types are allowed if allowed is empty.
+func checkMIMEType(detected string, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(detected)
+	if err != nil {
+		return fmt.Errorf("failed to parse MIME type %q: %w", detected, err)
+	}
+
+	for _, a := range allowed {
+		a = strings.ToLower(strings.TrimSuffix(a, "*"))
+		if mediaType == a || (strings.HasSuffix(a, "/") && strings.HasPrefix(mediaType, a)) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("MIME type %s is not allowed, must be one of %s", mediaType, strings.Join(allowed, ", "))
+}
+
 type addImageOpts struct {
 	name       string
 	image      string
@@ -94,6 +154,42 @@ func imageHandler(cv ocm.ComponentVersionAccess, opts *addImageOpts) error {
 	return nil
 }
 
+// helmChartType is the resource type of helm charts.
+const helmChartType = "helmChart"
+
+type addHelmChartOpts struct {
+	name       string
+	chart      string
+	version    string
+	skipDigest bool
+}
+
+func helmChartHandler(cv ocm.ComponentVersionAccess, opts *addHelmChartOpts) error {
+	r := &compdesc.ResourceMeta{
+		ElementMeta: compdesc.ElementMeta{
+			Name:    opts.name,
+			Version: opts.version,
+		},
+		Relation: metav1.ExternalRelation,
+		Type:     helmChartType,
+	}
+
+	spec := ociartifact.New(opts.chart)
+
+	modificationOptions := ocm.ModificationOptions{
+		ModifyResource: pointer.Bool(true),
+	}
+	if opts.skipDigest {
+		modificationOptions.SkipDigest = pointer.Bool(opts.skipDigest)
+	}
+
+	if err := cv.SetResource(r, spec, modificationOptions); err != nil {
+		return fmt.Errorf("failed to add helm chart: %w", err)
+	}
+
+	return nil
+}
+
 type addReferenceOpts struct {
 	name      string
 	version   string
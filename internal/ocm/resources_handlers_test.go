@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company and Gardener contributors.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ocm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckResourceSize(t *testing.T) {
+	testCases := []struct {
+		name        string
+		size        int64
+		maxSize     int64
+		expectedErr string
+	}{
+		{
+			name:    "below limit",
+			size:    1024,
+			maxSize: 2048,
+		},
+		{
+			name:    "at limit",
+			size:    2048,
+			maxSize: 2048,
+		},
+		{
+			name:        "above limit",
+			size:        4096,
+			maxSize:     2048,
+			expectedErr: "is 4096 bytes, which exceeds the maximum resource size of 2048 bytes",
+		},
+		{
+			name: "below default limit",
+			size: DefaultMaxResourceSize,
+		},
+		{
+			name:        "above default limit",
+			size:        DefaultMaxResourceSize + 1,
+			expectedErr: fmt.Sprintf("which exceeds the maximum resource size of %d bytes", DefaultMaxResourceSize),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "resource")
+			f, err := os.Create(path)
+			require.NoError(t, err)
+			// the file is sparse, so large sizes do not use disk space
+			require.NoError(t, f.Truncate(tc.size))
+			require.NoError(t, f.Close())
+
+			err = checkResourceSize(path, tc.maxSize)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, path)
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		err := checkResourceSize(filepath.Join(t.TempDir(), "missing"), 0)
+		assert.ErrorContains(t, err, "failed to stat file")
+	})
+}
+
+func Test_CheckMIMEType(t *testing.T) {
+	testCases := []struct {
+		name        string
+		detected    string
+		allowed     []string
+		expectedErr string
+	}{
+		{
+			name:     "any type is allowed if empty",
+			detected: "application/x-executable",
+		},
+		{
+			name:     "yaml",
+			detected: "application/yaml",
+			allowed:  []string{"application/yaml", "application/gzip"},
+		},
+		{
+			name:     "gzip",
+			detected: "application/gzip",
+			allowed:  []string{"application/yaml", "application/gzip"},
+		},
+		{
+			name:     "parameters are ignored",
+			detected: "text/plain; charset=utf-8",
+			allowed:  []string{"text/plain"},
+		},
+		{
+			name:     "prefix",
+			detected: "text/plain; charset=utf-8",
+			allowed:  []string{"text/*"},
+		},
+		{
+			name:        "executable",
+			detected:    "application/x-executable",
+			allowed:     []string{"application/yaml", "application/gzip", "text/*"},
+			expectedErr: "MIME type application/x-executable is not allowed, must be one of application/yaml, application/gzip, text/*",
+		},
+		{
+			name:        "prefix does not match other subtypes",
+			detected:    "application/x-executable",
+			allowed:     []string{"application/x"},
+			expectedErr: "MIME type application/x-executable is not allowed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkMIMEType(tc.detected, tc.allowed)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}